@@ -21,7 +21,10 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readAccountGetRequest(request *AccountGetServerRequest, r *http.Request) error {
@@ -39,8 +42,15 @@ func writeAccountGetResponse(response *AccountGetServerResponse, w http.Response
 	return MarshalAccount(response.body, w)
 }
 func readAccountUpdateRequest(request *AccountUpdateServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalAccount(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, accountValidation, helpers.ValidateUpdate)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalAccount(body)
 	return err
 }
 func writeAccountUpdateRequest(request *AccountUpdateRequest, writer io.Writer) error {
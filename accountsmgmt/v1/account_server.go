@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // AccountServer represents the interface the manages the 'account' resource.
@@ -117,23 +117,23 @@ func (r *AccountUpdateServerResponse) Status(value int) *AccountUpdateServerResp
 // dispatchAccount navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchAccount(w http.ResponseWriter, r *http.Request, server AccountServer, segments []string) {
+func dispatchAccount(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AccountServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptAccountGetRequest(w, r, server)
+			adaptAccountGetRequest(w, r, logger, server)
 			return
 		case "PATCH":
-			adaptAccountUpdateRequest(w, r, server)
+			adaptAccountUpdateRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -141,31 +141,24 @@ func dispatchAccount(w http.ResponseWriter, r *http.Request, server AccountServe
 // adaptAccountGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAccountGetRequest(w http.ResponseWriter, r *http.Request, server AccountServer) {
+func adaptAccountGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AccountServer) {
 	request := &AccountGetServerRequest{}
 	err := readAccountGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AccountGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAccountGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -176,31 +169,24 @@ func adaptAccountGetRequest(w http.ResponseWriter, r *http.Request, server Accou
 // adaptAccountUpdateRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAccountUpdateRequest(w http.ResponseWriter, r *http.Request, server AccountServer) {
+func adaptAccountUpdateRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AccountServer) {
 	request := &AccountUpdateServerRequest{}
 	err := readAccountUpdateRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AccountUpdateServerResponse{}
 	response.status = 204
 	err = server.Update(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAccountUpdateResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
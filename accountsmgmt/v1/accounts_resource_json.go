@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readAccountsAddRequest(request *AccountsAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalAccount(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, accountValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalAccount(body)
 	return err
 }
 func writeAccountsAddRequest(request *AccountsAddRequest, writer io.Writer) error {
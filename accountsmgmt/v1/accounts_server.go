@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // AccountsServer represents the interface the manages the 'accounts' resource.
@@ -292,17 +292,17 @@ func (r *AccountsListServerResponse) Status(value int) *AccountsListServerRespon
 // dispatchAccounts navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchAccounts(w http.ResponseWriter, r *http.Request, server AccountsServer, segments []string) {
+func dispatchAccounts(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AccountsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptAccountsAddRequest(w, r, server)
+			adaptAccountsAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptAccountsListRequest(w, r, server)
+			adaptAccountsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -310,41 +310,34 @@ func dispatchAccounts(w http.ResponseWriter, r *http.Request, server AccountsSer
 	default:
 		target := server.Account(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAccount(w, r, target, segments[1:])
+		dispatchAccount(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptAccountsAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAccountsAddRequest(w http.ResponseWriter, r *http.Request, server AccountsServer) {
+func adaptAccountsAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AccountsServer) {
 	request := &AccountsAddServerRequest{}
 	err := readAccountsAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AccountsAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAccountsAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -355,31 +348,24 @@ func adaptAccountsAddRequest(w http.ResponseWriter, r *http.Request, server Acco
 // adaptAccountsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAccountsListRequest(w http.ResponseWriter, r *http.Request, server AccountsServer) {
+func adaptAccountsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AccountsServer) {
 	request := &AccountsListServerRequest{}
 	err := readAccountsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AccountsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAccountsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
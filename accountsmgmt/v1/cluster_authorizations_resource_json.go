@@ -21,12 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readClusterAuthorizationsPostRequest(request *ClusterAuthorizationsPostServerRequest, r *http.Request) error {
-	var err error
-	request.request, err = UnmarshalClusterAuthorizationRequest(r)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, clusterAuthorizationRequestValidation, helpers.ValidateAction)
+	if err != nil {
+		return err
+	}
+	request.request, err = UnmarshalClusterAuthorizationRequest(body)
 	return err
 }
 func writeClusterAuthorizationsPostRequest(request *ClusterAuthorizationsPostRequest, writer io.Writer) error {
@@ -21,12 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readClusterRegistrationsPostRequest(request *ClusterRegistrationsPostServerRequest, r *http.Request) error {
-	var err error
-	request.request, err = UnmarshalClusterRegistrationRequest(r)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, clusterRegistrationRequestValidation, helpers.ValidateAction)
+	if err != nil {
+		return err
+	}
+	request.request, err = UnmarshalClusterRegistrationRequest(body)
 	return err
 }
 func writeClusterRegistrationsPostRequest(request *ClusterRegistrationsPostRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // ClusterRegistrationsServer represents the interface the manages the 'cluster_registrations' resource.
@@ -88,20 +88,20 @@ func (r *ClusterRegistrationsPostServerResponse) Status(value int) *ClusterRegis
 // dispatchClusterRegistrations navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchClusterRegistrations(w http.ResponseWriter, r *http.Request, server ClusterRegistrationsServer, segments []string) {
+func dispatchClusterRegistrations(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterRegistrationsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptClusterRegistrationsPostRequest(w, r, server)
+			adaptClusterRegistrationsPostRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -109,31 +109,24 @@ func dispatchClusterRegistrations(w http.ResponseWriter, r *http.Request, server
 // adaptClusterRegistrationsPostRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptClusterRegistrationsPostRequest(w http.ResponseWriter, r *http.Request, server ClusterRegistrationsServer) {
+func adaptClusterRegistrationsPostRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterRegistrationsServer) {
 	request := &ClusterRegistrationsPostServerRequest{}
 	err := readClusterRegistrationsPostRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ClusterRegistrationsPostServerResponse{}
 	response.status = 201
 	err = server.Post(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeClusterRegistrationsPostResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // CurrentAccessServer represents the interface the manages the 'current_access' resource.
@@ -138,20 +138,20 @@ func (r *CurrentAccessListServerResponse) Status(value int) *CurrentAccessListSe
 // dispatchCurrentAccess navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchCurrentAccess(w http.ResponseWriter, r *http.Request, server CurrentAccessServer, segments []string) {
+func dispatchCurrentAccess(w http.ResponseWriter, r *http.Request, logger logging.Logger, server CurrentAccessServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptCurrentAccessListRequest(w, r, server)
+			adaptCurrentAccessListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -159,31 +159,24 @@ func dispatchCurrentAccess(w http.ResponseWriter, r *http.Request, server Curren
 // adaptCurrentAccessListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptCurrentAccessListRequest(w http.ResponseWriter, r *http.Request, server CurrentAccessServer) {
+func adaptCurrentAccessListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server CurrentAccessServer) {
 	request := &CurrentAccessListServerRequest{}
 	err := readCurrentAccessListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &CurrentAccessListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeCurrentAccessListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
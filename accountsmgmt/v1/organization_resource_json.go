@@ -21,7 +21,10 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readOrganizationGetRequest(request *OrganizationGetServerRequest, r *http.Request) error {
@@ -39,8 +42,15 @@ func writeOrganizationGetResponse(response *OrganizationGetServerResponse, w htt
 	return MarshalOrganization(response.body, w)
 }
 func readOrganizationUpdateRequest(request *OrganizationUpdateServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalOrganization(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, organizationValidation, helpers.ValidateUpdate)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalOrganization(body)
 	return err
 }
 func writeOrganizationUpdateRequest(request *OrganizationUpdateRequest, writer io.Writer) error {
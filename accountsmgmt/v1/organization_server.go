@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // OrganizationServer represents the interface the manages the 'organization' resource.
@@ -129,17 +129,17 @@ func (r *OrganizationUpdateServerResponse) Status(value int) *OrganizationUpdate
 // dispatchOrganization navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchOrganization(w http.ResponseWriter, r *http.Request, server OrganizationServer, segments []string) {
+func dispatchOrganization(w http.ResponseWriter, r *http.Request, logger logging.Logger, server OrganizationServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptOrganizationGetRequest(w, r, server)
+			adaptOrganizationGetRequest(w, r, logger, server)
 			return
 		case "PATCH":
-			adaptOrganizationUpdateRequest(w, r, server)
+			adaptOrganizationUpdateRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -147,19 +147,19 @@ func dispatchOrganization(w http.ResponseWriter, r *http.Request, server Organiz
 	case "quota_summary":
 		target := server.QuotaSummary()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchQuotaSummary(w, r, target, segments[1:])
+		dispatchQuotaSummary(w, r, logger, target, segments[1:])
 	case "resource_quota":
 		target := server.ResourceQuota()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchResourceQuotas(w, r, target, segments[1:])
+		dispatchResourceQuotas(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -167,31 +167,24 @@ func dispatchOrganization(w http.ResponseWriter, r *http.Request, server Organiz
 // adaptOrganizationGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptOrganizationGetRequest(w http.ResponseWriter, r *http.Request, server OrganizationServer) {
+func adaptOrganizationGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server OrganizationServer) {
 	request := &OrganizationGetServerRequest{}
 	err := readOrganizationGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &OrganizationGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeOrganizationGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -202,31 +195,24 @@ func adaptOrganizationGetRequest(w http.ResponseWriter, r *http.Request, server
 // adaptOrganizationUpdateRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptOrganizationUpdateRequest(w http.ResponseWriter, r *http.Request, server OrganizationServer) {
+func adaptOrganizationUpdateRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server OrganizationServer) {
 	request := &OrganizationUpdateServerRequest{}
 	err := readOrganizationUpdateRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &OrganizationUpdateServerResponse{}
 	response.status = 204
 	err = server.Update(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeOrganizationUpdateResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -350,6 +350,63 @@ func (r *OrganizationsListRequest) SendContext(ctx context.Context) (result *Org
 	return
 }
 
+// Iterate sends this request repeatedly, one page at a time, and calls the given
+// function for each item of the collection, in order. If the function returns
+// false the iteration stops, otherwise it continues till all the pages have been
+// retrieved. Pages are requested lazily, only when the items of the previous page
+// have been processed.
+//
+// The iteration starts with the page set with the Page method, or with the first
+// page if it hasn't been set. The size of the pages is the one set with the Size
+// method, or 100 if it hasn't been set. The iteration stops when the total number
+// of items reported by the server has been reached, when a page is empty, or, if
+// the server doesn't report the total, when a page contains less items than
+// requested.
+//
+// If retrieving any of the pages fails the iteration stops and the error is
+// returned. If the server responded with an error the returned value will be of
+// type *errors.Error.
+func (r *OrganizationsListRequest) Iterate(ctx context.Context, f func(item *Organization) bool) error {
+	page := 1
+	if r.page != nil {
+		page = *r.page
+	}
+	size := 100
+	if r.size != nil {
+		size = *r.size
+	}
+	seen := (page - 1) * size
+	for {
+		request := *r
+		request.page = &page
+		request.size = &size
+		response, err := request.SendContext(ctx)
+		if err != nil {
+			return err
+		}
+		items := response.Items()
+		count := items.Len()
+		stop := false
+		items.Each(func(item *Organization) bool {
+			stop = !f(item)
+			return !stop
+		})
+		if stop || count == 0 {
+			return nil
+		}
+		seen += count
+		total, ok := response.GetTotal()
+		if ok {
+			if seen >= total {
+				return nil
+			}
+		} else if count < size {
+			return nil
+		}
+		page++
+	}
+}
+
 // OrganizationsListResponse is the response for the 'list' method.
 type OrganizationsListResponse struct {
 	status int
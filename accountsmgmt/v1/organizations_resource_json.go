@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readOrganizationsAddRequest(request *OrganizationsAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalOrganization(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, organizationValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalOrganization(body)
 	return err
 }
 func writeOrganizationsAddRequest(request *OrganizationsAddRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // OrganizationsServer represents the interface the manages the 'organizations' resource.
@@ -245,17 +245,17 @@ func (r *OrganizationsListServerResponse) Status(value int) *OrganizationsListSe
 // dispatchOrganizations navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchOrganizations(w http.ResponseWriter, r *http.Request, server OrganizationsServer, segments []string) {
+func dispatchOrganizations(w http.ResponseWriter, r *http.Request, logger logging.Logger, server OrganizationsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptOrganizationsAddRequest(w, r, server)
+			adaptOrganizationsAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptOrganizationsListRequest(w, r, server)
+			adaptOrganizationsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -263,41 +263,34 @@ func dispatchOrganizations(w http.ResponseWriter, r *http.Request, server Organi
 	default:
 		target := server.Organization(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchOrganization(w, r, target, segments[1:])
+		dispatchOrganization(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptOrganizationsAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptOrganizationsAddRequest(w http.ResponseWriter, r *http.Request, server OrganizationsServer) {
+func adaptOrganizationsAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server OrganizationsServer) {
 	request := &OrganizationsAddServerRequest{}
 	err := readOrganizationsAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &OrganizationsAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeOrganizationsAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -308,31 +301,24 @@ func adaptOrganizationsAddRequest(w http.ResponseWriter, r *http.Request, server
 // adaptOrganizationsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptOrganizationsListRequest(w http.ResponseWriter, r *http.Request, server OrganizationsServer) {
+func adaptOrganizationsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server OrganizationsServer) {
 	request := &OrganizationsListServerRequest{}
 	err := readOrganizationsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &OrganizationsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeOrganizationsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
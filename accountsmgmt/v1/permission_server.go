@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // PermissionServer represents the interface the manages the 'permission' resource.
@@ -85,23 +85,23 @@ func (r *PermissionGetServerResponse) Status(value int) *PermissionGetServerResp
 // dispatchPermission navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchPermission(w http.ResponseWriter, r *http.Request, server PermissionServer, segments []string) {
+func dispatchPermission(w http.ResponseWriter, r *http.Request, logger logging.Logger, server PermissionServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptPermissionDeleteRequest(w, r, server)
+			adaptPermissionDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptPermissionGetRequest(w, r, server)
+			adaptPermissionGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -109,31 +109,24 @@ func dispatchPermission(w http.ResponseWriter, r *http.Request, server Permissio
 // adaptPermissionDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptPermissionDeleteRequest(w http.ResponseWriter, r *http.Request, server PermissionServer) {
+func adaptPermissionDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server PermissionServer) {
 	request := &PermissionDeleteServerRequest{}
 	err := readPermissionDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &PermissionDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writePermissionDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -144,31 +137,24 @@ func adaptPermissionDeleteRequest(w http.ResponseWriter, r *http.Request, server
 // adaptPermissionGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptPermissionGetRequest(w http.ResponseWriter, r *http.Request, server PermissionServer) {
+func adaptPermissionGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server PermissionServer) {
 	request := &PermissionGetServerRequest{}
 	err := readPermissionGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &PermissionGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writePermissionGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readPermissionsAddRequest(request *PermissionsAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalPermission(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, permissionValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalPermission(body)
 	return err
 }
 func writePermissionsAddRequest(request *PermissionsAddRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // PermissionsServer represents the interface the manages the 'permissions' resource.
@@ -196,17 +196,17 @@ func (r *PermissionsListServerResponse) Status(value int) *PermissionsListServer
 // dispatchPermissions navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchPermissions(w http.ResponseWriter, r *http.Request, server PermissionsServer, segments []string) {
+func dispatchPermissions(w http.ResponseWriter, r *http.Request, logger logging.Logger, server PermissionsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptPermissionsAddRequest(w, r, server)
+			adaptPermissionsAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptPermissionsListRequest(w, r, server)
+			adaptPermissionsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -214,41 +214,34 @@ func dispatchPermissions(w http.ResponseWriter, r *http.Request, server Permissi
 	default:
 		target := server.Permission(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchPermission(w, r, target, segments[1:])
+		dispatchPermission(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptPermissionsAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptPermissionsAddRequest(w http.ResponseWriter, r *http.Request, server PermissionsServer) {
+func adaptPermissionsAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server PermissionsServer) {
 	request := &PermissionsAddServerRequest{}
 	err := readPermissionsAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &PermissionsAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writePermissionsAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -259,31 +252,24 @@ func adaptPermissionsAddRequest(w http.ResponseWriter, r *http.Request, server P
 // adaptPermissionsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptPermissionsListRequest(w http.ResponseWriter, r *http.Request, server PermissionsServer) {
+func adaptPermissionsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server PermissionsServer) {
 	request := &PermissionsListServerRequest{}
 	err := readPermissionsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &PermissionsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writePermissionsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // QuotaSummaryServer represents the interface the manages the 'quota_summary' resource.
@@ -189,20 +189,20 @@ func (r *QuotaSummaryListServerResponse) Status(value int) *QuotaSummaryListServ
 // dispatchQuotaSummary navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchQuotaSummary(w http.ResponseWriter, r *http.Request, server QuotaSummaryServer, segments []string) {
+func dispatchQuotaSummary(w http.ResponseWriter, r *http.Request, logger logging.Logger, server QuotaSummaryServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptQuotaSummaryListRequest(w, r, server)
+			adaptQuotaSummaryListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -210,31 +210,24 @@ func dispatchQuotaSummary(w http.ResponseWriter, r *http.Request, server QuotaSu
 // adaptQuotaSummaryListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptQuotaSummaryListRequest(w http.ResponseWriter, r *http.Request, server QuotaSummaryServer) {
+func adaptQuotaSummaryListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server QuotaSummaryServer) {
 	request := &QuotaSummaryListServerRequest{}
 	err := readQuotaSummaryListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &QuotaSummaryListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeQuotaSummaryListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
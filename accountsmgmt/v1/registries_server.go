@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // RegistriesServer represents the interface the manages the 'registries' resource.
@@ -143,14 +143,14 @@ func (r *RegistriesListServerResponse) Status(value int) *RegistriesListServerRe
 // dispatchRegistries navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchRegistries(w http.ResponseWriter, r *http.Request, server RegistriesServer, segments []string) {
+func dispatchRegistries(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistriesServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptRegistriesListRequest(w, r, server)
+			adaptRegistriesListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -158,41 +158,34 @@ func dispatchRegistries(w http.ResponseWriter, r *http.Request, server Registrie
 	default:
 		target := server.Registry(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRegistry(w, r, target, segments[1:])
+		dispatchRegistry(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptRegistriesListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRegistriesListRequest(w http.ResponseWriter, r *http.Request, server RegistriesServer) {
+func adaptRegistriesListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistriesServer) {
 	request := &RegistriesListServerRequest{}
 	err := readRegistriesListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RegistriesListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRegistriesListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
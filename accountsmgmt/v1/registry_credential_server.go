@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // RegistryCredentialServer represents the interface the manages the 'registry_credential' resource.
@@ -64,20 +64,20 @@ func (r *RegistryCredentialGetServerResponse) Status(value int) *RegistryCredent
 // dispatchRegistryCredential navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchRegistryCredential(w http.ResponseWriter, r *http.Request, server RegistryCredentialServer, segments []string) {
+func dispatchRegistryCredential(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistryCredentialServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptRegistryCredentialGetRequest(w, r, server)
+			adaptRegistryCredentialGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -85,31 +85,24 @@ func dispatchRegistryCredential(w http.ResponseWriter, r *http.Request, server R
 // adaptRegistryCredentialGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRegistryCredentialGetRequest(w http.ResponseWriter, r *http.Request, server RegistryCredentialServer) {
+func adaptRegistryCredentialGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistryCredentialServer) {
 	request := &RegistryCredentialGetServerRequest{}
 	err := readRegistryCredentialGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RegistryCredentialGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRegistryCredentialGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
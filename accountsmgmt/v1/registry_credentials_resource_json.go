@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readRegistryCredentialsAddRequest(request *RegistryCredentialsAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalRegistryCredential(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, registryCredentialValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalRegistryCredential(body)
 	return err
 }
 func writeRegistryCredentialsAddRequest(request *RegistryCredentialsAddRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // RegistryCredentialsServer represents the interface the manages the 'registry_credentials' resource.
@@ -196,17 +196,17 @@ func (r *RegistryCredentialsListServerResponse) Status(value int) *RegistryCrede
 // dispatchRegistryCredentials navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchRegistryCredentials(w http.ResponseWriter, r *http.Request, server RegistryCredentialsServer, segments []string) {
+func dispatchRegistryCredentials(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistryCredentialsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptRegistryCredentialsAddRequest(w, r, server)
+			adaptRegistryCredentialsAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptRegistryCredentialsListRequest(w, r, server)
+			adaptRegistryCredentialsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -214,41 +214,34 @@ func dispatchRegistryCredentials(w http.ResponseWriter, r *http.Request, server
 	default:
 		target := server.RegistryCredential(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRegistryCredential(w, r, target, segments[1:])
+		dispatchRegistryCredential(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptRegistryCredentialsAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRegistryCredentialsAddRequest(w http.ResponseWriter, r *http.Request, server RegistryCredentialsServer) {
+func adaptRegistryCredentialsAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistryCredentialsServer) {
 	request := &RegistryCredentialsAddServerRequest{}
 	err := readRegistryCredentialsAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RegistryCredentialsAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRegistryCredentialsAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -259,31 +252,24 @@ func adaptRegistryCredentialsAddRequest(w http.ResponseWriter, r *http.Request,
 // adaptRegistryCredentialsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRegistryCredentialsListRequest(w http.ResponseWriter, r *http.Request, server RegistryCredentialsServer) {
+func adaptRegistryCredentialsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistryCredentialsServer) {
 	request := &RegistryCredentialsListServerRequest{}
 	err := readRegistryCredentialsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RegistryCredentialsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRegistryCredentialsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // RegistryServer represents the interface the manages the 'registry' resource.
@@ -64,20 +64,20 @@ func (r *RegistryGetServerResponse) Status(value int) *RegistryGetServerResponse
 // dispatchRegistry navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchRegistry(w http.ResponseWriter, r *http.Request, server RegistryServer, segments []string) {
+func dispatchRegistry(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistryServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptRegistryGetRequest(w, r, server)
+			adaptRegistryGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -85,31 +85,24 @@ func dispatchRegistry(w http.ResponseWriter, r *http.Request, server RegistrySer
 // adaptRegistryGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRegistryGetRequest(w http.ResponseWriter, r *http.Request, server RegistryServer) {
+func adaptRegistryGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RegistryServer) {
 	request := &RegistryGetServerRequest{}
 	err := readRegistryGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RegistryGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRegistryGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -21,7 +21,10 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readResourceQuotaDeleteRequest(request *ResourceQuotaDeleteServerRequest, r *http.Request) error {
@@ -51,8 +54,15 @@ func writeResourceQuotaGetResponse(response *ResourceQuotaGetServerResponse, w h
 	return MarshalResourceQuota(response.body, w)
 }
 func readResourceQuotaUpdateRequest(request *ResourceQuotaUpdateServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalResourceQuota(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, resourceQuotaValidation, helpers.ValidateUpdate)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalResourceQuota(body)
 	return err
 }
 func writeResourceQuotaUpdateRequest(request *ResourceQuotaUpdateRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // ResourceQuotaServer represents the interface the manages the 'resource_quota' resource.
@@ -138,26 +138,26 @@ func (r *ResourceQuotaUpdateServerResponse) Status(value int) *ResourceQuotaUpda
 // dispatchResourceQuota navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchResourceQuota(w http.ResponseWriter, r *http.Request, server ResourceQuotaServer, segments []string) {
+func dispatchResourceQuota(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceQuotaServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptResourceQuotaDeleteRequest(w, r, server)
+			adaptResourceQuotaDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptResourceQuotaGetRequest(w, r, server)
+			adaptResourceQuotaGetRequest(w, r, logger, server)
 			return
 		case "PATCH":
-			adaptResourceQuotaUpdateRequest(w, r, server)
+			adaptResourceQuotaUpdateRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -165,31 +165,24 @@ func dispatchResourceQuota(w http.ResponseWriter, r *http.Request, server Resour
 // adaptResourceQuotaDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptResourceQuotaDeleteRequest(w http.ResponseWriter, r *http.Request, server ResourceQuotaServer) {
+func adaptResourceQuotaDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceQuotaServer) {
 	request := &ResourceQuotaDeleteServerRequest{}
 	err := readResourceQuotaDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ResourceQuotaDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeResourceQuotaDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -200,31 +193,24 @@ func adaptResourceQuotaDeleteRequest(w http.ResponseWriter, r *http.Request, ser
 // adaptResourceQuotaGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptResourceQuotaGetRequest(w http.ResponseWriter, r *http.Request, server ResourceQuotaServer) {
+func adaptResourceQuotaGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceQuotaServer) {
 	request := &ResourceQuotaGetServerRequest{}
 	err := readResourceQuotaGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ResourceQuotaGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeResourceQuotaGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -235,31 +221,24 @@ func adaptResourceQuotaGetRequest(w http.ResponseWriter, r *http.Request, server
 // adaptResourceQuotaUpdateRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptResourceQuotaUpdateRequest(w http.ResponseWriter, r *http.Request, server ResourceQuotaServer) {
+func adaptResourceQuotaUpdateRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceQuotaServer) {
 	request := &ResourceQuotaUpdateServerRequest{}
 	err := readResourceQuotaUpdateRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ResourceQuotaUpdateServerResponse{}
 	response.status = 204
 	err = server.Update(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeResourceQuotaUpdateResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readResourceQuotasAddRequest(request *ResourceQuotasAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalResourceQuota(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, resourceQuotaValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalResourceQuota(body)
 	return err
 }
 func writeResourceQuotasAddRequest(request *ResourceQuotasAddRequest, writer io.Writer) error {
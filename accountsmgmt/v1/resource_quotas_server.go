@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // ResourceQuotasServer represents the interface the manages the 'resource_quotas' resource.
@@ -245,17 +245,17 @@ func (r *ResourceQuotasListServerResponse) Status(value int) *ResourceQuotasList
 // dispatchResourceQuotas navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchResourceQuotas(w http.ResponseWriter, r *http.Request, server ResourceQuotasServer, segments []string) {
+func dispatchResourceQuotas(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceQuotasServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptResourceQuotasAddRequest(w, r, server)
+			adaptResourceQuotasAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptResourceQuotasListRequest(w, r, server)
+			adaptResourceQuotasListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -263,41 +263,34 @@ func dispatchResourceQuotas(w http.ResponseWriter, r *http.Request, server Resou
 	default:
 		target := server.ResourceQuota(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchResourceQuota(w, r, target, segments[1:])
+		dispatchResourceQuota(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptResourceQuotasAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptResourceQuotasAddRequest(w http.ResponseWriter, r *http.Request, server ResourceQuotasServer) {
+func adaptResourceQuotasAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceQuotasServer) {
 	request := &ResourceQuotasAddServerRequest{}
 	err := readResourceQuotasAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ResourceQuotasAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeResourceQuotasAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -308,31 +301,24 @@ func adaptResourceQuotasAddRequest(w http.ResponseWriter, r *http.Request, serve
 // adaptResourceQuotasListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptResourceQuotasListRequest(w http.ResponseWriter, r *http.Request, server ResourceQuotasServer) {
+func adaptResourceQuotasListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceQuotasServer) {
 	request := &ResourceQuotasListServerRequest{}
 	err := readResourceQuotasListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ResourceQuotasListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeResourceQuotasListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
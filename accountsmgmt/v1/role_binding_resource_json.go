@@ -21,7 +21,10 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readRoleBindingDeleteRequest(request *RoleBindingDeleteServerRequest, r *http.Request) error {
@@ -51,8 +54,15 @@ func writeRoleBindingGetResponse(response *RoleBindingGetServerResponse, w http.
 	return MarshalRoleBinding(response.body, w)
 }
 func readRoleBindingUpdateRequest(request *RoleBindingUpdateServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalRoleBinding(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, roleBindingValidation, helpers.ValidateUpdate)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalRoleBinding(body)
 	return err
 }
 func writeRoleBindingUpdateRequest(request *RoleBindingUpdateRequest, writer io.Writer) error {
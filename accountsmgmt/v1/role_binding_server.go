@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // RoleBindingServer represents the interface the manages the 'role_binding' resource.
@@ -138,26 +138,26 @@ func (r *RoleBindingUpdateServerResponse) Status(value int) *RoleBindingUpdateSe
 // dispatchRoleBinding navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchRoleBinding(w http.ResponseWriter, r *http.Request, server RoleBindingServer, segments []string) {
+func dispatchRoleBinding(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleBindingServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptRoleBindingDeleteRequest(w, r, server)
+			adaptRoleBindingDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptRoleBindingGetRequest(w, r, server)
+			adaptRoleBindingGetRequest(w, r, logger, server)
 			return
 		case "PATCH":
-			adaptRoleBindingUpdateRequest(w, r, server)
+			adaptRoleBindingUpdateRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -165,31 +165,24 @@ func dispatchRoleBinding(w http.ResponseWriter, r *http.Request, server RoleBind
 // adaptRoleBindingDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRoleBindingDeleteRequest(w http.ResponseWriter, r *http.Request, server RoleBindingServer) {
+func adaptRoleBindingDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleBindingServer) {
 	request := &RoleBindingDeleteServerRequest{}
 	err := readRoleBindingDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RoleBindingDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRoleBindingDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -200,31 +193,24 @@ func adaptRoleBindingDeleteRequest(w http.ResponseWriter, r *http.Request, serve
 // adaptRoleBindingGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRoleBindingGetRequest(w http.ResponseWriter, r *http.Request, server RoleBindingServer) {
+func adaptRoleBindingGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleBindingServer) {
 	request := &RoleBindingGetServerRequest{}
 	err := readRoleBindingGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RoleBindingGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRoleBindingGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -235,31 +221,24 @@ func adaptRoleBindingGetRequest(w http.ResponseWriter, r *http.Request, server R
 // adaptRoleBindingUpdateRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRoleBindingUpdateRequest(w http.ResponseWriter, r *http.Request, server RoleBindingServer) {
+func adaptRoleBindingUpdateRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleBindingServer) {
 	request := &RoleBindingUpdateServerRequest{}
 	err := readRoleBindingUpdateRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RoleBindingUpdateServerResponse{}
 	response.status = 204
 	err = server.Update(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRoleBindingUpdateResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readRoleBindingsAddRequest(request *RoleBindingsAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalRoleBinding(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, roleBindingValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalRoleBinding(body)
 	return err
 }
 func writeRoleBindingsAddRequest(request *RoleBindingsAddRequest, writer io.Writer) error {
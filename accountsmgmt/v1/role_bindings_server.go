@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // RoleBindingsServer represents the interface the manages the 'role_bindings' resource.
@@ -245,17 +245,17 @@ func (r *RoleBindingsListServerResponse) Status(value int) *RoleBindingsListServ
 // dispatchRoleBindings navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchRoleBindings(w http.ResponseWriter, r *http.Request, server RoleBindingsServer, segments []string) {
+func dispatchRoleBindings(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleBindingsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptRoleBindingsAddRequest(w, r, server)
+			adaptRoleBindingsAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptRoleBindingsListRequest(w, r, server)
+			adaptRoleBindingsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -263,41 +263,34 @@ func dispatchRoleBindings(w http.ResponseWriter, r *http.Request, server RoleBin
 	default:
 		target := server.RoleBinding(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRoleBinding(w, r, target, segments[1:])
+		dispatchRoleBinding(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptRoleBindingsAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRoleBindingsAddRequest(w http.ResponseWriter, r *http.Request, server RoleBindingsServer) {
+func adaptRoleBindingsAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleBindingsServer) {
 	request := &RoleBindingsAddServerRequest{}
 	err := readRoleBindingsAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RoleBindingsAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRoleBindingsAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -308,31 +301,24 @@ func adaptRoleBindingsAddRequest(w http.ResponseWriter, r *http.Request, server
 // adaptRoleBindingsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRoleBindingsListRequest(w http.ResponseWriter, r *http.Request, server RoleBindingsServer) {
+func adaptRoleBindingsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleBindingsServer) {
 	request := &RoleBindingsListServerRequest{}
 	err := readRoleBindingsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RoleBindingsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRoleBindingsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
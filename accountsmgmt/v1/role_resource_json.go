@@ -21,7 +21,10 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readRoleDeleteRequest(request *RoleDeleteServerRequest, r *http.Request) error {
@@ -51,8 +54,15 @@ func writeRoleGetResponse(response *RoleGetServerResponse, w http.ResponseWriter
 	return MarshalRole(response.body, w)
 }
 func readRoleUpdateRequest(request *RoleUpdateServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalRole(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, roleValidation, helpers.ValidateUpdate)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalRole(body)
 	return err
 }
 func writeRoleUpdateRequest(request *RoleUpdateRequest, writer io.Writer) error {
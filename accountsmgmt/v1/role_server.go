@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // RoleServer represents the interface the manages the 'role' resource.
@@ -138,26 +138,26 @@ func (r *RoleUpdateServerResponse) Status(value int) *RoleUpdateServerResponse {
 // dispatchRole navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchRole(w http.ResponseWriter, r *http.Request, server RoleServer, segments []string) {
+func dispatchRole(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptRoleDeleteRequest(w, r, server)
+			adaptRoleDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptRoleGetRequest(w, r, server)
+			adaptRoleGetRequest(w, r, logger, server)
 			return
 		case "PATCH":
-			adaptRoleUpdateRequest(w, r, server)
+			adaptRoleUpdateRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -165,31 +165,24 @@ func dispatchRole(w http.ResponseWriter, r *http.Request, server RoleServer, seg
 // adaptRoleDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRoleDeleteRequest(w http.ResponseWriter, r *http.Request, server RoleServer) {
+func adaptRoleDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleServer) {
 	request := &RoleDeleteServerRequest{}
 	err := readRoleDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RoleDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRoleDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -200,31 +193,24 @@ func adaptRoleDeleteRequest(w http.ResponseWriter, r *http.Request, server RoleS
 // adaptRoleGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRoleGetRequest(w http.ResponseWriter, r *http.Request, server RoleServer) {
+func adaptRoleGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleServer) {
 	request := &RoleGetServerRequest{}
 	err := readRoleGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RoleGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRoleGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -235,31 +221,24 @@ func adaptRoleGetRequest(w http.ResponseWriter, r *http.Request, server RoleServ
 // adaptRoleUpdateRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRoleUpdateRequest(w http.ResponseWriter, r *http.Request, server RoleServer) {
+func adaptRoleUpdateRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RoleServer) {
 	request := &RoleUpdateServerRequest{}
 	err := readRoleUpdateRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RoleUpdateServerResponse{}
 	response.status = 204
 	err = server.Update(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRoleUpdateResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readRolesAddRequest(request *RolesAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalRole(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, roleValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalRole(body)
 	return err
 }
 func writeRolesAddRequest(request *RolesAddRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // RolesServer represents the interface the manages the 'roles' resource.
@@ -245,17 +245,17 @@ func (r *RolesListServerResponse) Status(value int) *RolesListServerResponse {
 // dispatchRoles navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchRoles(w http.ResponseWriter, r *http.Request, server RolesServer, segments []string) {
+func dispatchRoles(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RolesServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptRolesAddRequest(w, r, server)
+			adaptRolesAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptRolesListRequest(w, r, server)
+			adaptRolesListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -263,41 +263,34 @@ func dispatchRoles(w http.ResponseWriter, r *http.Request, server RolesServer, s
 	default:
 		target := server.Role(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRole(w, r, target, segments[1:])
+		dispatchRole(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptRolesAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRolesAddRequest(w http.ResponseWriter, r *http.Request, server RolesServer) {
+func adaptRolesAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RolesServer) {
 	request := &RolesAddServerRequest{}
 	err := readRolesAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RolesAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRolesAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -308,31 +301,24 @@ func adaptRolesAddRequest(w http.ResponseWriter, r *http.Request, server RolesSe
 // adaptRolesListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptRolesListRequest(w http.ResponseWriter, r *http.Request, server RolesServer) {
+func adaptRolesListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server RolesServer) {
 	request := &RolesListServerRequest{}
 	err := readRolesListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &RolesListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeRolesListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,6 +23,8 @@ import (
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // Server represents the interface the manages the 'root' resource.
@@ -115,11 +117,14 @@ type Server interface {
 // Dispatch navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []string) {
+func Dispatch(w http.ResponseWriter, r *http.Request, logger logging.Logger, server Server, segments []string) {
+	w, r, end := helpers.StartServerSpan(w, r, "/api/accounts_mgmt/v1")
+	defer end()
+	defer errors.RecoverPanic(w, r, logger)
 	if len(segments) == 0 {
 		switch r.Method {
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -127,110 +132,110 @@ func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []
 	case "skus":
 		target := server.SKUS()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchSKUS(w, r, target, segments[1:])
+		dispatchSKUS(w, r, logger, target, segments[1:])
 	case "access_token":
 		target := server.AccessToken()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAccessToken(w, r, target, segments[1:])
+		dispatchAccessToken(w, r, logger, target, segments[1:])
 	case "accounts":
 		target := server.Accounts()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAccounts(w, r, target, segments[1:])
+		dispatchAccounts(w, r, logger, target, segments[1:])
 	case "cluster_authorizations":
 		target := server.ClusterAuthorizations()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchClusterAuthorizations(w, r, target, segments[1:])
+		dispatchClusterAuthorizations(w, r, logger, target, segments[1:])
 	case "cluster_registrations":
 		target := server.ClusterRegistrations()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchClusterRegistrations(w, r, target, segments[1:])
+		dispatchClusterRegistrations(w, r, logger, target, segments[1:])
 	case "current_access":
 		target := server.CurrentAccess()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRoles(w, r, target, segments[1:])
+		dispatchRoles(w, r, logger, target, segments[1:])
 	case "current_account":
 		target := server.CurrentAccount()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchCurrentAccount(w, r, target, segments[1:])
+		dispatchCurrentAccount(w, r, logger, target, segments[1:])
 	case "organizations":
 		target := server.Organizations()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchOrganizations(w, r, target, segments[1:])
+		dispatchOrganizations(w, r, logger, target, segments[1:])
 	case "permissions":
 		target := server.Permissions()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchPermissions(w, r, target, segments[1:])
+		dispatchPermissions(w, r, logger, target, segments[1:])
 	case "registries":
 		target := server.Registries()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRegistries(w, r, target, segments[1:])
+		dispatchRegistries(w, r, logger, target, segments[1:])
 	case "registry_credentials":
 		target := server.RegistryCredentials()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRegistryCredentials(w, r, target, segments[1:])
+		dispatchRegistryCredentials(w, r, logger, target, segments[1:])
 	case "resource_quota":
 		target := server.ResourceQuota()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchResourceQuotas(w, r, target, segments[1:])
+		dispatchResourceQuotas(w, r, logger, target, segments[1:])
 	case "role_bindings":
 		target := server.RoleBindings()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRoleBindings(w, r, target, segments[1:])
+		dispatchRoleBindings(w, r, logger, target, segments[1:])
 	case "roles":
 		target := server.Roles()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchRoles(w, r, target, segments[1:])
+		dispatchRoles(w, r, logger, target, segments[1:])
 	case "subscriptions":
 		target := server.Subscriptions()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchSubscriptions(w, r, target, segments[1:])
+		dispatchSubscriptions(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // SKUServer represents the interface the manages the 'SKU' resource.
@@ -64,20 +64,20 @@ func (r *SKUGetServerResponse) Status(value int) *SKUGetServerResponse {
 // dispatchSKU navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchSKU(w http.ResponseWriter, r *http.Request, server SKUServer, segments []string) {
+func dispatchSKU(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SKUServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptSKUGetRequest(w, r, server)
+			adaptSKUGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -85,31 +85,24 @@ func dispatchSKU(w http.ResponseWriter, r *http.Request, server SKUServer, segme
 // adaptSKUGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptSKUGetRequest(w http.ResponseWriter, r *http.Request, server SKUServer) {
+func adaptSKUGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SKUServer) {
 	request := &SKUGetServerRequest{}
 	err := readSKUGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &SKUGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeSKUGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // SKUSServer represents the interface the manages the 'SKUS' resource.
@@ -192,14 +192,14 @@ func (r *SKUSListServerResponse) Status(value int) *SKUSListServerResponse {
 // dispatchSKUS navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchSKUS(w http.ResponseWriter, r *http.Request, server SKUSServer, segments []string) {
+func dispatchSKUS(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SKUSServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptSKUSListRequest(w, r, server)
+			adaptSKUSListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -207,41 +207,34 @@ func dispatchSKUS(w http.ResponseWriter, r *http.Request, server SKUSServer, seg
 	default:
 		target := server.SKU(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchSKU(w, r, target, segments[1:])
+		dispatchSKU(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptSKUSListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptSKUSListRequest(w http.ResponseWriter, r *http.Request, server SKUSServer) {
+func adaptSKUSListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SKUSServer) {
 	request := &SKUSListServerRequest{}
 	err := readSKUSListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &SKUSListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeSKUSListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // SubscriptionReservedResourcesServer represents the interface the manages the 'subscription_reserved_resources' resource.
@@ -144,14 +144,14 @@ func (r *SubscriptionReservedResourcesListServerResponse) Status(value int) *Sub
 // dispatchSubscriptionReservedResources navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchSubscriptionReservedResources(w http.ResponseWriter, r *http.Request, server SubscriptionReservedResourcesServer, segments []string) {
+func dispatchSubscriptionReservedResources(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SubscriptionReservedResourcesServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptSubscriptionReservedResourcesListRequest(w, r, server)
+			adaptSubscriptionReservedResourcesListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -159,41 +159,34 @@ func dispatchSubscriptionReservedResources(w http.ResponseWriter, r *http.Reques
 	default:
 		target := server.ReservedResource(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchSubscriptionReservedResource(w, r, target, segments[1:])
+		dispatchSubscriptionReservedResource(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptSubscriptionReservedResourcesListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptSubscriptionReservedResourcesListRequest(w http.ResponseWriter, r *http.Request, server SubscriptionReservedResourcesServer) {
+func adaptSubscriptionReservedResourcesListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SubscriptionReservedResourcesServer) {
 	request := &SubscriptionReservedResourcesListServerRequest{}
 	err := readSubscriptionReservedResourcesListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &SubscriptionReservedResourcesListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeSubscriptionReservedResourcesListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // SubscriptionServer represents the interface the manages the 'subscription' resource.
@@ -91,17 +91,17 @@ func (r *SubscriptionGetServerResponse) Status(value int) *SubscriptionGetServer
 // dispatchSubscription navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchSubscription(w http.ResponseWriter, r *http.Request, server SubscriptionServer, segments []string) {
+func dispatchSubscription(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SubscriptionServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptSubscriptionDeleteRequest(w, r, server)
+			adaptSubscriptionDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptSubscriptionGetRequest(w, r, server)
+			adaptSubscriptionGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -109,12 +109,12 @@ func dispatchSubscription(w http.ResponseWriter, r *http.Request, server Subscri
 	case "reserved_resources":
 		target := server.ReservedResources()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchSubscriptionReservedResources(w, r, target, segments[1:])
+		dispatchSubscriptionReservedResources(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -122,31 +122,24 @@ func dispatchSubscription(w http.ResponseWriter, r *http.Request, server Subscri
 // adaptSubscriptionDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptSubscriptionDeleteRequest(w http.ResponseWriter, r *http.Request, server SubscriptionServer) {
+func adaptSubscriptionDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SubscriptionServer) {
 	request := &SubscriptionDeleteServerRequest{}
 	err := readSubscriptionDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &SubscriptionDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeSubscriptionDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -157,31 +150,24 @@ func adaptSubscriptionDeleteRequest(w http.ResponseWriter, r *http.Request, serv
 // adaptSubscriptionGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptSubscriptionGetRequest(w http.ResponseWriter, r *http.Request, server SubscriptionServer) {
+func adaptSubscriptionGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SubscriptionServer) {
 	request := &SubscriptionGetServerRequest{}
 	err := readSubscriptionGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &SubscriptionGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeSubscriptionGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // SubscriptionsServer represents the interface the manages the 'subscriptions' resource.
@@ -239,14 +239,14 @@ func (r *SubscriptionsListServerResponse) Status(value int) *SubscriptionsListSe
 // dispatchSubscriptions navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchSubscriptions(w http.ResponseWriter, r *http.Request, server SubscriptionsServer, segments []string) {
+func dispatchSubscriptions(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SubscriptionsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptSubscriptionsListRequest(w, r, server)
+			adaptSubscriptionsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -254,41 +254,34 @@ func dispatchSubscriptions(w http.ResponseWriter, r *http.Request, server Subscr
 	default:
 		target := server.Subscription(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchSubscription(w, r, target, segments[1:])
+		dispatchSubscription(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptSubscriptionsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptSubscriptionsListRequest(w http.ResponseWriter, r *http.Request, server SubscriptionsServer) {
+func adaptSubscriptionsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SubscriptionsServer) {
 	request := &SubscriptionsListServerRequest{}
 	err := readSubscriptionsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &SubscriptionsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeSubscriptionsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -0,0 +1,627 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// IMPORTANT: This file has been generated automatically, refrain from modifying it manually as all
+// your changes will be lost when the file is generated again.
+
+package v1 // github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1
+
+import (
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+)
+
+// actionValidation describes the 'action' enumerated type.
+var actionValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationEnum,
+	Values: []string{
+		"create",
+		"delete",
+		"get",
+		"list",
+		"update",
+	},
+}
+
+// skuValidation describes the 'SKU' type.
+var skuValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// accessTokenValidation describes the 'access_token' type.
+var accessTokenValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// accessTokenAuthValidation describes the 'access_token_auth' type.
+var accessTokenAuthValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// accountValidation describes the 'account' type.
+var accountValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterAuthorizationRequestValidation describes the 'cluster_authorization_request' type.
+var clusterAuthorizationRequestValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterAuthorizationResponseValidation describes the 'cluster_authorization_response' type.
+var clusterAuthorizationResponseValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterRegistrationRequestValidation describes the 'cluster_registration_request' type.
+var clusterRegistrationRequestValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterRegistrationResponseValidation describes the 'cluster_registration_response' type.
+var clusterRegistrationResponseValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// organizationValidation describes the 'organization' type.
+var organizationValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// permissionValidation describes the 'permission' type.
+var permissionValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// planValidation describes the 'plan' type.
+var planValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// quotaSummaryValidation describes the 'quota_summary' type.
+var quotaSummaryValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// registryValidation describes the 'registry' type.
+var registryValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// registryCredentialValidation describes the 'registry_credential' type.
+var registryCredentialValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// reservedResourceValidation describes the 'reserved_resource' type.
+var reservedResourceValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// resourceValidation describes the 'resource' type.
+var resourceValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// resourceQuotaValidation describes the 'resource_quota' type.
+var resourceQuotaValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// roleValidation describes the 'role' type.
+var roleValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// roleBindingValidation describes the 'role_binding' type.
+var roleBindingValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// subscriptionValidation describes the 'subscription' type.
+var subscriptionValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// init sets the attributes of the descriptions of the object types. This isn't done in the
+// declarations because the types may refer to each other.
+func init() {
+	skuValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"byoc": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"availability_zone_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"resources": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: resourceValidation,
+			},
+		},
+	}
+	accessTokenValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"auths": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationMap,
+				Item: accessTokenAuthValidation,
+			},
+		},
+	}
+	accessTokenAuthValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"auth": {
+			Type: helpers.ValidationStringType,
+		},
+		"email": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	accountValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"ban_code": {
+			Type: helpers.ValidationStringType,
+		},
+		"ban_description": {
+			Type: helpers.ValidationStringType,
+		},
+		"banned": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"email": {
+			Type: helpers.ValidationStringType,
+		},
+		"first_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"last_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+		"organization": {
+			Type: organizationValidation,
+		},
+		"username": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	clusterAuthorizationRequestValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"byoc": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"account_username": {
+			Type: helpers.ValidationStringType,
+		},
+		"availability_zone": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"disconnected": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"display_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"external_cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"managed": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"reserve": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"resources": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: reservedResourceValidation,
+			},
+		},
+	}
+	clusterAuthorizationResponseValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"allowed": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"excess_resources": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: reservedResourceValidation,
+			},
+		},
+		"subscription": {
+			Type: subscriptionValidation,
+		},
+	}
+	clusterRegistrationRequestValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"authorization_token": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	clusterRegistrationResponseValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"account_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"authorization_token": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"expires_at": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	organizationValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"external_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	permissionValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"action": {
+			Type: actionValidation,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"role_id": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	planValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+	}
+	quotaSummaryValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"byoc": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"allowed": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"availability_zone_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"organization_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"reserved": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"resource_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	registryValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"url": {
+			Type: helpers.ValidationStringType,
+		},
+		"cloud_alias": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+		"org_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"team_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"type": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	registryCredentialValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"account": {
+			Type: accountValidation,
+		},
+		"registry": {
+			Type: registryValidation,
+		},
+		"token": {
+			Type: helpers.ValidationStringType,
+		},
+		"username": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	reservedResourceValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"byoc": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"availability_zone_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"count": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"created_at": {
+			Type: helpers.ValidationDateType,
+		},
+		"resource_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"updated_at": {
+			Type: helpers.ValidationDateType,
+		},
+	}
+	resourceValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"allowed": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"resource_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	resourceQuotaValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"byoc": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"sku": {
+			Type: helpers.ValidationStringType,
+		},
+		"allowed": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"availability_zone_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"organization_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"reserved": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"resource_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"type": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	roleValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+		"permissions": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: permissionValidation,
+			},
+		},
+	}
+	roleBindingValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"account": {
+			Type: accountValidation,
+		},
+		"account_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"config_managed": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"organization": {
+			Type: organizationValidation,
+		},
+		"organization_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"role": {
+			Type: roleValidation,
+		},
+		"role_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"subscription": {
+			Type: subscriptionValidation,
+		},
+		"subscription_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"type": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	subscriptionValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"created_at": {
+			Type:     helpers.ValidationDateType,
+			ReadOnly: true,
+		},
+		"creator": {
+			Type: accountValidation,
+		},
+		"display_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"external_cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"last_telemetry_date": {
+			Type: helpers.ValidationDateType,
+		},
+		"organization_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"plan": {
+			Type: planValidation,
+		},
+		"registry_credential": {
+			Type: registryCredentialValidation,
+		},
+		"updated_at": {
+			Type:     helpers.ValidationDateType,
+			ReadOnly: true,
+		},
+	}
+}
@@ -60,8 +60,33 @@ func BearerFromContext(ctx context.Context) (result string, err error) {
 	return
 }
 
+// ContextWithRule creates a new context containing the name of the authorization policy rule that
+// allowed access.
+func ContextWithRule(parent context.Context, rule string) context.Context {
+	return context.WithValue(parent, ruleKeyValue, rule)
+}
+
+// RuleFromContext extracts from the context the name of the authorization policy rule that allowed
+// access. If no rule is found in the context then the result will be the empty string.
+func RuleFromContext(ctx context.Context) (result string, err error) {
+	switch rule := ctx.Value(ruleKeyValue).(type) {
+	case nil:
+	case string:
+		result = rule
+	default:
+		err = fmt.Errorf(
+			"expected a string in the '%s' context value, but got '%T'",
+			ruleKeyValue, rule,
+		)
+	}
+	return
+}
+
 // tokenKeyType is the type of the key used to store the token in the context.
 type tokenKeyType string
 
 // tokenKeyValue is the key used to store the token in the context:
 const tokenKeyValue tokenKeyType = "token"
+
+// ruleKeyValue is the key used to store the name of the policy rule in the context:
+const ruleKeyValue tokenKeyType = "rule"
@@ -69,3 +69,19 @@ var _ = Describe("Get bearer from context", func() {
 		Expect(extracted).To(BeEmpty())
 	})
 })
+
+var _ = Describe("Get rule from context", func() {
+	It("Succeeds if there is a rule", func() {
+		ctx := ContextWithRule(context.TODO(), "myrule")
+		extracted, err := RuleFromContext(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extracted).To(Equal("myrule"))
+	})
+
+	It("Succeeds if there is no rule", func() {
+		ctx := context.TODO()
+		extracted, err := RuleFromContext(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(extracted).To(BeEmpty())
+	})
+})
@@ -14,19 +14,18 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Package authentication contains an HTTP handler that checks the bearer tokens of requests. Note
+// that building the handler registers the `EdDSA` signing method globally with the
+// github.com/dgrijalva/jwt-go library, as that library doesn't support it and only has a global
+// registry of signing methods. A method with that name that was already registered is preserved.
 package authentication
 
 import (
 	"context"
-	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"math/big"
 	"net/http"
 	"net/url"
 	"os"
@@ -37,9 +36,16 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/ghodss/yaml"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/propagators"
 
 	"github.com/openshift-online/ocm-sdk-go"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/internal"
 )
 
 // HandlerBuilder contains the data and logic needed to create a new authentication handler. Don't
@@ -51,10 +57,41 @@ type HandlerBuilder struct {
 	publicPaths  []string
 	keysFiles    []string
 	keysURLs     []string
+	issuers      []string
 	keysCAs      *x509.CertPool
 	keysInsecure bool
 	aclFiles     []string
 	next         http.Handler
+
+	// Keys refresh:
+	keysRefreshInterval time.Duration
+	keysReloadInterval  time.Duration
+
+	// Metrics:
+	subsystem string
+
+	// Authorization policies:
+	policyFiles []string
+
+	// Claims validation:
+	acceptedIssuers []string
+	audiences       []string
+	requiredScopes  []string
+	leeway          time.Duration
+
+	// Token introspection:
+	introspectionURL          string
+	introspectionClientID     string
+	introspectionClientSecret string
+	introspectionCacheSize    int
+	introspectionCacheTTL     time.Duration
+
+	// Identity:
+	claimMapping ClaimMapping
+
+	// Tracing:
+	tracerProvider  trace.TracerProvider
+	tracePropagator otel.TextMapPropagator
 }
 
 // Handler is an HTTP handler that checks authentication using the JWT tokens from the authorization
@@ -70,16 +107,58 @@ type Handler struct {
 	keysFiles       []string
 	keysURLs        []string
 	keysClient      *http.Client
-	keys            *sync.Map
-	lastKeyReload   time.Time
 	aclItems        map[string]*regexp.Regexp
 	next            http.Handler
+
+	// Keys, and the data needed to refresh them:
+	keysMutex           *sync.Mutex
+	keys                map[string]*internal.Key
+	keysSources         map[string]map[string]*internal.Key
+	keysExpiry          time.Time
+	keysLoad            chan struct{}
+	lastKeyReload       time.Time
+	keysRefreshInterval time.Duration
+	keysReloadInterval  time.Duration
+
+	// Metrics:
+	keysMetric      *prometheus.CounterVec
+	keysCountMetric prometheus.Gauge
+
+	// Authorization policies:
+	policyRules []*policyRule
+
+	// Claims validation:
+	acceptedIssuers map[string]bool
+	audiences       map[string]bool
+	requiredScopes  []string
+	leeway          time.Duration
+
+	// Token introspection:
+	introspectionURL          string
+	introspectionClientID     string
+	introspectionClientSecret string
+	introspectionCache        *introspectionCache
+
+	// Identity:
+	claimMapping *ClaimMapping
+
+	// Tracing:
+	tracer          trace.Tracer
+	tracePropagator otel.TextMapPropagator
 }
 
 // NewHandler creates a builder that can then be configured and used to create authentication
 // handlers.
 func NewHandler() *HandlerBuilder {
-	return &HandlerBuilder{}
+	return &HandlerBuilder{
+		keysRefreshInterval: defaultKeysRefreshInterval,
+		keysReloadInterval:  defaultKeysReloadInterval,
+
+		introspectionCacheSize: defaultIntrospectionCacheSize,
+		introspectionCacheTTL:  defaultIntrospectionCacheTTL,
+
+		claimMapping: RedHatSSOClaims,
+	}
 }
 
 // Logger sets the logger that the middleware will use to send messages to the logger. This is
@@ -157,6 +236,105 @@ func (b *HandlerBuilder) KeysURL(value string) *HandlerBuilder {
 	return b
 }
 
+// Issuer sets the URL of an OpenID provider whose tokens will be accepted. The URL of the JSON web
+// key set of the provider will be obtained using OpenID discovery, loading the
+// `/.well-known/openid-configuration` document of the issuer when the handler is built. This method
+// may be called multiple times to accept tokens from multiple providers.
+//
+// At least one keys file, one keys URL or one issuer is mandatory.
+func (b *HandlerBuilder) Issuer(value string) *HandlerBuilder {
+	if value != "" {
+		b.issuers = append(b.issuers, value)
+	}
+	return b
+}
+
+// AcceptedIssuers sets the values of the `iss` claim that will be accepted. Tokens whose issuer isn't one
+// of these values will be rejected. This method may be called multiple times, and then all the
+// given values will be accepted. Trailing slashes are ignored when comparing issuers. If no issuer
+// is given the `iss` claim isn't checked.
+//
+// Note that this is different to the Issuer method, which is used to discover the keys of an
+// OpenID provider, and doesn't restrict the issuers that are accepted.
+func (b *HandlerBuilder) AcceptedIssuers(values ...string) *HandlerBuilder {
+	for _, value := range values {
+		if value != "" {
+			b.acceptedIssuers = append(b.acceptedIssuers, value)
+		}
+	}
+	return b
+}
+
+// Audiences sets the audiences that will be accepted. Tokens will be accepted only if one of the
+// values of the `aud` claim, or the value of the `azp` claim, is one of these values. This method
+// may be called multiple times, and then all the given values will be accepted. If no audience is
+// given the audience of the token isn't checked.
+func (b *HandlerBuilder) Audiences(values ...string) *HandlerBuilder {
+	for _, value := range values {
+		if value != "" {
+			b.audiences = append(b.audiences, value)
+		}
+	}
+	return b
+}
+
+// RequiredScopes sets the scopes that tokens must have. Tokens will be accepted only if the
+// space separated list of scopes in the `scope` claim contains all these scopes. This method may be
+// called multiple times, and then all the given scopes will be required.
+func (b *HandlerBuilder) RequiredScopes(values ...string) *HandlerBuilder {
+	for _, value := range values {
+		if value != "" {
+			b.requiredScopes = append(b.requiredScopes, value)
+		}
+	}
+	return b
+}
+
+// Leeway sets the tolerance for differences between the clock of the server that issued the token
+// and the local clock, used when checking the `exp`, `iat` and `nbf` claims. The default value is
+// zero.
+func (b *HandlerBuilder) Leeway(value time.Duration) *HandlerBuilder {
+	b.leeway = value
+	return b
+}
+
+// IntrospectionURL sets the URL of the token introspection endpoint, as described in RFC 7662. When
+// this is set the handler will send the tokens that pass the signature and claims checks to this
+// endpoint, and will reject them if they aren't active, for example because the session has been
+// revoked. It will also accept opaque tokens, that aren't JSON web tokens, verifying them only
+// with the introspection endpoint and using the fields of the introspection response as claims.
+//
+// The certificate authorities and insecure flag used to load keys are also used to connect to
+// this endpoint.
+func (b *HandlerBuilder) IntrospectionURL(value string) *HandlerBuilder {
+	b.introspectionURL = value
+	return b
+}
+
+// IntrospectionClient sets the client identifier and secret that will be used to authenticate to
+// the introspection endpoint, using basic authentication.
+func (b *HandlerBuilder) IntrospectionClient(id, secret string) *HandlerBuilder {
+	b.introspectionClientID = id
+	b.introspectionClientSecret = secret
+	return b
+}
+
+// IntrospectionCacheSize sets the maximum number of introspection results that will be kept in
+// memory. When the cache is full the least recently used results are discarded. The default value
+// is 1000. A value of zero disables the cache.
+func (b *HandlerBuilder) IntrospectionCacheSize(value int) *HandlerBuilder {
+	b.introspectionCacheSize = value
+	return b
+}
+
+// IntrospectionCacheTTL sets the time that introspection results will be kept in the cache. Results
+// are never kept after the expiration time of the token. Note that a revoked token may be accepted
+// during this time. The default value is one minute.
+func (b *HandlerBuilder) IntrospectionCacheTTL(value time.Duration) *HandlerBuilder {
+	b.introspectionCacheTTL = value
+	return b
+}
+
 // KeysCAs sets the certificate authorities that will be trusted when verifying the certificate of
 // the web server where keys are loaded from.
 func (b *HandlerBuilder) KeysCAs(value *x509.CertPool) *HandlerBuilder {
@@ -172,6 +350,46 @@ func (b *HandlerBuilder) KeysInsecure(value bool) *HandlerBuilder {
 	return b
 }
 
+// KeysRefreshInterval sets the maximum time that keys loaded from files and URLs will be used
+// before loading them again, so that keys rotated by the identity provider are picked up and keys
+// that have been retired are removed. If the server that returns the keys sends a `Cache-Control`
+// header with a shorter `max-age` then that will be used instead. The refresh happens in the
+// background, triggered by the first request received after the interval expires. The default
+// value is one hour.
+func (b *HandlerBuilder) KeysRefreshInterval(value time.Duration) *HandlerBuilder {
+	b.keysRefreshInterval = value
+	return b
+}
+
+// KeysReloadInterval sets the minimum time between two consecutive reloads of the keys. Reloads
+// are triggered when a token signed with an unknown key is received, and this limit avoids
+// sending a flood of requests to the server when many of those tokens are received. It is also
+// the minimum time between periodic refreshes. The default value is one minute.
+func (b *HandlerBuilder) KeysReloadInterval(value time.Duration) *HandlerBuilder {
+	b.keysReloadInterval = value
+	return b
+}
+
+// Metrics sets the name of the subsystem that will be used by the handler to register metrics
+// with Prometheus. If this isn't explicitly specified, or if it is an empty string, then no
+// metrics will be registered. For example, if the value is `api_inbound` then the following
+// metrics will be registered:
+//
+//	api_inbound_keys_refresh_count - Number of attempts to refresh the keys.
+//	api_inbound_keys_count - Number of keys currently used to verify tokens.
+//
+// The keys refresh count metric will contain the following labels:
+//
+//	reason - Why the keys were refreshed, `expired` or `unknown_key`.
+//	result - Result of the attempt, `success`, `failure` or `rate_limited`.
+//
+// Note that setting this attribute is not enough to have metrics published, you also need to
+// create and start a metrics server, as described in the documentation of the Prometheus library.
+func (b *HandlerBuilder) Metrics(value string) *HandlerBuilder {
+	b.subsystem = value
+	return b
+}
+
 // ACLFile sets a file that contains items of the access control list. This should be a YAML file
 // with the following format:
 //
@@ -195,6 +413,86 @@ func (b *HandlerBuilder) ACLFile(value string) *HandlerBuilder {
 	return b
 }
 
+// PolicyFile sets a file that contains authorization policy rules. This should be a YAML file
+// with the following format:
+//
+// rules:
+// - name: create-clusters
+//   methods:
+//   - POST
+//   paths:
+//   - ^/api/clusters_mgmt/v1/clusters/?$
+//   when:
+//     any:
+//     - claim: realm_access.roles
+//       values:
+//       - cluster-creator
+//     - claim: org_id
+//       pattern: ^1234$
+//
+// - name: deny-service-accounts
+//   effect: deny
+//   when:
+//     claim: username
+//     pattern: ^service-account-
+//
+// - name: read-only
+//   methods:
+//   - GET
+//
+// The name of the rule is mandatory, and it will be written to the log and added to the request
+// context when the rule matches. The effect can be `allow` or `deny`, and the default is `allow`.
+// The methods and paths fields restrict the requests that the rule applies to, and when they
+// aren't given the rule applies to all the methods and paths. Paths are regular expressions.
+//
+// The optional when field contains a condition on the claims of the token. A condition is one of
+// the following:
+//
+//	all - A list of conditions that must all be satisfied.
+//	any - A list of conditions where at least one must be satisfied.
+//	not - A condition that must not be satisfied.
+//	claim - The name of a claim, optionally with dots to navigate nested objects, together with a
+//	pattern regular expression or a list of values. If the claim is an array, like `groups`,
+//	it is enough if one of the elements matches.
+//
+// If any rule with the `deny` effect matches the request access is denied, regardless of the
+// rules with the `allow` effect that also match. Otherwise access is allowed if at least one rule
+// with the `allow` effect matches, and the name added to the context is the name of the first one,
+// in the order they appear in the files, and files in the order they were added. If no rule
+// matches access is denied. Requests that are denied by the policy are rejected with the 403
+// status code. If no policy file is given then access will be allowed to all JWT tokens that
+// satisfy the access control list.
+func (b *HandlerBuilder) PolicyFile(value string) *HandlerBuilder {
+	if value != "" {
+		b.policyFiles = append(b.policyFiles, value)
+	}
+	return b
+}
+
+// ClaimMapping sets the claims of the token that contain the details of the identity of the caller.
+// The identity is added to the request context, and can be extracted using the IdentityFromContext
+// function. The default is RedHatSSOClaims, and KeycloakClaims can be used for a generic Keycloak
+// server.
+func (b *HandlerBuilder) ClaimMapping(value ClaimMapping) *HandlerBuilder {
+	b.claimMapping = value
+	return b
+}
+
+// TracerProvider sets the OpenTelemetry tracer provider that will be used to create a span for each
+// request received. The default is to use the global tracer provider.
+func (b *HandlerBuilder) TracerProvider(value trace.TracerProvider) *HandlerBuilder {
+	b.tracerProvider = value
+	return b
+}
+
+// TracePropagator sets the OpenTelemetry propagator that will be used to extract the trace context
+// from the headers of the requests received. The default is to use the W3C trace context
+// propagator, which reads the `traceparent` and `tracestate` headers.
+func (b *HandlerBuilder) TracePropagator(value otel.TextMapPropagator) *HandlerBuilder {
+	b.tracePropagator = value
+	return b
+}
+
 // Next sets the HTTP handler that will be called when the authentication handler has authenticated
 // correctly the request. This is mandatory.
 func (b *HandlerBuilder) Next(value http.Handler) *HandlerBuilder {
@@ -222,9 +520,72 @@ func (b *HandlerBuilder) Build() (handler *Handler, err error) {
 		return
 	}
 
-	// Check that there is at least one keys source:
-	if len(b.keysFiles)+len(b.keysURLs) == 0 {
-		err = fmt.Errorf("at least one keys file or one keys URL must be configured")
+	// Check the keys refresh intervals:
+	if b.keysRefreshInterval <= 0 {
+		err = fmt.Errorf(
+			"keys refresh interval %s isn't valid, it must be positive",
+			b.keysRefreshInterval,
+		)
+		return
+	}
+	if b.keysReloadInterval < 0 {
+		err = fmt.Errorf(
+			"keys reload interval %s isn't valid, it must be zero or positive",
+			b.keysReloadInterval,
+		)
+		return
+	}
+
+	// Check the leeway:
+	if b.leeway < 0 {
+		err = fmt.Errorf(
+			"leeway %s isn't valid, it must be zero or positive",
+			b.leeway,
+		)
+		return
+	}
+
+	// Check the introspection parameters:
+	if b.introspectionURL != "" {
+		var parsed *url.URL
+		parsed, err = url.Parse(b.introspectionURL)
+		if err != nil {
+			err = fmt.Errorf(
+				"introspection URL '%s' isn't a valid URL: %v",
+				b.introspectionURL, err,
+			)
+			return
+		}
+		if !strings.EqualFold(parsed.Scheme, "https") {
+			err = fmt.Errorf(
+				"introspection URL '%s' doesn't use the HTTPS protocol",
+				b.introspectionURL,
+			)
+			return
+		}
+	}
+	if b.introspectionCacheSize < 0 {
+		err = fmt.Errorf(
+			"introspection cache size %d isn't valid, it must be zero or positive",
+			b.introspectionCacheSize,
+		)
+		return
+	}
+	if b.introspectionCacheTTL <= 0 {
+		err = fmt.Errorf(
+			"introspection cache TTL %s isn't valid, it must be positive",
+			b.introspectionCacheTTL,
+		)
+		return
+	}
+
+	// Check that there is at least one keys source, or an introspection endpoint that can be
+	// used to verify opaque tokens:
+	if len(b.keysFiles)+len(b.keysURLs)+len(b.issuers) == 0 && b.introspectionURL == "" {
+		err = fmt.Errorf(
+			"at least one keys file, one keys URL, one issuer or an introspection URL " +
+				"must be configured",
+		)
 		return
 	}
 
@@ -244,18 +605,10 @@ func (b *HandlerBuilder) Build() (handler *Handler, err error) {
 
 	// Check that all the configured keys URLs are valid HTTPS URLs:
 	for _, addr := range b.keysURLs {
-		var parsed *url.URL
-		parsed, err = url.Parse(addr)
+		err = checkKeysURL(addr)
 		if err != nil {
-			err = fmt.Errorf("keys URL '%s' isn't a valid URL: %v", addr, err)
 			return
 		}
-		if !strings.EqualFold(parsed.Scheme, "https") {
-			err = fmt.Errorf(
-				"keys URL '%s' doesn't use the HTTPS protocol: %v",
-				addr, err,
-			)
-		}
 	}
 
 	// Create the HTTP client that will be used to load the keys:
@@ -278,8 +631,33 @@ func (b *HandlerBuilder) Build() (handler *Handler, err error) {
 		}
 	}
 
-	// Create the bearer token tokenParser:
-	tokenParser := &jwt.Parser{}
+	// Make sure that the parser supports the EdDSA signing method, as the JWT library doesn't
+	// support it directly. Note that this registers it for all the parsers of the process.
+	internal.RegisterEdDSA()
+
+	// Create the bearer token tokenParser. Note that the time claims aren't validated by the
+	// parser because it doesn't support a leeway, we do it ourselves instead.
+	tokenParser := &jwt.Parser{
+		SkipClaimsValidation: true,
+	}
+
+	// Prepare the sets of accepted issuers and audiences:
+	var acceptedIssuers map[string]bool
+	if len(b.acceptedIssuers) > 0 {
+		acceptedIssuers = map[string]bool{}
+		for _, issuer := range b.acceptedIssuers {
+			acceptedIssuers[strings.TrimRight(issuer, "/")] = true
+		}
+	}
+	var audiences map[string]bool
+	if len(b.audiences) > 0 {
+		audiences = map[string]bool{}
+		for _, audience := range b.audiences {
+			audiences[audience] = true
+		}
+	}
+	requiredScopes := make([]string, len(b.requiredScopes))
+	copy(requiredScopes, b.requiredScopes)
 
 	// Make copies of the lists of keys files and URLs:
 	keysFiles := make([]string, len(b.keysFiles))
@@ -287,8 +665,35 @@ func (b *HandlerBuilder) Build() (handler *Handler, err error) {
 	keysURLs := make([]string, len(b.keysURLs))
 	copy(keysURLs, b.keysURLs)
 
-	// Create the initial empty map of keys:
-	keys := &sync.Map{}
+	// Add the keys URLs of the issuers:
+	for _, issuer := range b.issuers {
+		var document *internal.DiscoveryDocument
+		document, err = internal.Discover(context.Background(), keysClient, issuer)
+		if err != nil {
+			err = fmt.Errorf(
+				"can't discover OpenID configuration of issuer '%s': %v",
+				issuer, err,
+			)
+			return
+		}
+		if document.JWKSURI == "" {
+			err = fmt.Errorf(
+				"OpenID configuration of issuer '%s' doesn't contain the keys URL",
+				issuer,
+			)
+			return
+		}
+		b.logger.Info(
+			context.Background(),
+			"Discovered keys URL '%s' for issuer '%s'",
+			document.JWKSURI, issuer,
+		)
+		err = checkKeysURL(document.JWKSURI)
+		if err != nil {
+			return
+		}
+		keysURLs = append(keysURLs, document.JWKSURI)
+	}
 
 	// Load the ACL files:
 	aclItems := map[string]*regexp.Regexp{}
@@ -299,6 +704,30 @@ func (b *HandlerBuilder) Build() (handler *Handler, err error) {
 		}
 	}
 
+	// Load the policy files:
+	var policyRules []*policyRule
+	for _, file := range b.policyFiles {
+		policyRules, err = b.loadPolicyFile(file, policyRules)
+		if err != nil {
+			return
+		}
+	}
+
+	// Create the tracer, using the global provider if no provider has been configured, and set
+	// the default propagator if needed:
+	tracerProvider := b.tracerProvider
+	if tracerProvider == nil {
+		tracerProvider = global.TracerProvider()
+	}
+	tracer := tracerProvider.Tracer(
+		internal.TracerName,
+		trace.WithInstrumentationVersion(sdk.Version),
+	)
+	tracePropagator := b.tracePropagator
+	if tracePropagator == nil {
+		tracePropagator = propagators.TraceContext{}
+	}
+
 	// Calculate the prefixes used to generate error messages:
 	errorHrefPrefix := fmt.Sprintf("/api/%s/%s/errors", b.service, b.version)
 	errorCodePrefix := strings.ToUpper(strings.ReplaceAll(b.service, "_", "-"))
@@ -315,9 +744,45 @@ func (b *HandlerBuilder) Build() (handler *Handler, err error) {
 		keysFiles:       keysFiles,
 		keysURLs:        keysURLs,
 		keysClient:      keysClient,
-		keys:            keys,
 		aclItems:        aclItems,
 		next:            b.next,
+
+		keysMutex:           &sync.Mutex{},
+		keys:                map[string]*internal.Key{},
+		keysSources:         map[string]map[string]*internal.Key{},
+		keysRefreshInterval: b.keysRefreshInterval,
+		keysReloadInterval:  b.keysReloadInterval,
+
+		policyRules: policyRules,
+
+		acceptedIssuers: acceptedIssuers,
+		audiences:       audiences,
+		requiredScopes:  requiredScopes,
+		leeway:          b.leeway,
+
+		introspectionURL:          b.introspectionURL,
+		introspectionClientID:     b.introspectionClientID,
+		introspectionClientSecret: b.introspectionClientSecret,
+
+		claimMapping: b.claimMapping.copy(),
+
+		tracer:          tracer,
+		tracePropagator: tracePropagator,
+	}
+	if b.introspectionURL != "" && b.introspectionCacheSize > 0 {
+		handler.introspectionCache = newIntrospectionCache(
+			b.introspectionCacheSize,
+			b.introspectionCacheTTL,
+		)
+	}
+
+	// Register metrics:
+	if b.subsystem != "" {
+		err = handler.registerMetrics(b.subsystem)
+		if err != nil {
+			err = fmt.Errorf("can't register metrics: %v", err)
+			return
+		}
 	}
 
 	return
@@ -357,6 +822,10 @@ func (b *HandlerBuilder) loadACLFile(file string, items map[string]*regexp.Regex
 
 // ServeHTTP is the implementation of the HTTP handler interface.
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Extract the trace context from the request headers and start the span:
+	w, r, end := internal.StartServerSpan(h.tracer, h.tracePropagator, w, r, tracingSpan)
+	defer end()
+
 	// Get the context:
 	ctx := r.Context()
 
@@ -397,244 +866,68 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Use the JWT library to verify that the token is correctly signed and that the basic
-	// claims are correct:
-	token, claims, ok := h.checkToken(w, r, bearer)
-	if !ok {
-		return
-	}
-
-	// The library that we use considers token valid if the claims that it checks don't exist,
-	// but we want to reject those tokens, so we need to do some additional validations:
-	ok = h.checkClaims(w, r, claims)
-	if !ok {
-		return
-	}
-
-	// Check if the claims match at least one of the ACL items:
-	ok = h.checkACL(w, r, claims)
-	if !ok {
-		return
-	}
-	// Add the token to the context:
-	ctx = ContextWithToken(ctx, token)
-	r = r.WithContext(ctx)
-
-	// Call the next handler:
-	h.next.ServeHTTP(w, r)
-}
-
-// selectKey selects the key that should be used to verify the given token.
-func (h *Handler) selectKey(ctx context.Context, token *jwt.Token) (key interface{}, err error) {
-	// Get the key identifier:
-	value, ok := token.Header["kid"]
-	if !ok {
-		err = fmt.Errorf("token doesn't have a 'kid' field in the header")
-		return
-	}
-	kid, ok := value.(string)
-	if !ok {
-		err = fmt.Errorf(
-			"token has a 'kid' field, but it is a %T instead of a string",
-			value,
-		)
-		return
-	}
-
-	// Get the key for that key identifier. If there is no such key and we didn't reload keys
-	// recently then we try to reload them now.
-	key, ok = h.keys.Load(kid)
-	if !ok && time.Since(h.lastKeyReload) > 1*time.Minute {
-		err = h.loadKeys(ctx)
-		if err != nil {
+	// Tokens that aren't JSON web tokens can only be verified using the introspection
+	// endpoint:
+	var token *jwt.Token
+	var claims jwt.MapClaims
+	var ok bool
+	if h.introspectionURL != "" && !isJWT(bearer) {
+		token, claims, ok = h.checkOpaqueToken(w, r, bearer)
+		if !ok {
 			return
 		}
-		h.lastKeyReload = time.Now()
-		key, ok = h.keys.Load(kid)
-	}
-	if !ok {
-		err = fmt.Errorf("there is no key for key identifier '%s'", kid)
-		return
-	}
-
-	return
-}
-
-// keyData is the type used to read a single key from a JSON document.
-type keyData struct {
-	Kid string `json:"kid"`
-	Kty string `json:"kty"`
-	Alg string `json:"alg"`
-	Use string `json:"use"`
-	N   string `json:"n"`
-	E   string `json:"e"`
-}
-
-// setData is the type used to read a collection of keys from a JSON document.
-type setData struct {
-	Keys []keyData `json:"keys"`
-}
-
-// loadKeys loads the JSON web key set from the URLs specified in the configuration.
-func (h *Handler) loadKeys(ctx context.Context) error {
-	// Load keys from the files given in the configuration:
-	for _, keysFile := range h.keysFiles {
-		h.logger.Info(ctx, "Loading keys from file '%s'", keysFile)
-		err := h.loadKeysFile(ctx, keysFile)
-		if err != nil {
-			h.logger.Error(ctx, "Can't load keys from file '%s': %v", keysFile, err)
-		}
-	}
-
-	// Load keys from URLs given in the configuration:
-	for _, keysURL := range h.keysURLs {
-		h.logger.Info(ctx, "Loading keys from URL '%s'", keysURL)
-		err := h.loadKeysURL(ctx, keysURL)
-		if err != nil {
-			h.logger.Error(ctx, "Can't load keys from URL '%s': %v", keysURL, err)
+	} else {
+		// Use the JWT library to verify that the token is correctly signed and that the
+		// basic claims are correct:
+		token, claims, ok = h.checkToken(w, r, bearer)
+		if !ok {
+			return
 		}
-	}
-
-	return nil
-}
 
-// loadKeysFile loads a JSON we key set from a file.
-func (h *Handler) loadKeysFile(ctx context.Context, file string) error {
-	reader, err := os.Open(file) // nolint
-	if err != nil {
-		return err
-	}
-	return h.readKeys(ctx, reader)
-}
-
-// loadKeysURL loads a JSON we key set from an URL.
-func (h *Handler) loadKeysURL(ctx context.Context, addr string) error {
-	request, err := http.NewRequest(http.MethodGet, addr, nil)
-	if err != nil {
-		return err
-	}
-	request = request.WithContext(ctx)
-	response, err := h.keysClient.Do(request)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		err := response.Body.Close()
-		if err != nil {
-			h.logger.Error(
-				ctx,
-				"Can't close response body for request to '%s': %v",
-				addr, err,
-			)
+		// The library that we use considers token valid if the claims that it checks don't
+		// exist, but we want to reject those tokens, so we need to do some additional
+		// validations:
+		ok = h.checkClaims(w, r, claims)
+		if !ok {
+			return
 		}
-	}()
-	return h.readKeys(ctx, response.Body)
-}
-
-// readKeys reads the keys from JSON web key set available in the given reader.
-func (h *Handler) readKeys(ctx context.Context, reader io.Reader) error {
-	// Read the JSON data:
-	jsonData, err := ioutil.ReadAll(reader)
-	if err != nil {
-		return err
-	}
-
-	// Parse the JSON data:
-	var setData setData
-	err = json.Unmarshal(jsonData, &setData)
-	if err != nil {
-		return err
-	}
 
-	// Convert the key data to actual keys that can be used to verify the signatures of the
-	// tokens:
-	for _, keyData := range setData.Keys {
-		if h.logger.DebugEnabled() {
-			h.logger.Debug(ctx, "Value of 'kid' is '%s'", keyData.Kid)
-			h.logger.Debug(ctx, "Value of 'kty' is '%s'", keyData.Kty)
-			h.logger.Debug(ctx, "Value of 'alg' is '%s'", keyData.Alg)
-			h.logger.Debug(ctx, "Value of 'e' is '%s'", keyData.E)
-			h.logger.Debug(ctx, "Value of 'n' is '%s'", keyData.N)
-		}
-		if keyData.Kid == "" {
-			h.logger.Error(ctx, "Can't read key because 'kid' is empty")
-			continue
-		}
-		if keyData.Kty == "" {
-			h.logger.Error(
-				ctx,
-				"Can't read key '%s' because 'kty' is empty",
-				keyData.Kid,
-			)
-			continue
-		}
-		if keyData.Alg == "" {
-			h.logger.Error(
-				ctx,
-				"Can't read key '%s' because 'alg' is empty",
-				keyData.Kid,
-			)
-			continue
-		}
-		if keyData.E == "" {
-			h.logger.Error(
-				ctx,
-				"Can't read key '%s' because 'e' is empty",
-				keyData.Kid,
-			)
-			continue
-		}
-		if keyData.E == "" {
-			h.logger.Error(
-				ctx,
-				"Can't read key '%s' because 'n' is empty",
-				keyData.Kid,
-			)
-			continue
-		}
-		var key interface{}
-		key, err = h.parseKey(keyData)
-		if err != nil {
-			h.logger.Error(
-				ctx,
-				"Key '%s' will be ignored because it can't be parsed",
-				keyData.Kid,
-			)
-			continue
+		// Check that the token hasn't been revoked:
+		if h.introspectionURL != "" {
+			_, ok = h.checkIntrospection(w, r, bearer)
+			if !ok {
+				return
+			}
 		}
-		h.keys.Store(keyData.Kid, key)
-		h.logger.Info(ctx, "Loaded key '%s'", keyData.Kid)
 	}
 
-	return nil
-}
+	// Add the identity of the caller to the context, so that it is available to the rest of the
+	// checks and to the next handler:
+	identity := h.claimMapping.identity(claims)
+	ctx = ContextWithIdentity(ctx, identity)
+	r = r.WithContext(ctx)
 
-// parseKey converts the key data loaded from the JSON document to an actual key that can be used
-// to verify the signatures of tokens.
-func (h *Handler) parseKey(data keyData) (key interface{}, err error) {
-	// Check key type:
-	if data.Kty != "RSA" {
-		err = fmt.Errorf("key type '%s' isn't supported", data.Kty)
+	// Check if the claims match at least one of the ACL items:
+	ok = h.checkACL(w, r, claims)
+	if !ok {
 		return
 	}
 
-	// Decode the e and n values:
-	nb, err := base64.RawURLEncoding.DecodeString(data.N)
-	if err != nil {
-		return
-	}
-	eb, err := base64.RawURLEncoding.DecodeString(data.E)
-	if err != nil {
+	// Check the authorization policies:
+	rule, ok := h.checkPolicies(w, r, claims)
+	if !ok {
 		return
 	}
 
-	// Create the key:
-	key = &rsa.PublicKey{
-		N: new(big.Int).SetBytes(nb),
-		E: int(new(big.Int).SetBytes(eb).Int64()),
+	// Add the token, and the policy rule that allowed access, to the context:
+	ctx = ContextWithToken(ctx, token)
+	if rule != "" {
+		ctx = ContextWithRule(ctx, rule)
 	}
+	r = r.WithContext(ctx)
 
-	return
+	// Call the next handler:
+	h.next.ServeHTTP(w, r)
 }
 
 // checkToken checks if the token is valid. If it is valid it returns the parsed token, the
@@ -671,21 +964,6 @@ func (h *Handler) checkToken(w http.ResponseWriter, r *http.Request,
 					w, r,
 					"Signature of bearer token isn't valid",
 				)
-			case typed.Errors&jwt.ValidationErrorExpired != 0:
-				h.sendError(
-					w, r,
-					"Bearer token is expired",
-				)
-			case typed.Errors&jwt.ValidationErrorIssuedAt != 0:
-				h.sendError(
-					w, r,
-					"Bearer token was issued in the future",
-				)
-			case typed.Errors&jwt.ValidationErrorNotValidYet != 0:
-				h.sendError(
-					w, r,
-					"Bearer token isn't valid yet",
-				)
 			default:
 				h.sendError(
 					w, r,
@@ -721,14 +999,144 @@ func (h *Handler) checkClaims(w http.ResponseWriter, r *http.Request,
 		)
 		return false
 	}
-	_, ok = h.checkTimeClaim(w, r, claims, "iat")
+	iat, ok := h.checkTimeClaim(w, r, claims, "iat")
+	if !ok {
+		return false
+	}
+	exp, ok := h.checkTimeClaim(w, r, claims, "exp")
 	if !ok {
 		return false
 	}
-	_, ok = h.checkTimeClaim(w, r, claims, "exp")
+
+	// Check the time claims, tolerating the configured difference between clocks:
+	now := time.Now()
+	if now.After(exp.Add(h.leeway)) {
+		h.sendError(
+			w, r,
+			"Bearer token is expired",
+		)
+		return false
+	}
+	if iat.After(now.Add(h.leeway)) {
+		h.sendError(
+			w, r,
+			"Bearer token was issued in the future",
+		)
+		return false
+	}
+	if _, present := claims["nbf"]; present {
+		var nbf time.Time
+		nbf, ok = h.checkTimeClaim(w, r, claims, "nbf")
+		if !ok {
+			return false
+		}
+		if nbf.After(now.Add(h.leeway)) {
+			h.sendError(
+				w, r,
+				"Bearer token isn't valid yet",
+			)
+			return false
+		}
+	}
+
+	return h.checkRestrictions(w, r, claims)
+}
+
+// checkRestrictions checks that the issuer, audience and scopes of the token are acceptable. If
+// something is wrong it sends an error response to the client and returns false.
+func (h *Handler) checkRestrictions(w http.ResponseWriter, r *http.Request,
+	claims jwt.MapClaims) bool {
+	// Check the issuer:
+	if h.acceptedIssuers != nil {
+		iss, ok := h.checkStringClaim(w, r, claims, "iss")
+		if !ok {
+			return false
+		}
+		if !h.acceptedIssuers[strings.TrimRight(iss, "/")] {
+			h.sendError(
+				w, r,
+				"Bearer token issuer '%s' isn't accepted",
+				iss,
+			)
+			return false
+		}
+	}
+
+	// Check the audience:
+	if h.audiences != nil && !h.checkAudience(w, r, claims) {
+		return false
+	}
+
+	// Check the scopes:
+	if len(h.requiredScopes) > 0 && !h.checkScopes(w, r, claims) {
+		return false
+	}
+
+	return true
+}
+
+// checkAudience checks that the `aud` or `azp` claims contain one of the accepted audiences. If
+// they don't it sends an error response to the client and returns false.
+func (h *Handler) checkAudience(w http.ResponseWriter, r *http.Request,
+	claims jwt.MapClaims) bool {
+	var values []string
+	switch aud := claims["aud"].(type) {
+	case string:
+		values = append(values, aud)
+	case []interface{}:
+		for _, item := range aud {
+			text, ok := item.(string)
+			if ok {
+				values = append(values, text)
+			}
+		}
+	}
+	azp, ok := claims["azp"].(string)
+	if ok {
+		values = append(values, azp)
+	}
+	if len(values) == 0 {
+		h.sendError(
+			w, r,
+			"Bearer token doesn't contain required claim 'aud'",
+		)
+		return false
+	}
+	for _, value := range values {
+		if h.audiences[value] {
+			return true
+		}
+	}
+	h.sendError(
+		w, r,
+		"Bearer token audience '%s' isn't accepted",
+		strings.Join(values, "', '"),
+	)
+	return false
+}
+
+// checkScopes checks that the `scope` claim contains all the required scopes. If it doesn't it
+// sends an error response to the client and returns false.
+func (h *Handler) checkScopes(w http.ResponseWriter, r *http.Request,
+	claims jwt.MapClaims) bool {
+	value, ok := h.checkStringClaim(w, r, claims, "scope")
 	if !ok {
 		return false
 	}
+	scopes := map[string]bool{}
+	for _, scope := range strings.Fields(value) {
+		scopes[scope] = true
+	}
+	for _, scope := range h.requiredScopes {
+		if !scopes[scope] {
+			h.sendError(
+				w, r,
+				"Bearer token doesn't have required scope '%s'",
+				scope,
+			)
+			return false
+		}
+	}
 	return true
 }
 
@@ -824,7 +1232,7 @@ func (h *Handler) checkACL(w http.ResponseWriter, r *http.Request, claims jwt.Ma
 	return false
 }
 
-// sendError sends an error response to the client with the given status code and with a message
+// sendError sends an error response to the client with the 401 status code and with a message
 // compossed using the given format and arguments as the fmt.Sprintf function does.
 func (h *Handler) sendError(w http.ResponseWriter, r *http.Request, format string, args ...interface{}) {
 	// Prepare the headers:
@@ -833,21 +1241,61 @@ func (h *Handler) sendError(w http.ResponseWriter, r *http.Request, format strin
 		fmt.Sprintf("Bearer realm=\"%s/%s\"", h.service, h.version),
 	)
 
+	// Send the response:
+	h.sendStatus(w, r, http.StatusUnauthorized, format, args...)
+}
+
+// sendForbidden sends an error response to the client with the 403 status code. This is used when
+// the token is valid but the authorization policy doesn't allow the request, so sending the token
+// again, or a different one, isn't expected to help, and the `WWW-Authenticate` header isn't added.
+func (h *Handler) sendForbidden(w http.ResponseWriter, r *http.Request, format string,
+	args ...interface{}) {
+	h.sendStatus(w, r, http.StatusForbidden, format, args...)
+}
+
+// sendStatus sends an error response to the client with the given status code and with a message
+// compossed using the given format and arguments as the fmt.Sprintf function does.
+func (h *Handler) sendStatus(w http.ResponseWriter, r *http.Request, status int, format string,
+	args ...interface{}) {
+	// Add the error code to the span:
+	code := fmt.Sprintf("%s-%d", h.errorCodePrefix, status)
+	trace.SpanFromContext(r.Context()).SetAttributes(tracingErrorCodeKey.String(code))
+
 	// Prepare the body:
 	response, err := errors.NewError().
-		ID(fmt.Sprintf("%d", http.StatusUnauthorized)).
-		HREF(fmt.Sprintf("%s/%d", h.errorHrefPrefix, http.StatusUnauthorized)).
-		Code(fmt.Sprintf("%s-%d", h.errorCodePrefix, http.StatusUnauthorized)).
+		ID(fmt.Sprintf("%d", status)).
+		HREF(fmt.Sprintf("%s/%d", h.errorHrefPrefix, status)).
+		Code(code).
 		Reason(fmt.Sprintf(format, args...)).
 		Build()
 	if err != nil {
 		h.logger.Error(r.Context(), "Can't build error response: %v", err)
-		errors.SendPanic(w, r)
+		errors.SendPanicWithLogger(w, r, h.logger)
+		return
 	}
 
 	// Send the response:
-	errors.SendError(w, r, response)
+	errors.SendErrorWithLogger(w, r, h.logger, response)
 }
 
+// checkKeysURL checks that the given keys URL is a valid HTTPS URL.
+func checkKeysURL(addr string) error {
+	parsed, err := url.Parse(addr)
+	if err != nil {
+		return fmt.Errorf("keys URL '%s' isn't a valid URL: %v", addr, err)
+	}
+	if !strings.EqualFold(parsed.Scheme, "https") {
+		return fmt.Errorf("keys URL '%s' doesn't use the HTTPS protocol", addr)
+	}
+	return nil
+}
+
+// Name of the span created for each request, and of the attribute that contains the error code
+// when the request is rejected:
+const (
+	tracingSpan         = "authentication"
+	tracingErrorCodeKey = label.Key("ocm.error_code")
+)
+
 // Regular expression used to extract the bearer token from the authorization header:
 var bearerRE = regexp.MustCompile(`^([a-zA-Z0-9]+)\s+(.*)$`)
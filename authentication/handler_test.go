@@ -19,10 +19,18 @@ limitations under the License.
 package authentication
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -30,6 +38,8 @@ import (
 	. "github.com/onsi/gomega/ghttp"
 
 	"github.com/dgrijalva/jwt-go"
+
+	"github.com/openshift-online/ocm-sdk-go/internal"
 )
 
 var _ = Describe("Handler", func() {
@@ -156,6 +166,26 @@ var _ = Describe("Handler", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("Can't be built with a URL that isn't HTTPS followed by one that is", func() {
+		// Prepare the next handler:
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		// Try to create the handler:
+		_, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysURL("http://api.openshift.com/.well-known/jwks.json").
+			KeysURL("https://api.openshift.com/.well-known/jwks.json").
+			Next(next).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("http://api.openshift.com"))
+		Expect(err.Error()).To(ContainSubstring("HTTPS"))
+	})
+
 	It("Can be built with one keys file", func() {
 		// Prepare the next handler:
 		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -666,6 +696,205 @@ var _ = Describe("Handler", func() {
 		}`))
 	})
 
+	Describe("Claims validation", func() {
+		// Next handler used by these tests:
+		var next http.Handler
+
+		BeforeEach(func() {
+			next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+		})
+
+		// builder returns a handler builder with the mandatory parameters:
+		builder := func() *HandlerBuilder {
+			return NewHandler().
+				Logger(logger).
+				Service("clusters_mgmt").
+				Version("v1").
+				KeysFile(keysFile).
+				Next(next)
+		}
+
+		// send sends a request with a token containing the given claims and returns the
+		// response recorder:
+		send := func(handler *Handler, claims jwt.MapClaims) *httptest.ResponseRecorder {
+			request := httptest.NewRequest(http.MethodGet, "/private", nil)
+			request.Header.Set("Authorization", "Bearer "+IssueBearer(claims))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder
+		}
+
+		// reason returns the expected body for the given reason:
+		reason := func(text string) string {
+			return fmt.Sprintf(`{
+				"kind": "Error",
+				"id": "401",
+				"href": "/api/clusters_mgmt/v1/errors/401",
+				"code": "CLUSTERS-MGMT-401",
+				"reason": "%s"
+			}`, text)
+		}
+
+		It("Accepts expired token within the leeway", func() {
+			handler, err := builder().
+				Leeway(1 * time.Minute).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, jwt.MapClaims{
+				"exp": time.Now().Add(-30 * time.Second).Unix(),
+			})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Rejects expired token outside the leeway", func() {
+			handler, err := builder().
+				Leeway(1 * time.Minute).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, jwt.MapClaims{
+				"exp": time.Now().Add(-2 * time.Minute).Unix(),
+			})
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Body).To(MatchJSON(reason("Bearer token is expired")))
+		})
+
+		It("Accepts token issued in the future within the leeway", func() {
+			handler, err := builder().
+				Leeway(1 * time.Minute).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			iat := time.Now().Add(30 * time.Second)
+			recorder := send(handler, jwt.MapClaims{
+				"iat": iat.Unix(),
+				"nbf": iat.Unix(),
+				"exp": iat.Add(1 * time.Minute).Unix(),
+			})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Can't be built with negative leeway", func() {
+			_, err := builder().
+				Leeway(-1 * time.Second).
+				Build()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("leeway"))
+		})
+
+		It("Accepts token from accepted issuer", func() {
+			handler, err := builder().
+				AcceptedIssuers(
+					"https://sso.example.com",
+					"https://sso.redhat.com/auth/realms/redhat-external/",
+				).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, nil)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Rejects token from issuer that isn't accepted", func() {
+			handler, err := builder().
+				AcceptedIssuers("https://sso.example.com").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, nil)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Body).To(MatchJSON(reason(
+				"Bearer token issuer " +
+					"'https://sso.redhat.com/auth/realms/redhat-external' " +
+					"isn't accepted",
+			)))
+		})
+
+		It("Accepts token with accepted audience", func() {
+			handler, err := builder().
+				Audiences("myservice").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, jwt.MapClaims{
+				"aud": []interface{}{"account", "myservice"},
+			})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Accepts token with accepted authorized party", func() {
+			handler, err := builder().
+				Audiences("myservice").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, jwt.MapClaims{
+				"aud": "account",
+				"azp": "myservice",
+			})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Rejects token with audience that isn't accepted", func() {
+			handler, err := builder().
+				Audiences("myservice").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, jwt.MapClaims{
+				"aud": "otherservice",
+			})
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Body).To(MatchJSON(reason(
+				"Bearer token audience 'otherservice' isn't accepted",
+			)))
+		})
+
+		It("Rejects token without audience", func() {
+			handler, err := builder().
+				Audiences("myservice").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, nil)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Body).To(MatchJSON(reason(
+				"Bearer token doesn't contain required claim 'aud'",
+			)))
+		})
+
+		It("Accepts token with required scopes", func() {
+			handler, err := builder().
+				RequiredScopes("openid", "api.clusters").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, jwt.MapClaims{
+				"scope": "openid email api.clusters",
+			})
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("Rejects token without required scope", func() {
+			handler, err := builder().
+				RequiredScopes("openid", "api.clusters").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, jwt.MapClaims{
+				"scope": "openid email",
+			})
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Body).To(MatchJSON(reason(
+				"Bearer token doesn't have required scope 'api.clusters'",
+			)))
+		})
+
+		It("Rejects token without scope claim", func() {
+			handler, err := builder().
+				RequiredScopes("openid").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			recorder := send(handler, nil)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(recorder.Body).To(MatchJSON(reason(
+				"Bearer token doesn't contain required claim 'scope'",
+			)))
+		})
+	})
+
 	It("Loads keys from file", func() {
 		// Prepare the next handler:
 		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -990,6 +1219,446 @@ var _ = Describe("Handler", func() {
 		Expect(recorder.Code).To(Equal(http.StatusOK))
 	})
 
+	It("Loads keys from the URL discovered from the issuer", func() {
+		// Prepare the server that will return the OpenID configuration and the keys:
+		server := NewTLSServer()
+		defer server.Close()
+		server.RouteToHandler(
+			http.MethodGet,
+			"/.well-known/openid-configuration",
+			RespondWith(http.StatusOK, fmt.Sprintf(
+				`{
+					"issuer": "%s",
+					"jwks_uri": "%s/certs"
+				}`,
+				server.URL(), server.URL(),
+			)),
+		)
+		server.RouteToHandler(
+			http.MethodGet,
+			"/certs",
+			RespondWith(http.StatusOK, keysBytes),
+		)
+
+		// Prepare the next handler:
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		// Prepare the token:
+		bearer := IssueBearer(nil)
+
+		// Prepare the handler:
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			Issuer(server.URL()).
+			KeysInsecure(true).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Send the request:
+		request := httptest.NewRequest(http.MethodGet, "/private", nil)
+		request.Header.Set("Authorization", "Bearer "+bearer)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		// Verify that the request is accepted:
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	})
+
+	It("Can't be built if the discovered keys URL isn't HTTPS", func() {
+		// Prepare the server that will return the OpenID configuration:
+		server := NewTLSServer()
+		defer server.Close()
+		server.RouteToHandler(
+			http.MethodGet,
+			"/.well-known/openid-configuration",
+			RespondWith(http.StatusOK, fmt.Sprintf(
+				`{
+					"issuer": "%s",
+					"jwks_uri": "http://api.openshift.com/.well-known/jwks.json"
+				}`,
+				server.URL(),
+			)),
+		)
+
+		// Prepare the next handler:
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		// Try to create the handler:
+		_, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			Issuer(server.URL()).
+			KeysInsecure(true).
+			Next(next).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("http://api.openshift.com"))
+		Expect(err.Error()).To(ContainSubstring("HTTPS"))
+	})
+
+	It("Can't be built if the issuer can't be discovered", func() {
+		// Prepare the server:
+		server := NewTLSServer()
+		defer server.Close()
+		server.AppendHandlers(
+			RespondWith(http.StatusNotFound, nil),
+		)
+
+		// Prepare the next handler:
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		// Try to create the handler:
+		_, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			Issuer(server.URL()).
+			KeysInsecure(true).
+			Next(next).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("discover"))
+	})
+
+	It("Removes retired keys when they are refreshed", func() {
+		// Prepare the server that will return the keys, initially containing the key used to
+		// sign the tokens:
+		var body atomic.Value
+		body.Store(keysBytes)
+		server := NewTLSServer()
+		defer server.Close()
+		server.RouteToHandler(
+			http.MethodGet,
+			"/",
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Write(body.Load().([]byte)) // nolint
+			},
+		)
+
+		// Prepare the next handler:
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		// Prepare the token:
+		bearer := IssueBearer(nil)
+
+		// Prepare the handler:
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysURL(server.URL()).
+			KeysInsecure(true).
+			KeysRefreshInterval(100 * time.Millisecond).
+			KeysReloadInterval(0).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// send sends a request and returns the response code:
+		send := func() int {
+			request := httptest.NewRequest(http.MethodGet, "/private", nil)
+			request.Header.Set("Authorization", "Bearer "+bearer)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		// Verify that the token is initially accepted:
+		Expect(send()).To(Equal(http.StatusOK))
+
+		// Retire the key and verify that eventually the token is rejected:
+		body.Store([]byte(`{"keys": []}`))
+		Eventually(send, 5*time.Second, 50*time.Millisecond).Should(
+			Equal(http.StatusUnauthorized),
+		)
+	})
+
+	It("Honors the cache control header of the keys response", func() {
+		// Prepare the server that will return the keys:
+		server := NewTLSServer()
+		defer server.Close()
+		server.RouteToHandler(
+			http.MethodGet,
+			"/",
+			RespondWith(
+				http.StatusOK,
+				keysBytes,
+				http.Header{
+					"Cache-Control": []string{"public, max-age=1"},
+				},
+			),
+		)
+
+		// Prepare the next handler:
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		// Prepare the token:
+		bearer := IssueBearer(nil)
+
+		// Prepare the handler:
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysURL(server.URL()).
+			KeysInsecure(true).
+			KeysReloadInterval(0).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// send sends a request and returns the response code:
+		send := func() int {
+			request := httptest.NewRequest(http.MethodGet, "/private", nil)
+			request.Header.Set("Authorization", "Bearer "+bearer)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		// Send a request, wait till the maximum age expires, and verify that sending
+		// another request triggers a refresh, even if the default interval is much longer:
+		Expect(send()).To(Equal(http.StatusOK))
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+		time.Sleep(1100 * time.Millisecond)
+		Expect(send()).To(Equal(http.StatusOK))
+		Eventually(server.ReceivedRequests).Should(HaveLen(2))
+	})
+
+	It("Rate limits reloads caused by unknown keys", func() {
+		// Prepare the server that will return the keys:
+		server := NewTLSServer()
+		defer server.Close()
+		server.RouteToHandler(
+			http.MethodGet,
+			"/",
+			RespondWith(http.StatusOK, keysBytes),
+		)
+
+		// Prepare the next handler:
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		// Prepare the handler:
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysURL(server.URL()).
+			KeysInsecure(true).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Send multiple requests with tokens signed with an unknown key:
+		for i := 0; i < 10; i++ {
+			token := IssueToken(nil)
+			token.Header["kid"] = "456"
+			token.Raw, err = token.SignedString(privateKey)
+			Expect(err).ToNot(HaveOccurred())
+			request := httptest.NewRequest(http.MethodGet, "/private", nil)
+			request.Header.Set("Authorization", "Bearer "+token.Raw)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		}
+
+		// Verify that the keys were loaded only once:
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Can't be built with invalid keys refresh interval", func() {
+		// Prepare the next handler:
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		// Try to create the handler:
+		_, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			KeysRefreshInterval(0).
+			Next(next).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("refresh interval"))
+	})
+
+	Describe("Key types", func() {
+		// Next handler used by these tests:
+		var next http.Handler
+
+		BeforeEach(func() {
+			next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+		})
+
+		// writeKeys writes the given JSON web key set to a temporary file and returns the name
+		// of the file:
+		writeKeys := func(keys string) string {
+			file, err := ioutil.TempFile("", "jwks-*.json")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = file.WriteString(keys)
+			Expect(err).ToNot(HaveOccurred())
+			err = file.Close()
+			Expect(err).ToNot(HaveOccurred())
+			return file.Name()
+		}
+
+		// issueBearer issues a token with the default claims, signed with the given method
+		// and key:
+		issueBearer := func(method jwt.SigningMethod, kid string, key interface{}) string {
+			token := jwt.NewWithClaims(method, DefaultClaims())
+			token.Header["kid"] = kid
+			bearer, err := token.SignedString(key)
+			Expect(err).ToNot(HaveOccurred())
+			return bearer
+		}
+
+		// ecKey returns the JSON representation of an elliptic curve public key:
+		ecKey := func(kid, alg, crv string, key *ecdsa.PrivateKey) string {
+			size := (key.Curve.Params().BitSize + 7) / 8
+			x := make([]byte, size)
+			y := make([]byte, size)
+			xb := key.X.Bytes()
+			yb := key.Y.Bytes()
+			copy(x[size-len(xb):], xb)
+			copy(y[size-len(yb):], yb)
+			return fmt.Sprintf(
+				`{
+					"kid": "%s",
+					"kty": "EC",
+					"alg": "%s",
+					"use": "sig",
+					"crv": "%s",
+					"x": "%s",
+					"y": "%s"
+				}`,
+				kid, alg, crv,
+				base64.RawURLEncoding.EncodeToString(x),
+				base64.RawURLEncoding.EncodeToString(y),
+			)
+		}
+
+		// send sends a request with the given bearer token to the handler and returns the
+		// response code:
+		send := func(keys, bearer string) int {
+			file := writeKeys(keys)
+			defer os.Remove(file) // nolint
+			handler, err := NewHandler().
+				Logger(logger).
+				Service("clusters_mgmt").
+				Version("v1").
+				KeysFile(file).
+				Next(next).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			request := httptest.NewRequest(http.MethodGet, "/private", nil)
+			request.Header.Set("Authorization", "Bearer "+bearer)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		It("Accepts token signed with P-256 key", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			keys := fmt.Sprintf(`{"keys": [%s]}`, ecKey("456", "ES256", "P-256", key))
+			bearer := issueBearer(jwt.SigningMethodES256, "456", key)
+			Expect(send(keys, bearer)).To(Equal(http.StatusOK))
+		})
+
+		It("Accepts token signed with P-384 key", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			keys := fmt.Sprintf(`{"keys": [%s]}`, ecKey("456", "ES384", "P-384", key))
+			bearer := issueBearer(jwt.SigningMethodES384, "456", key)
+			Expect(send(keys, bearer)).To(Equal(http.StatusOK))
+		})
+
+		It("Accepts token signed with P-521 key", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			keys := fmt.Sprintf(`{"keys": [%s]}`, ecKey("456", "ES512", "P-521", key))
+			bearer := issueBearer(jwt.SigningMethodES512, "456", key)
+			Expect(send(keys, bearer)).To(Equal(http.StatusOK))
+		})
+
+		It("Accepts token signed with Ed25519 key", func() {
+			public, private, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			keys := fmt.Sprintf(
+				`{
+					"keys": [{
+						"kid": "456",
+						"kty": "OKP",
+						"alg": "EdDSA",
+						"use": "sig",
+						"crv": "Ed25519",
+						"x": "%s"
+					}]
+				}`,
+				base64.RawURLEncoding.EncodeToString(public),
+			)
+			bearer := issueBearer(internal.SigningMethodEdDSA, "456", private)
+			Expect(send(keys, bearer)).To(Equal(http.StatusOK))
+		})
+
+		It("Rejects token with algorithm that doesn't match the key", func() {
+			// The signature is valid, but the key is declared for RS256 only:
+			bearer := issueBearer(jwt.SigningMethodPS256, "123", privateKey)
+			Expect(send(string(keysBytes), bearer)).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Rejects token signed with key of other type", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			keys := fmt.Sprintf(`{"keys": [%s]}`, ecKey("456", "ES256", "P-256", key))
+			bearer := issueBearer(jwt.SigningMethodRS256, "456", privateKey)
+			Expect(send(keys, bearer)).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Rejects token signed with key that isn't intended for signatures", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			keys := fmt.Sprintf(
+				`{"keys": [%s]}`,
+				strings.Replace(
+					ecKey("456", "ES256", "P-256", key),
+					`"use": "sig"`, `"use": "enc"`, 1,
+				),
+			)
+			bearer := issueBearer(jwt.SigningMethodES256, "456", key)
+			Expect(send(keys, bearer)).To(Equal(http.StatusUnauthorized))
+		})
+
+		It("Ignores keys with unsupported curve", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			keys := fmt.Sprintf(`{"keys": [%s]}`, ecKey("456", "ES256", "secp256k1", key))
+			bearer := issueBearer(jwt.SigningMethodES256, "456", key)
+			Expect(send(keys, bearer)).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
 	It("Returns the response of the next handler", func() {
 		// Prepare the token:
 		bearer := IssueBearer(nil)
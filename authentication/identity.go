@@ -0,0 +1,291 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used to extract the identity of the caller from the
+// claims of the token.
+
+package authentication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/openshift-online/ocm-sdk-go"
+)
+
+// Identity contains the details of the authenticated caller, extracted from the claims of the
+// token according to the claim mapping configured in the handler.
+type Identity struct {
+	// Subject is the value of the `sub` claim.
+	Subject string
+
+	// Username is the name of the user.
+	Username string
+
+	// Email is the email address of the user.
+	Email string
+
+	// AccountID is the identifier of the account of the user.
+	AccountID string
+
+	// OrgID is the identifier of the organization of the user.
+	OrgID string
+
+	// Roles contains the roles assigned to the user.
+	Roles []string
+
+	// Claims contains all the claims of the token, for the details that aren't part of the
+	// mapping.
+	Claims jwt.MapClaims
+}
+
+// HasRole returns true if the identity has the given role.
+func (i *Identity) HasRole(role string) bool {
+	for _, current := range i.Roles {
+		if current == role {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns a short description of the identity, suitable for log messages. It is the user
+// name if available, and the subject otherwise.
+func (i *Identity) String() string {
+	if i.Username != "" {
+		return i.Username
+	}
+	return i.Subject
+}
+
+// ClaimMapping describes which claims of the token contain the details of the identity of the
+// caller. Claim names may contain dots to navigate nested objects, for example
+// `realm_access.roles`. Empty claim names mean that the detail isn't available.
+type ClaimMapping struct {
+	// Username is the claim that contains the name of the user.
+	Username string
+
+	// Email is the claim that contains the email address of the user.
+	Email string
+
+	// AccountID is the claim that contains the identifier of the account.
+	AccountID string
+
+	// OrgID is the claim that contains the identifier of the organization.
+	OrgID string
+
+	// Roles are the claims that contain the roles of the user. The values of all these claims
+	// are combined. Each claim can be a single string or an array of strings.
+	Roles []string
+}
+
+// RedHatSSOClaims is the claim mapping for tokens issued by the Red Hat SSO service. This is the
+// default.
+var RedHatSSOClaims = ClaimMapping{
+	Username:  "username",
+	Email:     "email",
+	AccountID: "account_id",
+	OrgID:     "org_id",
+	Roles: []string{
+		"realm_access.roles",
+	},
+}
+
+// KeycloakClaims is the claim mapping for tokens issued by a generic Keycloak server.
+var KeycloakClaims = ClaimMapping{
+	Username: "preferred_username",
+	Email:    "email",
+	Roles: []string{
+		"realm_access.roles",
+	},
+}
+
+// copy returns a deep copy of the claim mapping.
+func (m ClaimMapping) copy() *ClaimMapping {
+	result := m
+	result.Roles = make([]string, len(m.Roles))
+	copy(result.Roles, m.Roles)
+	return &result
+}
+
+// identity creates the identity corresponding to the given claims.
+func (m *ClaimMapping) identity(claims jwt.MapClaims) *Identity {
+	result := &Identity{
+		Subject:   stringClaim(claims, "sub"),
+		Username:  stringClaim(claims, m.Username),
+		Email:     stringClaim(claims, m.Email),
+		AccountID: stringClaim(claims, m.AccountID),
+		OrgID:     stringClaim(claims, m.OrgID),
+		Claims:    claims,
+	}
+	for _, name := range m.Roles {
+		value, ok := lookupClaim(claims, name)
+		if !ok {
+			continue
+		}
+		switch typed := value.(type) {
+		case string:
+			result.Roles = append(result.Roles, typed)
+		case []interface{}:
+			for _, item := range typed {
+				text, ok := item.(string)
+				if ok {
+					result.Roles = append(result.Roles, text)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// stringClaim returns the value of the given claim if it exists and it is a string or a number.
+// Otherwise it returns an empty string.
+func stringClaim(claims jwt.MapClaims, name string) string {
+	if name == "" {
+		return ""
+	}
+	value, ok := lookupClaim(claims, name)
+	if !ok {
+		return ""
+	}
+	switch value.(type) {
+	case string, float64:
+		return fmt.Sprintf("%v", value)
+	default:
+		return ""
+	}
+}
+
+// lookupClaim returns the value of the given claim. The name may contain dots to navigate nested
+// objects.
+func lookupClaim(claims jwt.MapClaims, name string) (value interface{}, ok bool) {
+	value = map[string]interface{}(claims)
+	for _, segment := range strings.Split(name, ".") {
+		var object map[string]interface{}
+		object, ok = value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		value, ok = object[segment]
+		if !ok {
+			return
+		}
+	}
+	return
+}
+
+// ContextWithIdentity creates a new context containing the given identity.
+func ContextWithIdentity(parent context.Context, identity *Identity) context.Context {
+	return context.WithValue(parent, identityKeyValue, identity)
+}
+
+// IdentityFromContext extracts the identity of the caller from the context. If no identity is
+// found in the context then the result will be nil.
+func IdentityFromContext(ctx context.Context) (result *Identity, err error) {
+	switch identity := ctx.Value(identityKeyValue).(type) {
+	case nil:
+	case *Identity:
+		result = identity
+	default:
+		err = fmt.Errorf(
+			"expected an identity in the '%s' context value, but got '%T'",
+			identityKeyValue, identity,
+		)
+	}
+	return
+}
+
+// identityKeyValue is the key used to store the identity in the context:
+const identityKeyValue tokenKeyType = "identity"
+
+// IdentityLogger is a logger that adds to the messages the identity of the caller, when the
+// context contains it. The rest of the work is delegated to another logger.
+type IdentityLogger struct {
+	next sdk.Logger
+}
+
+// Make sure that we implement the interface:
+var _ sdk.Logger = &IdentityLogger{}
+
+// NewIdentityLogger creates a logger that adds to the messages the identity of the caller found in
+// the context, and then sends them to the given logger. For example, if the user name of the
+// caller is `myuser` then the message `Cluster created` will be sent as
+// `[myuser] Cluster created`.
+func NewIdentityLogger(next sdk.Logger) *IdentityLogger {
+	return &IdentityLogger{
+		next: next,
+	}
+}
+
+// DebugEnabled returns true iff the debug level is enabled.
+func (l *IdentityLogger) DebugEnabled() bool {
+	return l.next.DebugEnabled()
+}
+
+// InfoEnabled returns true iff the information level is enabled.
+func (l *IdentityLogger) InfoEnabled() bool {
+	return l.next.InfoEnabled()
+}
+
+// WarnEnabled returns true iff the warning level is enabled.
+func (l *IdentityLogger) WarnEnabled() bool {
+	return l.next.WarnEnabled()
+}
+
+// ErrorEnabled returns true iff the error level is enabled.
+func (l *IdentityLogger) ErrorEnabled() bool {
+	return l.next.ErrorEnabled()
+}
+
+// Debug sends to the log a debug message formatted using the fmt.Sprintf function and the given
+// format and arguments.
+func (l *IdentityLogger) Debug(ctx context.Context, format string, args ...interface{}) {
+	l.next.Debug(ctx, l.format(ctx, format), args...)
+}
+
+// Info sends to the log an information message formatted using the fmt.Sprintf function and the
+// given format and arguments.
+func (l *IdentityLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	l.next.Info(ctx, l.format(ctx, format), args...)
+}
+
+// Warn sends to the log a warning message formatted using the fmt.Sprintf function and the given
+// format and arguments.
+func (l *IdentityLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	l.next.Warn(ctx, l.format(ctx, format), args...)
+}
+
+// Error sends to the log an error message formatted using the fmt.Sprintf function and the given
+// format and arguments.
+func (l *IdentityLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	l.next.Error(ctx, l.format(ctx, format), args...)
+}
+
+// format adds the identity of the caller to the given format, if the context contains it.
+func (l *IdentityLogger) format(ctx context.Context, format string) string {
+	if ctx == nil {
+		return format
+	}
+	identity, err := IdentityFromContext(ctx)
+	if err != nil || identity == nil {
+		return format
+	}
+	caller := strings.ReplaceAll(identity.String(), "%", "%%")
+	return "[" + caller + "] " + format
+}
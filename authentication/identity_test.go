@@ -0,0 +1,191 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the extraction of the identity of the caller.
+
+package authentication
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/openshift-online/ocm-sdk-go"
+)
+
+var _ = Describe("Identity", func() {
+	// Identity found by the next handler in the context:
+	var identity *Identity
+
+	// Next handler used by the tests:
+	var next http.Handler
+
+	BeforeEach(func() {
+		identity = nil
+		next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			identity, err = IdentityFromContext(r.Context())
+			Expect(err).ToNot(HaveOccurred())
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+
+	// send sends a request with a token containing the given claims to the handler:
+	send := func(handler *Handler, claims jwt.MapClaims) {
+		request := httptest.NewRequest(http.MethodGet, "/private", nil)
+		request.Header.Set("Authorization", "Bearer "+IssueBearer(claims))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+	}
+
+	It("Uses the Red Hat SSO claims by default", func() {
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		send(handler, jwt.MapClaims{
+			"sub":        "mysubject",
+			"username":   "myuser",
+			"email":      "myuser@example.com",
+			"account_id": "123",
+			"org_id":     "456",
+			"realm_access": map[string]interface{}{
+				"roles": []interface{}{"admin", "viewer"},
+			},
+		})
+		Expect(identity).ToNot(BeNil())
+		Expect(identity.Subject).To(Equal("mysubject"))
+		Expect(identity.Username).To(Equal("myuser"))
+		Expect(identity.Email).To(Equal("myuser@example.com"))
+		Expect(identity.AccountID).To(Equal("123"))
+		Expect(identity.OrgID).To(Equal("456"))
+		Expect(identity.Roles).To(ConsistOf("admin", "viewer"))
+		Expect(identity.HasRole("admin")).To(BeTrue())
+		Expect(identity.HasRole("editor")).To(BeFalse())
+		Expect(identity.Claims["sub"]).To(Equal("mysubject"))
+	})
+
+	It("Uses the configured claim mapping", func() {
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			ClaimMapping(KeycloakClaims).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		send(handler, jwt.MapClaims{
+			"sub":                "mysubject",
+			"username":           "ignored",
+			"preferred_username": "myuser",
+			"realm_access": map[string]interface{}{
+				"roles": []interface{}{"admin"},
+			},
+		})
+		Expect(identity).ToNot(BeNil())
+		Expect(identity.Username).To(Equal("myuser"))
+		Expect(identity.AccountID).To(BeEmpty())
+		Expect(identity.Roles).To(ConsistOf("admin"))
+	})
+
+	It("Combines roles from multiple claims", func() {
+		mapping := KeycloakClaims
+		mapping.Roles = []string{
+			"realm_access.roles",
+			"resource_access.myclient.roles",
+		}
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			ClaimMapping(mapping).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		send(handler, jwt.MapClaims{
+			"realm_access": map[string]interface{}{
+				"roles": []interface{}{"admin"},
+			},
+			"resource_access": map[string]interface{}{
+				"myclient": map[string]interface{}{
+					"roles": []interface{}{"cluster-creator"},
+				},
+			},
+		})
+		Expect(identity).ToNot(BeNil())
+		Expect(identity.Roles).To(ConsistOf("admin", "cluster-creator"))
+	})
+
+	It("Succeeds if there is no identity in the context", func() {
+		result, err := IdentityFromContext(context.TODO())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+	})
+})
+
+var _ = Describe("Identity logger", func() {
+	// Buffer where the messages are written:
+	var buffer *bytes.Buffer
+
+	// Logger used by the tests:
+	var identityLogger *IdentityLogger
+
+	BeforeEach(func() {
+		buffer = &bytes.Buffer{}
+		next, err := sdk.NewStdLoggerBuilder().
+			Streams(buffer, buffer).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		identityLogger = NewIdentityLogger(next)
+	})
+
+	It("Adds the user name to the messages", func() {
+		ctx := ContextWithIdentity(context.TODO(), &Identity{
+			Subject:  "mysubject",
+			Username: "myuser",
+		})
+		identityLogger.Info(ctx, "Created cluster '%s'", "mycluster")
+		Expect(buffer.String()).To(Equal("[myuser] Created cluster 'mycluster'\n"))
+	})
+
+	It("Uses the subject if there is no user name", func() {
+		ctx := ContextWithIdentity(context.TODO(), &Identity{
+			Subject: "my%subject",
+		})
+		identityLogger.Warn(ctx, "Failed")
+		Expect(buffer.String()).To(Equal("[my%subject] Failed\n"))
+	})
+
+	It("Doesn't change the messages if there is no identity", func() {
+		identityLogger.Debug(context.TODO(), "Hello %s", "world")
+		identityLogger.Warn(nil, "Bye") // nolint
+		Expect(buffer.String()).To(Equal("Hello world\nBye\n"))
+	})
+})
@@ -0,0 +1,270 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the token introspection support described in RFC 7662,
+// used to check if tokens have been revoked and to verify opaque tokens.
+
+package authentication
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Default values for the introspection cache:
+const (
+	defaultIntrospectionCacheSize = 1000
+	defaultIntrospectionCacheTTL  = 1 * time.Minute
+)
+
+// introspectionResult is the result of the introspection of a token.
+type introspectionResult struct {
+	// active indicates if the server considers the token active.
+	active bool
+
+	// claims contains the fields of the introspection response. For opaque tokens these are used
+	// instead of the claims of the token.
+	claims jwt.MapClaims
+}
+
+// introspectionCache is a cache of introspection results, indexed by the hash of the token. When
+// the cache is full the least recently used results are discarded.
+type introspectionCache struct {
+	mutex   *sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List
+	entries map[[sha256.Size]byte]*list.Element
+}
+
+// introspectionCacheEntry is the type of the elements of the introspection cache.
+type introspectionCacheEntry struct {
+	key    [sha256.Size]byte
+	result *introspectionResult
+	expiry time.Time
+}
+
+// newIntrospectionCache creates a cache with the given maximum number of entries and time to live.
+func newIntrospectionCache(size int, ttl time.Duration) *introspectionCache {
+	return &introspectionCache{
+		mutex:   &sync.Mutex{},
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: map[[sha256.Size]byte]*list.Element{},
+	}
+}
+
+// get returns the cached result for the given token, or nil if there is no such result or it has
+// expired.
+func (c *introspectionCache) get(token string) *introspectionResult {
+	key := sha256.Sum256([]byte(token))
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := element.Value.(*introspectionCacheEntry)
+	if time.Now().After(entry.expiry) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil
+	}
+	c.order.MoveToFront(element)
+	return entry.result
+}
+
+// put adds the result for the given token to the cache. The result will expire when the time to
+// live of the cache expires or when the token expires, whatever happens first.
+func (c *introspectionCache) put(token string, result *introspectionResult) {
+	expiry := time.Now().Add(c.ttl)
+	if exp, ok := result.claims["exp"].(float64); ok {
+		tokenExpiry := time.Unix(int64(exp), 0)
+		if tokenExpiry.Before(expiry) {
+			expiry = tokenExpiry
+		}
+	}
+	key := sha256.Sum256([]byte(token))
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if ok {
+		entry := element.Value.(*introspectionCacheEntry)
+		entry.result = result
+		entry.expiry = expiry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&introspectionCacheEntry{
+		key:    key,
+		result: result,
+		expiry: expiry,
+	})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*introspectionCacheEntry).key)
+	}
+}
+
+// introspect sends the given token to the introspection endpoint and returns the result, using the
+// cache when possible.
+func (h *Handler) introspect(ctx context.Context, token string) (result *introspectionResult,
+	err error) {
+	// Try the cache first:
+	if h.introspectionCache != nil {
+		result = h.introspectionCache.get(token)
+		if result != nil {
+			return
+		}
+	}
+
+	// Send the request:
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", "access_token")
+	request, err := http.NewRequest(
+		http.MethodPost,
+		h.introspectionURL,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return
+	}
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if h.introspectionClientID != "" {
+		request.SetBasicAuth(
+			url.QueryEscape(h.introspectionClientID),
+			url.QueryEscape(h.introspectionClientSecret),
+		)
+	}
+	response, err := h.keysClient.Do(request)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err := response.Body.Close()
+		if err != nil {
+			h.logger.Error(
+				ctx,
+				"Can't close response body for request to '%s': %v",
+				h.introspectionURL, err,
+			)
+		}
+	}()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("response status is: %s", response.Status)
+		return
+	}
+
+	// Parse the response:
+	claims := jwt.MapClaims{}
+	err = json.Unmarshal(body, &claims)
+	if err != nil {
+		err = fmt.Errorf("can't parse introspection response: %v", err)
+		return
+	}
+	active, _ := claims["active"].(bool)
+	result = &introspectionResult{
+		active: active,
+		claims: claims,
+	}
+
+	// Save the result in the cache:
+	if h.introspectionCache != nil {
+		h.introspectionCache.put(token, result)
+	}
+
+	return
+}
+
+// checkIntrospection checks with the introspection endpoint that the given token is active. If it
+// isn't active, or it can't be checked, it sends an error response to the client and returns false.
+// If it is active it returns the introspection result and true.
+func (h *Handler) checkIntrospection(w http.ResponseWriter, r *http.Request,
+	bearer string) (result *introspectionResult, ok bool) {
+	ctx := r.Context()
+	result, err := h.introspect(ctx, bearer)
+	if err != nil {
+		h.logger.Error(
+			ctx,
+			"Can't introspect token using '%s': %v",
+			h.introspectionURL, err,
+		)
+		h.sendError(
+			w, r,
+			"Bearer token can't be verified",
+		)
+		return
+	}
+	if !result.active {
+		h.sendError(
+			w, r,
+			"Bearer token isn't active",
+		)
+		return
+	}
+	ok = true
+	return
+}
+
+// checkOpaqueToken checks a bearer token that isn't a JSON web token, using only the introspection
+// endpoint. If it is valid it returns a token object containing the claims returned by the
+// introspection endpoint, and true. If it isn't valid it sends an error response to the client and
+// returns false.
+func (h *Handler) checkOpaqueToken(w http.ResponseWriter, r *http.Request,
+	bearer string) (token *jwt.Token, claims jwt.MapClaims, ok bool) {
+	result, ok := h.checkIntrospection(w, r, bearer)
+	if !ok {
+		return
+	}
+	claims = result.claims
+	ok = h.checkRestrictions(w, r, claims)
+	if !ok {
+		return
+	}
+	token = &jwt.Token{
+		Raw:    bearer,
+		Header: map[string]interface{}{},
+		Claims: claims,
+		Valid:  true,
+	}
+	return
+}
+
+// isJWT checks if the given bearer token looks like a JSON web token, that is three base64
+// encoded segments separated by dots.
+func isJWT(bearer string) bool {
+	return strings.Count(bearer, ".") == 2
+}
@@ -0,0 +1,290 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the token introspection support of the authentication handler.
+
+package authentication
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Introspection", func() {
+	// Server that implements the introspection endpoint:
+	var server *ghttp.Server
+
+	// Token found by the next handler in the context:
+	var token *jwt.Token
+
+	// Next handler used by the tests:
+	var next http.Handler
+
+	BeforeEach(func() {
+		server = ghttp.NewTLSServer()
+		token = nil
+		next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			token, err = TokenFromContext(r.Context())
+			Expect(err).ToNot(HaveOccurred())
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	// builder returns a handler builder configured to use the introspection server:
+	builder := func() *HandlerBuilder {
+		return NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			KeysInsecure(true).
+			IntrospectionURL(server.URL()+"/introspect").
+			IntrospectionClient("myclient", "mysecret").
+			Next(next)
+	}
+
+	// send sends a request with the given bearer token and returns the response recorder:
+	send := func(handler *Handler, bearer string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/private", nil)
+		request.Header.Set("Authorization", "Bearer "+bearer)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// respond returns a handler that responds with the given introspection result:
+	respond := func(body string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest(http.MethodPost, "/introspect"),
+			ghttp.VerifyBasicAuth("myclient", "mysecret"),
+			ghttp.VerifyContentType("application/x-www-form-urlencoded"),
+			ghttp.VerifyFormKV("token_type_hint", "access_token"),
+			ghttp.RespondWith(http.StatusOK, body, http.Header{
+				"Content-Type": []string{"application/json"},
+			}),
+		)
+	}
+
+	It("Accepts active token", func() {
+		bearer := IssueBearer(nil)
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("token", bearer),
+				respond(`{"active": true}`),
+			),
+		)
+		handler, err := builder().Build()
+		Expect(err).ToNot(HaveOccurred())
+		recorder := send(handler, bearer)
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(token).ToNot(BeNil())
+		Expect(token.Raw).To(Equal(bearer))
+	})
+
+	It("Rejects revoked token", func() {
+		server.AppendHandlers(respond(`{"active": false}`))
+		handler, err := builder().Build()
+		Expect(err).ToNot(HaveOccurred())
+		recorder := send(handler, IssueBearer(nil))
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"kind": "Error",
+			"id": "401",
+			"href": "/api/clusters_mgmt/v1/errors/401",
+			"code": "CLUSTERS-MGMT-401",
+			"reason": "Bearer token isn't active"
+		}`))
+	})
+
+	It("Doesn't introspect token with invalid signature", func() {
+		handler, err := builder().Build()
+		Expect(err).ToNot(HaveOccurred())
+		recorder := send(handler, IssueBearer(nil)+"x")
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+
+	It("Rejects token if the introspection endpoint fails", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusInternalServerError, nil),
+		)
+		handler, err := builder().Build()
+		Expect(err).ToNot(HaveOccurred())
+		recorder := send(handler, IssueBearer(nil))
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"kind": "Error",
+			"id": "401",
+			"href": "/api/clusters_mgmt/v1/errors/401",
+			"code": "CLUSTERS-MGMT-401",
+			"reason": "Bearer token can't be verified"
+		}`))
+	})
+
+	It("Caches introspection results", func() {
+		server.AppendHandlers(respond(`{"active": true}`))
+		handler, err := builder().Build()
+		Expect(err).ToNot(HaveOccurred())
+		bearer := IssueBearer(nil)
+		for i := 0; i < 3; i++ {
+			recorder := send(handler, bearer)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		}
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Discards cached results when the TTL expires", func() {
+		server.AppendHandlers(
+			respond(`{"active": true}`),
+			respond(`{"active": false}`),
+		)
+		handler, err := builder().
+			IntrospectionCacheTTL(100 * time.Millisecond).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		bearer := IssueBearer(nil)
+		Expect(send(handler, bearer).Code).To(Equal(http.StatusOK))
+		time.Sleep(200 * time.Millisecond)
+		Expect(send(handler, bearer).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("Doesn't cache results if the cache is disabled", func() {
+		server.AppendHandlers(
+			respond(`{"active": true}`),
+			respond(`{"active": true}`),
+		)
+		handler, err := builder().
+			IntrospectionCacheSize(0).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		bearer := IssueBearer(nil)
+		Expect(send(handler, bearer).Code).To(Equal(http.StatusOK))
+		Expect(send(handler, bearer).Code).To(Equal(http.StatusOK))
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Accepts opaque token", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyFormKV("token", "myopaque"),
+				respond(`{
+					"active": true,
+					"sub": "mysubject",
+					"scope": "openid api.clusters"
+				}`),
+			),
+		)
+		handler, err := builder().
+			RequiredScopes("api.clusters").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		recorder := send(handler, "myopaque")
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(token).ToNot(BeNil())
+		Expect(token.Raw).To(Equal("myopaque"))
+		claims, ok := token.Claims.(jwt.MapClaims)
+		Expect(ok).To(BeTrue())
+		Expect(claims["sub"]).To(Equal("mysubject"))
+	})
+
+	It("Checks the restrictions of opaque tokens", func() {
+		server.AppendHandlers(respond(`{"active": true, "scope": "openid"}`))
+		handler, err := builder().
+			RequiredScopes("api.clusters").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		recorder := send(handler, "myopaque")
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"kind": "Error",
+			"id": "401",
+			"href": "/api/clusters_mgmt/v1/errors/401",
+			"code": "CLUSTERS-MGMT-401",
+			"reason": "Bearer token doesn't have required scope 'api.clusters'"
+		}`))
+	})
+
+	It("Rejects opaque token if introspection isn't enabled", func() {
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		recorder := send(handler, "myopaque")
+		Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("Can be built with only the introspection URL", func() {
+		_, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			IntrospectionURL(server.URL()).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Can't be built with an introspection URL that isn't HTTPS", func() {
+		_, err := builder().
+			IntrospectionURL("http://sso.example.com/introspect").
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("HTTPS"))
+	})
+})
+
+var _ = Describe("Introspection cache", func() {
+	It("Discards the least recently used results", func() {
+		cache := newIntrospectionCache(2, time.Minute)
+		first := &introspectionResult{active: true}
+		second := &introspectionResult{active: true}
+		third := &introspectionResult{active: false}
+		cache.put("first", first)
+		cache.put("second", second)
+		Expect(cache.get("first")).To(BeIdenticalTo(first))
+		cache.put("third", third)
+		Expect(cache.get("first")).To(BeIdenticalTo(first))
+		Expect(cache.get("second")).To(BeNil())
+		Expect(cache.get("third")).To(BeIdenticalTo(third))
+	})
+
+	It("Doesn't keep results after the token expires", func() {
+		cache := newIntrospectionCache(2, time.Minute)
+		cache.put("expired", &introspectionResult{
+			active: true,
+			claims: jwt.MapClaims{
+				"exp": float64(time.Now().Add(-time.Second).Unix()),
+			},
+		})
+		Expect(cache.get("expired")).To(BeNil())
+	})
+})
@@ -0,0 +1,336 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the logic that loads and refreshes the keys used by the authentication
+// handler to verify the signatures of tokens.
+
+package authentication
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/openshift-online/ocm-sdk-go/internal"
+)
+
+// Default values for the intervals used to refresh the keys:
+const (
+	defaultKeysRefreshInterval = 1 * time.Hour
+	defaultKeysReloadInterval  = 1 * time.Minute
+)
+
+// selectKey selects the key that should be used to verify the given token.
+func (h *Handler) selectKey(ctx context.Context, token *jwt.Token) (result interface{},
+	err error) {
+	// Get the key identifier:
+	value, ok := token.Header["kid"]
+	if !ok {
+		err = fmt.Errorf("token doesn't have a 'kid' field in the header")
+		return
+	}
+	kid, ok := value.(string)
+	if !ok {
+		err = fmt.Errorf(
+			"token has a 'kid' field, but it is a %T instead of a string",
+			value,
+		)
+		return
+	}
+
+	// Get the key for that key identifier. If the key exists but the keys need to be refreshed
+	// we do it in the background, so that the request isn't delayed. If the key doesn't exist
+	// we try to reload the keys now, but only if we didn't do it recently, to avoid sending a
+	// flood of requests to the server when we receive tokens signed with unknown keys.
+	key, ok, expired := h.lookupKey(kid)
+	if ok {
+		if expired {
+			h.refreshKeys(context.Background(), keysReasonExpired, false)
+		}
+	} else {
+		h.refreshKeys(ctx, keysReasonUnknown, true)
+		key, ok, _ = h.lookupKey(kid)
+		if !ok {
+			err = fmt.Errorf("there is no key for key identifier '%s'", kid)
+			return
+		}
+	}
+
+	// Check that the key is intended for signatures and for the algorithm used by the token,
+	// otherwise a token could, for example, be signed with HMAC using a public key as the
+	// secret:
+	err = key.Check(token)
+	if err != nil {
+		return
+	}
+	result = key.Public
+
+	return
+}
+
+// lookupKey returns the key with the given identifier, and a flag indicating if the keys need to
+// be refreshed.
+func (h *Handler) lookupKey(kid string) (key *internal.Key, ok, expired bool) {
+	h.keysMutex.Lock()
+	defer h.keysMutex.Unlock()
+	key, ok = h.keys[kid]
+	expired = time.Now().After(h.keysExpiry)
+	return
+}
+
+// refreshKeys reloads the keys from the files and URLs given in the configuration. If a reload
+// is already in progress it doesn't start a new one, and if wait is true it waits till the one in
+// progress finishes. Reloads triggered by unknown key identifiers are rate limited.
+func (h *Handler) refreshKeys(ctx context.Context, reason string, wait bool) {
+	h.keysMutex.Lock()
+	if h.keysLoad != nil {
+		load := h.keysLoad
+		h.keysMutex.Unlock()
+		if wait {
+			<-load
+		}
+		return
+	}
+	now := time.Now()
+	if reason == keysReasonUnknown && now.Sub(h.lastKeyReload) < h.keysReloadInterval {
+		h.keysMutex.Unlock()
+		h.logger.Debug(
+			ctx,
+			"Keys were reloaded less than %s ago, will not reload them again",
+			h.keysReloadInterval,
+		)
+		h.updateKeysMetric(reason, keysResultRateLimited)
+		return
+	}
+	load := make(chan struct{})
+	h.keysLoad = load
+	h.lastKeyReload = now
+	h.keysMutex.Unlock()
+	run := func() {
+		defer close(load)
+		h.loadKeys(ctx, reason)
+		h.keysMutex.Lock()
+		h.keysLoad = nil
+		h.keysMutex.Unlock()
+	}
+	if wait {
+		run()
+	} else {
+		go run()
+	}
+}
+
+// loadKeys loads the JSON web key sets from the files and URLs specified in the configuration,
+// and replaces the current keys with them. Keys that are no longer present in a source are
+// removed. When a source fails the keys previously loaded from it are preserved.
+func (h *Handler) loadKeys(ctx context.Context, reason string) {
+	interval := h.keysRefreshInterval
+
+	// Load keys from the files given in the configuration:
+	loaded := map[string]map[string]*internal.Key{}
+	for _, keysFile := range h.keysFiles {
+		h.logger.Info(ctx, "Loading keys from file '%s'", keysFile)
+		keys, err := h.loadKeysFile(ctx, keysFile)
+		if err != nil {
+			h.logger.Error(ctx, "Can't load keys from file '%s': %v", keysFile, err)
+			h.updateKeysMetric(reason, keysResultFailure)
+			continue
+		}
+		loaded[keysFile] = keys
+		h.updateKeysMetric(reason, keysResultSuccess)
+	}
+
+	// Load keys from URLs given in the configuration, honoring the cache control header
+	// returned by the server:
+	for _, keysURL := range h.keysURLs {
+		h.logger.Info(ctx, "Loading keys from URL '%s'", keysURL)
+		keys, maxAge, err := h.loadKeysURL(ctx, keysURL)
+		if err != nil {
+			h.logger.Error(ctx, "Can't load keys from URL '%s': %v", keysURL, err)
+			h.updateKeysMetric(reason, keysResultFailure)
+			continue
+		}
+		loaded[keysURL] = keys
+		if maxAge >= 0 && maxAge < interval {
+			interval = maxAge
+		}
+		h.updateKeysMetric(reason, keysResultSuccess)
+	}
+	if interval < h.keysReloadInterval {
+		interval = h.keysReloadInterval
+	}
+
+	// Replace the keys:
+	h.keysMutex.Lock()
+	defer h.keysMutex.Unlock()
+	for source, keys := range loaded {
+		h.keysSources[source] = keys
+	}
+	merged := map[string]*internal.Key{}
+	for _, keys := range h.keysSources {
+		for kid, key := range keys {
+			merged[kid] = key
+		}
+	}
+	for kid := range merged {
+		if _, ok := h.keys[kid]; !ok {
+			h.logger.Info(ctx, "Loaded key '%s'", kid)
+		}
+	}
+	for kid := range h.keys {
+		if _, ok := merged[kid]; !ok {
+			h.logger.Info(ctx, "Removed key '%s'", kid)
+		}
+	}
+	h.keys = merged
+	h.keysExpiry = time.Now().Add(interval)
+	h.logger.Info(
+		ctx,
+		"Loaded %d keys, will refresh them in %s",
+		len(merged), interval,
+	)
+	if h.keysCountMetric != nil {
+		h.keysCountMetric.Set(float64(len(merged)))
+	}
+}
+
+// loadKeysFile loads a JSON we key set from a file.
+func (h *Handler) loadKeysFile(ctx context.Context,
+	file string) (keys map[string]*internal.Key, err error) {
+	reader, err := os.Open(file) // nolint
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	return h.readKeys(ctx, reader)
+}
+
+// loadKeysURL loads a JSON we key set from an URL. It also returns the maximum age from the
+// `Cache-Control` header of the response, or -1 if there is no such header.
+func (h *Handler) loadKeysURL(ctx context.Context, addr string) (keys map[string]*internal.Key,
+	maxAge time.Duration, err error) {
+	request, err := http.NewRequest(http.MethodGet, addr, nil)
+	if err != nil {
+		return
+	}
+	request = request.WithContext(ctx)
+	response, err := h.keysClient.Do(request)
+	if err != nil {
+		return
+	}
+	defer func() {
+		err := response.Body.Close()
+		if err != nil {
+			h.logger.Error(
+				ctx,
+				"Can't close response body for request to '%s': %v",
+				addr, err,
+			)
+		}
+	}()
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("response status is: %s", response.Status)
+		return
+	}
+	maxAge = parseMaxAge(response.Header.Get("Cache-Control"))
+	keys, err = h.readKeys(ctx, response.Body)
+	return
+}
+
+// readKeys reads the keys from JSON web key set available in the given reader.
+func (h *Handler) readKeys(ctx context.Context,
+	reader io.Reader) (keys map[string]*internal.Key, err error) {
+	// Read the JSON data:
+	jsonData, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return
+	}
+
+	// Parse the JSON data:
+	var setData internal.KeySetData
+	err = json.Unmarshal(jsonData, &setData)
+	if err != nil {
+		return
+	}
+
+	// Convert the key data to actual keys that can be used to verify the signatures of the
+	// tokens:
+	keys = map[string]*internal.Key{}
+	for _, keyData := range setData.Keys {
+		if h.logger.DebugEnabled() {
+			h.logger.Debug(ctx, "Value of 'kid' is '%s'", keyData.Kid)
+			h.logger.Debug(ctx, "Value of 'kty' is '%s'", keyData.Kty)
+			h.logger.Debug(ctx, "Value of 'alg' is '%s'", keyData.Alg)
+			h.logger.Debug(ctx, "Value of 'use' is '%s'", keyData.Use)
+			h.logger.Debug(ctx, "Value of 'e' is '%s'", keyData.E)
+			h.logger.Debug(ctx, "Value of 'n' is '%s'", keyData.N)
+			h.logger.Debug(ctx, "Value of 'crv' is '%s'", keyData.Crv)
+			h.logger.Debug(ctx, "Value of 'x' is '%s'", keyData.X)
+			h.logger.Debug(ctx, "Value of 'y' is '%s'", keyData.Y)
+		}
+		err = internal.CheckKey(keyData)
+		if err != nil {
+			h.logger.Error(
+				ctx,
+				"Can't read key '%s' because %v",
+				keyData.Kid, err,
+			)
+			continue
+		}
+		var key *internal.Key
+		key, err = internal.ParseKey(keyData)
+		if err != nil {
+			h.logger.Error(
+				ctx,
+				"Key '%s' will be ignored because it can't be parsed",
+				keyData.Kid,
+			)
+			continue
+		}
+		keys[keyData.Kid] = key
+	}
+	err = nil
+
+	return
+}
+
+// parseMaxAge extracts the maximum age from the value of a `Cache-Control` header. It returns
+// zero if the header contains the `no-cache` or `no-store` directives, and -1 if it doesn't
+// contain any relevant directive.
+func parseMaxAge(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return -1
+}
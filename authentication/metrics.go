@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementations of the Prometheus metrics of the authentication handler.
+
+package authentication
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registerMetrics registers the metrics with the Prometheus library.
+func (h *Handler) registerMetrics(subsystem string) error {
+	var err error
+
+	// Register the keys refresh count metric:
+	h.keysMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "keys_refresh_count",
+			Help:      "Number of attempts to refresh the keys used to verify tokens.",
+		},
+		keysMetricsLabels,
+	)
+	err = prometheus.Register(h.keysMetric)
+	if err != nil {
+		registered, ok := err.(prometheus.AlreadyRegisteredError)
+		if ok {
+			h.keysMetric = registered.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			return err
+		}
+	}
+
+	// Register the keys count metric:
+	h.keysCountMetric = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: subsystem,
+			Name:      "keys_count",
+			Help:      "Number of keys currently used to verify tokens.",
+		},
+	)
+	err = prometheus.Register(h.keysCountMetric)
+	if err != nil {
+		registered, ok := err.(prometheus.AlreadyRegisteredError)
+		if ok {
+			h.keysCountMetric = registered.ExistingCollector.(prometheus.Gauge)
+		} else {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateKeysMetric increases the counter of refreshes of the keys.
+func (h *Handler) updateKeysMetric(reason, result string) {
+	if h.keysMetric == nil {
+		return
+	}
+	h.keysMetric.With(map[string]string{
+		metricsReasonLabel: reason,
+		metricsResultLabel: result,
+	}).Inc()
+}
+
+// Names of the labels added to metrics:
+const (
+	metricsReasonLabel = "reason"
+	metricsResultLabel = "result"
+)
+
+// Array of labels added to the keys metrics:
+var keysMetricsLabels = []string{
+	metricsReasonLabel,
+	metricsResultLabel,
+}
+
+// Values of the reason label of the keys metrics:
+const (
+	keysReasonExpired = "expired"
+	keysReasonUnknown = "unknown_key"
+)
+
+// Values of the result label of the keys metrics:
+const (
+	keysResultSuccess     = "success"
+	keysResultFailure     = "failure"
+	keysResultRateLimited = "rate_limited"
+)
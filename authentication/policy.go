@@ -0,0 +1,415 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used to load and evaluate authorization policies.
+
+package authentication
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ghodss/yaml"
+)
+
+// policyData is the type used to read a policy file.
+type policyData struct {
+	Rules []*policyRuleData `json:"rules"`
+}
+
+// policyRuleData is the type used to read a single rule from a policy file.
+type policyRuleData struct {
+	Name    string               `json:"name"`
+	Effect  string               `json:"effect"`
+	Methods []string             `json:"methods"`
+	Paths   []string             `json:"paths"`
+	When    *policyConditionData `json:"when"`
+}
+
+// policyConditionData is the type used to read a condition from a policy file. Only one of the
+// fields `all`, `any`, `not` or `claim` should be used.
+type policyConditionData struct {
+	All     []*policyConditionData `json:"all"`
+	Any     []*policyConditionData `json:"any"`
+	Not     *policyConditionData   `json:"not"`
+	Claim   string                 `json:"claim"`
+	Pattern string                 `json:"pattern"`
+	Values  []string               `json:"values"`
+}
+
+// Possible values of the effect of a policy rule:
+const (
+	policyEffectAllow = "allow"
+	policyEffectDeny  = "deny"
+)
+
+// policyRule is a rule of an authorization policy, ready to be evaluated.
+type policyRule struct {
+	name      string
+	deny      bool
+	methods   map[string]bool
+	paths     []*regexp.Regexp
+	condition policyCondition
+}
+
+// policyCondition is the interface implemented by the conditions of policy rules.
+type policyCondition interface {
+	// evaluate checks if the condition is satisfied by the given claims.
+	evaluate(claims jwt.MapClaims) bool
+}
+
+// policyAll is a condition that is satisfied when all its conditions are satisfied.
+type policyAll []policyCondition
+
+// policyAny is a condition that is satisfied when at least one of its conditions is satisfied.
+type policyAny []policyCondition
+
+// policyNot is a condition that is satisfied when its condition isn't satisfied.
+type policyNot struct {
+	condition policyCondition
+}
+
+// policyClaim is a condition that is satisfied when the value of a claim matches a regular
+// expression or is one of a set of values. If the claim is an array then it is enough if one of
+// the elements matches.
+type policyClaim struct {
+	name    string
+	pattern *regexp.Regexp
+	values  map[string]bool
+}
+
+// loadPolicyFile loads the rules of the given policy file and adds them to the given list.
+func (b *HandlerBuilder) loadPolicyFile(file string, rules []*policyRule) (result []*policyRule,
+	err error) {
+	// Load the YAML data:
+	yamlData, err := ioutil.ReadFile(file) // nolint
+	if err != nil {
+		return
+	}
+
+	// Parse the YAML data:
+	var data policyData
+	err = yaml.Unmarshal(yamlData, &data)
+	if err != nil {
+		err = fmt.Errorf("can't parse policy file '%s': %v", file, err)
+		return
+	}
+
+	// Process the rules:
+	result = rules
+	for i, ruleData := range data.Rules {
+		var rule *policyRule
+		rule, err = compilePolicyRule(ruleData)
+		if err != nil {
+			err = fmt.Errorf(
+				"rule %d of policy file '%s' isn't valid: %v",
+				i, file, err,
+			)
+			return
+		}
+		result = append(result, rule)
+	}
+
+	return
+}
+
+// compilePolicyRule checks the data of a policy rule and converts it into a rule that can be
+// evaluated.
+func compilePolicyRule(data *policyRuleData) (rule *policyRule, err error) {
+	if data == nil {
+		err = fmt.Errorf("rule is empty")
+		return
+	}
+	if data.Name == "" {
+		err = fmt.Errorf("name is mandatory")
+		return
+	}
+	rule = &policyRule{
+		name: data.Name,
+	}
+	switch strings.ToLower(data.Effect) {
+	case "", policyEffectAllow:
+	case policyEffectDeny:
+		rule.deny = true
+	default:
+		err = fmt.Errorf(
+			"effect '%s' of rule '%s' isn't valid, it should be '%s' or '%s'",
+			data.Effect, data.Name, policyEffectAllow, policyEffectDeny,
+		)
+		return
+	}
+	if len(data.Methods) > 0 {
+		rule.methods = map[string]bool{}
+		for _, method := range data.Methods {
+			rule.methods[strings.ToUpper(method)] = true
+		}
+	}
+	rule.paths = make([]*regexp.Regexp, len(data.Paths))
+	for i, path := range data.Paths {
+		rule.paths[i], err = regexp.Compile(path)
+		if err != nil {
+			err = fmt.Errorf(
+				"path '%s' of rule '%s' isn't a valid regular expression: %v",
+				path, data.Name, err,
+			)
+			return
+		}
+	}
+	if data.When != nil {
+		rule.condition, err = compilePolicyCondition(data.When)
+		if err != nil {
+			err = fmt.Errorf("condition of rule '%s' isn't valid: %v", data.Name, err)
+			return
+		}
+	}
+	return
+}
+
+// compilePolicyCondition checks the data of a condition and converts it into a condition that can
+// be evaluated.
+func compilePolicyCondition(data *policyConditionData) (condition policyCondition, err error) {
+	// Check that exactly one kind of condition has been used:
+	count := 0
+	if data.All != nil {
+		count++
+	}
+	if data.Any != nil {
+		count++
+	}
+	if data.Not != nil {
+		count++
+	}
+	if data.Claim != "" {
+		count++
+	}
+	if count != 1 {
+		err = fmt.Errorf(
+			"condition should contain exactly one of 'all', 'any', 'not' or 'claim'",
+		)
+		return
+	}
+
+	switch {
+	case data.All != nil:
+		var items policyAll
+		items, err = compilePolicyConditions(data.All)
+		condition = items
+	case data.Any != nil:
+		var items policyAny
+		items, err = compilePolicyConditions(data.Any)
+		condition = items
+	case data.Not != nil:
+		var inner policyCondition
+		inner, err = compilePolicyCondition(data.Not)
+		condition = &policyNot{
+			condition: inner,
+		}
+	default:
+		condition, err = compilePolicyClaim(data)
+	}
+	return
+}
+
+// compilePolicyConditions compiles a list of conditions.
+func compilePolicyConditions(data []*policyConditionData) (conditions []policyCondition,
+	err error) {
+	conditions = make([]policyCondition, len(data))
+	for i, item := range data {
+		if item == nil {
+			err = fmt.Errorf("condition is empty")
+			return
+		}
+		conditions[i], err = compilePolicyCondition(item)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// compilePolicyClaim compiles a condition that checks the value of a claim.
+func compilePolicyClaim(data *policyConditionData) (condition *policyClaim, err error) {
+	if (data.Pattern == "") == (data.Values == nil) {
+		err = fmt.Errorf(
+			"condition for claim '%s' should contain exactly one of 'pattern' or 'values'",
+			data.Claim,
+		)
+		return
+	}
+	condition = &policyClaim{
+		name: data.Claim,
+	}
+	if data.Pattern != "" {
+		condition.pattern, err = regexp.Compile(data.Pattern)
+		if err != nil {
+			err = fmt.Errorf(
+				"pattern '%s' for claim '%s' isn't a valid regular expression: %v",
+				data.Pattern, data.Claim, err,
+			)
+			return
+		}
+	} else {
+		condition.values = map[string]bool{}
+		for _, value := range data.Values {
+			condition.values[value] = true
+		}
+	}
+	return
+}
+
+// matches checks if the rule applies to the given request and claims.
+func (r *policyRule) matches(request *http.Request, claims jwt.MapClaims) bool {
+	if r.methods != nil && !r.methods[request.Method] {
+		return false
+	}
+	if len(r.paths) > 0 {
+		found := false
+		for _, path := range r.paths {
+			if path.MatchString(request.URL.Path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return r.condition == nil || r.condition.evaluate(claims)
+}
+
+func (c policyAll) evaluate(claims jwt.MapClaims) bool {
+	for _, condition := range c {
+		if !condition.evaluate(claims) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c policyAny) evaluate(claims jwt.MapClaims) bool {
+	for _, condition := range c {
+		if condition.evaluate(claims) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *policyNot) evaluate(claims jwt.MapClaims) bool {
+	return !c.condition.evaluate(claims)
+}
+
+func (c *policyClaim) evaluate(claims jwt.MapClaims) bool {
+	// Find the value of the claim, navigating nested objects:
+	value, ok := lookupClaim(claims, c.name)
+	if !ok {
+		return false
+	}
+
+	// If the value is an array then it is enough if one of the elements matches:
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+	for _, item := range items {
+		switch item.(type) {
+		case string, float64, bool:
+		default:
+			continue
+		}
+		text := fmt.Sprintf("%v", item)
+		if c.pattern != nil && c.pattern.MatchString(text) {
+			return true
+		}
+		if c.values != nil && c.values[text] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPolicies evaluates the rules of the authorization policies. Rules with the deny effect take
+// precedence: if any of them matches the request access is denied. Otherwise the first rule with
+// the allow effect that matches the request allows access. If no rule matches access is denied. If
+// access is denied it sends an error response to the client and returns false. If access is
+// allowed, or there are no policies, it returns the name of the matched rule and true.
+func (h *Handler) checkPolicies(w http.ResponseWriter, r *http.Request,
+	claims jwt.MapClaims) (rule string, ok bool) {
+	// If there are no rules we consider that there are no restrictions:
+	if len(h.policyRules) == 0 {
+		ok = true
+		return
+	}
+
+	// Find the first deny rule that matches, and the first allow rule that matches:
+	ctx := r.Context()
+	subject, _ := claims["sub"].(string)
+	var allow, deny *policyRule
+	for _, current := range h.policyRules {
+		if current.deny && deny != nil || !current.deny && allow != nil {
+			continue
+		}
+		if !current.matches(r, claims) {
+			continue
+		}
+		if current.deny {
+			deny = current
+			break
+		}
+		allow = current
+	}
+
+	// Deny rules take precedence:
+	if deny != nil {
+		rule = deny.name
+		h.logger.Info(
+			ctx,
+			"Rule '%s' denied access to '%s %s' for subject '%s'",
+			rule, r.Method, r.URL.Path, subject,
+		)
+		h.sendForbidden(
+			w, r,
+			"Access denied",
+		)
+		return
+	}
+	if allow != nil {
+		rule = allow.name
+		h.logger.Debug(
+			ctx,
+			"Rule '%s' allowed access to '%s %s' for subject '%s'",
+			rule, r.Method, r.URL.Path, subject,
+		)
+		ok = true
+		return
+	}
+
+	// No rule matches, so access is denied:
+	h.logger.Info(
+		ctx,
+		"No rule allowed access to '%s %s' for subject '%s'",
+		r.Method, r.URL.Path, subject,
+	)
+	h.sendForbidden(
+		w, r,
+		"Access denied",
+	)
+	return
+}
@@ -0,0 +1,279 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the authorization policies of the authentication handler.
+
+package authentication
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var _ = Describe("Policy", func() {
+	// Name of the temporary policy file:
+	var policyFile string
+
+	// Name of the rule that the next handler found in the context:
+	var rule string
+
+	// Next handler used by the tests:
+	var next http.Handler
+
+	BeforeEach(func() {
+		rule = ""
+		next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			rule, err = RuleFromContext(r.Context())
+			Expect(err).ToNot(HaveOccurred())
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+
+	AfterEach(func() {
+		if policyFile != "" {
+			err := os.Remove(policyFile)
+			Expect(err).ToNot(HaveOccurred())
+			policyFile = ""
+		}
+	})
+
+	// build creates a handler that uses the given policy:
+	build := func(policy string) (*Handler, error) {
+		file, err := ioutil.TempFile("", "policy-*.yml")
+		Expect(err).ToNot(HaveOccurred())
+		_, err = file.WriteString(policy)
+		Expect(err).ToNot(HaveOccurred())
+		err = file.Close()
+		Expect(err).ToNot(HaveOccurred())
+		policyFile = file.Name()
+		return NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			PolicyFile(policyFile).
+			Next(next).
+			Build()
+	}
+
+	// send sends a request with a token containing the given claims and returns the response
+	// code:
+	send := func(handler *Handler, method, path string, claims jwt.MapClaims) int {
+		request := httptest.NewRequest(method, path, nil)
+		request.Header.Set("Authorization", "Bearer "+IssueBearer(claims))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// Policy used by most of the tests:
+	const policy = `
+rules:
+- name: deny-service-accounts
+  effect: deny
+  when:
+    claim: username
+    pattern: ^service-account-
+
+- name: create-clusters
+  methods:
+  - POST
+  paths:
+  - ^/api/clusters_mgmt/v1/clusters/?$
+  when:
+    any:
+    - claim: realm_access.roles
+      values:
+      - cluster-creator
+    - all:
+      - claim: org_id
+        values:
+        - "123"
+      - not:
+          claim: groups
+          values:
+          - suspended
+
+- name: read-only
+  methods:
+  - GET
+`
+
+	It("Allows request that matches a rule using a nested array claim", func() {
+		handler, err := build(policy)
+		Expect(err).ToNot(HaveOccurred())
+		code := send(handler, http.MethodPost, "/api/clusters_mgmt/v1/clusters", jwt.MapClaims{
+			"realm_access": map[string]interface{}{
+				"roles": []interface{}{"other", "cluster-creator"},
+			},
+		})
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(rule).To(Equal("create-clusters"))
+	})
+
+	It("Allows request that matches combined conditions", func() {
+		handler, err := build(policy)
+		Expect(err).ToNot(HaveOccurred())
+		code := send(handler, http.MethodPost, "/api/clusters_mgmt/v1/clusters", jwt.MapClaims{
+			"org_id": "123",
+			"groups": []interface{}{"admins"},
+		})
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(rule).To(Equal("create-clusters"))
+	})
+
+	It("Denies request that fails a negated condition", func() {
+		handler, err := build(policy)
+		Expect(err).ToNot(HaveOccurred())
+		code := send(handler, http.MethodPost, "/api/clusters_mgmt/v1/clusters", jwt.MapClaims{
+			"org_id": "123",
+			"groups": []interface{}{"admins", "suspended"},
+		})
+		Expect(code).To(Equal(http.StatusForbidden))
+	})
+
+	It("Denies request with method that doesn't match any rule", func() {
+		handler, err := build(policy)
+		Expect(err).ToNot(HaveOccurred())
+		code := send(handler, http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123", jwt.MapClaims{
+			"realm_access": map[string]interface{}{
+				"roles": []interface{}{"cluster-creator"},
+			},
+		})
+		Expect(code).To(Equal(http.StatusForbidden))
+	})
+
+	It("Doesn't send the authenticate header when access is denied", func() {
+		handler, err := build(policy)
+		Expect(err).ToNot(HaveOccurred())
+		request := httptest.NewRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123", nil)
+		request.Header.Set("Authorization", "Bearer "+IssueBearer(nil))
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		Expect(recorder.Header().Get("WWW-Authenticate")).To(BeEmpty())
+	})
+
+	It("Lets deny rules override allow rules that appear before", func() {
+		handler, err := build(`
+rules:
+- name: read-only
+  methods:
+  - GET
+
+- name: deny-service-accounts
+  effect: deny
+  when:
+    claim: username
+    pattern: ^service-account-
+`)
+		Expect(err).ToNot(HaveOccurred())
+		code := send(handler, http.MethodGet, "/api/clusters_mgmt/v1/clusters", jwt.MapClaims{
+			"username": "service-account-123",
+		})
+		Expect(code).To(Equal(http.StatusForbidden))
+		code = send(handler, http.MethodGet, "/api/clusters_mgmt/v1/clusters", jwt.MapClaims{
+			"username": "myuser",
+		})
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(rule).To(Equal("read-only"))
+	})
+
+	It("Applies rules in order", func() {
+		handler, err := build(policy)
+		Expect(err).ToNot(HaveOccurred())
+		code := send(handler, http.MethodGet, "/api/clusters_mgmt/v1/clusters", jwt.MapClaims{
+			"username": "service-account-123",
+		})
+		Expect(code).To(Equal(http.StatusForbidden))
+		code = send(handler, http.MethodGet, "/api/clusters_mgmt/v1/clusters", jwt.MapClaims{
+			"username": "myuser",
+		})
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(rule).To(Equal("read-only"))
+	})
+
+	It("Doesn't add rule to the context if there is no policy", func() {
+		handler, err := NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		code := send(handler, http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123", nil)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(rule).To(BeEmpty())
+	})
+
+	It("Can't be built with rule without name", func() {
+		_, err := build(`
+rules:
+- methods:
+  - GET
+`)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("name is mandatory"))
+	})
+
+	It("Can't be built with invalid effect", func() {
+		_, err := build(`
+rules:
+- name: myrule
+  effect: maybe
+`)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("maybe"))
+	})
+
+	It("Can't be built with condition that mixes kinds", func() {
+		_, err := build(`
+rules:
+- name: myrule
+  when:
+    claim: sub
+    values:
+    - myuser
+    not:
+      claim: sub
+      values:
+      - otheruser
+`)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("exactly one"))
+	})
+
+	It("Can't be built with invalid pattern", func() {
+		_, err := build(`
+rules:
+- name: myrule
+  when:
+    claim: sub
+    pattern: "["
+`)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("regular expression"))
+	})
+})
@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the OpenTelemetry tracing of the authentication handler.
+
+package authentication
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/api/trace/tracetest"
+	"go.opentelemetry.io/otel/propagators"
+)
+
+var _ = Describe("Tracing", func() {
+	// Recorder that collects the spans, and provider that uses it:
+	var recorder *tracetest.StandardSpanRecorder
+	var provider trace.TracerProvider
+
+	// Span found by the next handler in the context:
+	var current trace.Span
+
+	// Handler used by the tests:
+	var handler *Handler
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the tracer provider:
+		recorder = &tracetest.StandardSpanRecorder{}
+		provider = tracetest.NewTracerProvider(tracetest.WithSpanRecorder(recorder))
+
+		// Create the handler:
+		current = nil
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current = trace.SpanFromContext(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+		handler, err = NewHandler().
+			Logger(logger).
+			Service("clusters_mgmt").
+			Version("v1").
+			KeysFile(keysFile).
+			TracerProvider(provider).
+			Next(next).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// span returns the span created by the handler:
+	span := func() *tracetest.Span {
+		spans := recorder.Completed()
+		Expect(spans).To(HaveLen(1))
+		return spans[0]
+	}
+
+	It("Continues the trace of the caller", func() {
+		// Create the span of the caller:
+		ctx, caller := provider.Tracer("test").Start(context.Background(), "caller")
+		caller.End()
+
+		// Send the request, including the trace context in the headers:
+		request := httptest.NewRequest(http.MethodGet, "/private", nil)
+		request.Header.Set("Authorization", "Bearer "+IssueBearer(nil))
+		propagators.TraceContext{}.Inject(ctx, request.Header)
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+		Expect(response.Code).To(Equal(http.StatusOK))
+
+		// Check that the span of the handler is a child of the span of the caller, and that
+		// it is available to the next handler:
+		var result *tracetest.Span
+		for _, completed := range recorder.Completed() {
+			if completed.Name() == "authentication" {
+				result = completed
+			}
+		}
+		Expect(result).ToNot(BeNil())
+		Expect(result.SpanKind()).To(Equal(trace.SpanKindServer))
+		Expect(result.SpanContext().TraceID).To(Equal(caller.SpanContext().TraceID))
+		Expect(result.ParentSpanID()).To(Equal(caller.SpanContext().SpanID))
+		Expect(current).ToNot(BeNil())
+		Expect(current.SpanContext()).To(Equal(result.SpanContext()))
+	})
+
+	It("Adds the error code when the request is rejected", func() {
+		// Send the request without the authorization header:
+		request := httptest.NewRequest(http.MethodGet, "/private", nil)
+		response := httptest.NewRecorder()
+		handler.ServeHTTP(response, request)
+		Expect(response.Code).To(Equal(http.StatusUnauthorized))
+
+		// Check the span:
+		result := span()
+		Expect(result.Name()).To(Equal("authentication"))
+		Expect(result.Attributes()).To(HaveKey(tracingErrorCodeKey))
+		Expect(result.Attributes()[tracingErrorCodeKey].AsString()).To(
+			Equal("CLUSTERS-MGMT-401"),
+		)
+		Expect(result.Attributes()["http.status_code"].Emit()).To(Equal("401"))
+	})
+})
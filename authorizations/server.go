@@ -24,6 +24,7 @@ import (
 
 	v1 "github.com/openshift-online/ocm-sdk-go/authorizations/v1"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // Server is the interface for the 'authorizations' service.
@@ -35,22 +36,22 @@ type Server interface {
 
 // Dispatch navigates the servers tree till it finds one that matches the given set
 // of path segments, and then invokes it.
-func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []string) {
+func Dispatch(w http.ResponseWriter, r *http.Request, logger logging.Logger, server Server, segments []string) {
 	if len(segments) == 0 {
 		// TODO: This should send the service metadata.
-		errors.SendMethodNotAllowed(w, r)
+		errors.SendMethodNotAllowedWithLogger(w, r, logger)
 		return
 	} else {
 		switch segments[0] {
 		case "v1":
 			version := server.V1()
 			if version == nil {
-				errors.SendNotFound(w, r)
+				errors.SendNotFoundWithLogger(w, r, logger)
 				return
 			}
-			v1.Dispatch(w, r, version, segments[1:])
+			v1.Dispatch(w, r, logger, version, segments[1:])
 		default:
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -21,12 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/authorizations/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readAccessReviewPostRequest(request *AccessReviewPostServerRequest, r *http.Request) error {
-	var err error
-	request.request, err = UnmarshalAccessReviewRequest(r)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, accessReviewRequestValidation, helpers.ValidateAction)
+	if err != nil {
+		return err
+	}
+	request.request, err = UnmarshalAccessReviewRequest(body)
 	return err
 }
 func writeAccessReviewPostRequest(request *AccessReviewPostRequest, writer io.Writer) error {
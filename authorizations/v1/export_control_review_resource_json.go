@@ -21,12 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/authorizations/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readExportControlReviewPostRequest(request *ExportControlReviewPostServerRequest, r *http.Request) error {
-	var err error
-	request.request, err = UnmarshalExportControlReviewRequest(r)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, exportControlReviewRequestValidation, helpers.ValidateAction)
+	if err != nil {
+		return err
+	}
+	request.request, err = UnmarshalExportControlReviewRequest(body)
 	return err
 }
 func writeExportControlReviewPostRequest(request *ExportControlReviewPostRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // ExportControlReviewServer represents the interface the manages the 'export_control_review' resource.
@@ -87,20 +87,20 @@ func (r *ExportControlReviewPostServerResponse) Status(value int) *ExportControl
 // dispatchExportControlReview navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchExportControlReview(w http.ResponseWriter, r *http.Request, server ExportControlReviewServer, segments []string) {
+func dispatchExportControlReview(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ExportControlReviewServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptExportControlReviewPostRequest(w, r, server)
+			adaptExportControlReviewPostRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -108,31 +108,24 @@ func dispatchExportControlReview(w http.ResponseWriter, r *http.Request, server
 // adaptExportControlReviewPostRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptExportControlReviewPostRequest(w http.ResponseWriter, r *http.Request, server ExportControlReviewServer) {
+func adaptExportControlReviewPostRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ExportControlReviewServer) {
 	request := &ExportControlReviewPostServerRequest{}
 	err := readExportControlReviewPostRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ExportControlReviewPostServerResponse{}
 	response.status = 201
 	err = server.Post(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeExportControlReviewPostResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
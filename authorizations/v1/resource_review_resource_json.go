@@ -21,12 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/authorizations/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readResourceReviewPostRequest(request *ResourceReviewPostServerRequest, r *http.Request) error {
-	var err error
-	request.request, err = UnmarshalResourceReviewRequest(r)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, resourceReviewRequestValidation, helpers.ValidateAction)
+	if err != nil {
+		return err
+	}
+	request.request, err = UnmarshalResourceReviewRequest(body)
 	return err
 }
 func writeResourceReviewPostRequest(request *ResourceReviewPostRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // ResourceReviewServer represents the interface the manages the 'resource_review' resource.
@@ -88,20 +88,20 @@ func (r *ResourceReviewPostServerResponse) Status(value int) *ResourceReviewPost
 // dispatchResourceReview navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchResourceReview(w http.ResponseWriter, r *http.Request, server ResourceReviewServer, segments []string) {
+func dispatchResourceReview(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceReviewServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptResourceReviewPostRequest(w, r, server)
+			adaptResourceReviewPostRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -109,31 +109,24 @@ func dispatchResourceReview(w http.ResponseWriter, r *http.Request, server Resou
 // adaptResourceReviewPostRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptResourceReviewPostRequest(w http.ResponseWriter, r *http.Request, server ResourceReviewServer) {
+func adaptResourceReviewPostRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ResourceReviewServer) {
 	request := &ResourceReviewPostServerRequest{}
 	err := readResourceReviewPostRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ResourceReviewPostServerResponse{}
 	response.status = 201
 	err = server.Post(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeResourceReviewPostResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
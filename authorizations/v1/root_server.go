@@ -23,6 +23,8 @@ import (
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // Server represents the interface the manages the 'root' resource.
@@ -52,11 +54,14 @@ type Server interface {
 // Dispatch navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []string) {
+func Dispatch(w http.ResponseWriter, r *http.Request, logger logging.Logger, server Server, segments []string) {
+	w, r, end := helpers.StartServerSpan(w, r, "/api/authorizations/v1")
+	defer end()
+	defer errors.RecoverPanic(w, r, logger)
 	if len(segments) == 0 {
 		switch r.Method {
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -64,33 +69,33 @@ func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []
 	case "access_review":
 		target := server.AccessReview()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAccessReview(w, r, target, segments[1:])
+		dispatchAccessReview(w, r, logger, target, segments[1:])
 	case "export_control_review":
 		target := server.ExportControlReview()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchExportControlReview(w, r, target, segments[1:])
+		dispatchExportControlReview(w, r, logger, target, segments[1:])
 	case "resource_review":
 		target := server.ResourceReview()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchResourceReview(w, r, target, segments[1:])
+		dispatchResourceReview(w, r, logger, target, segments[1:])
 	case "self_access_review":
 		target := server.SelfAccessReview()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchSelfAccessReview(w, r, target, segments[1:])
+		dispatchSelfAccessReview(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -21,12 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/authorizations/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readSelfAccessReviewPostRequest(request *SelfAccessReviewPostServerRequest, r *http.Request) error {
-	var err error
-	request.request, err = UnmarshalSelfAccessReviewRequest(r)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, selfAccessReviewRequestValidation, helpers.ValidateAction)
+	if err != nil {
+		return err
+	}
+	request.request, err = UnmarshalSelfAccessReviewRequest(body)
 	return err
 }
 func writeSelfAccessReviewPostRequest(request *SelfAccessReviewPostRequest, writer io.Writer) error {
@@ -0,0 +1,217 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// IMPORTANT: This file has been generated automatically, refrain from modifying it manually as all
+// your changes will be lost when the file is generated again.
+
+package v1 // github.com/openshift-online/ocm-sdk-go/authorizations/v1
+
+import (
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+)
+
+// accessReviewRequestValidation describes the 'access_review_request' type.
+var accessReviewRequestValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// accessReviewResponseValidation describes the 'access_review_response' type.
+var accessReviewResponseValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// exportControlReviewRequestValidation describes the 'export_control_review_request' type.
+var exportControlReviewRequestValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// exportControlReviewResponseValidation describes the 'export_control_review_response' type.
+var exportControlReviewResponseValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// resourceReviewValidation describes the 'resource_review' type.
+var resourceReviewValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// resourceReviewRequestValidation describes the 'resource_review_request' type.
+var resourceReviewRequestValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// selfAccessReviewRequestValidation describes the 'self_access_review_request' type.
+var selfAccessReviewRequestValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// selfAccessReviewResponseValidation describes the 'self_access_review_response' type.
+var selfAccessReviewResponseValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// init sets the attributes of the descriptions of the object types. This isn't done in the
+// declarations because the types may refer to each other.
+func init() {
+	accessReviewRequestValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"account_username": {
+			Type: helpers.ValidationStringType,
+		},
+		"action": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_uuid": {
+			Type: helpers.ValidationStringType,
+		},
+		"organization_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"subscription_id": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	accessReviewResponseValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"account_username": {
+			Type: helpers.ValidationStringType,
+		},
+		"action": {
+			Type: helpers.ValidationStringType,
+		},
+		"allowed": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_uuid": {
+			Type: helpers.ValidationStringType,
+		},
+		"organization_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"subscription_id": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	exportControlReviewRequestValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"account_username": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	exportControlReviewResponseValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"restricted": {
+			Type: helpers.ValidationBooleanType,
+		},
+	}
+	resourceReviewValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"account_username": {
+			Type: helpers.ValidationStringType,
+		},
+		"action": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_ids": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"cluster_uuids": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"organization_ids": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"subscription_ids": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+	}
+	resourceReviewRequestValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"account_username": {
+			Type: helpers.ValidationStringType,
+		},
+		"action": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	selfAccessReviewRequestValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"action": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_uuid": {
+			Type: helpers.ValidationStringType,
+		},
+		"organization_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"subscription_id": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	selfAccessReviewResponseValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"action": {
+			Type: helpers.ValidationStringType,
+		},
+		"allowed": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"cluster_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"cluster_uuid": {
+			Type: helpers.ValidationStringType,
+		},
+		"organization_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"subscription_id": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+}
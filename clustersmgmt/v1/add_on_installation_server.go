@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // AddOnInstallationServer represents the interface the manages the 'add_on_installation' resource.
@@ -85,23 +85,23 @@ func (r *AddOnInstallationGetServerResponse) Status(value int) *AddOnInstallatio
 // dispatchAddOnInstallation navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchAddOnInstallation(w http.ResponseWriter, r *http.Request, server AddOnInstallationServer, segments []string) {
+func dispatchAddOnInstallation(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnInstallationServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptAddOnInstallationDeleteRequest(w, r, server)
+			adaptAddOnInstallationDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptAddOnInstallationGetRequest(w, r, server)
+			adaptAddOnInstallationGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -109,31 +109,24 @@ func dispatchAddOnInstallation(w http.ResponseWriter, r *http.Request, server Ad
 // adaptAddOnInstallationDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnInstallationDeleteRequest(w http.ResponseWriter, r *http.Request, server AddOnInstallationServer) {
+func adaptAddOnInstallationDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnInstallationServer) {
 	request := &AddOnInstallationDeleteServerRequest{}
 	err := readAddOnInstallationDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnInstallationDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnInstallationDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -144,31 +137,24 @@ func adaptAddOnInstallationDeleteRequest(w http.ResponseWriter, r *http.Request,
 // adaptAddOnInstallationGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnInstallationGetRequest(w http.ResponseWriter, r *http.Request, server AddOnInstallationServer) {
+func adaptAddOnInstallationGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnInstallationServer) {
 	request := &AddOnInstallationGetServerRequest{}
 	err := readAddOnInstallationGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnInstallationGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnInstallationGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readAddOnInstallationsAddRequest(request *AddOnInstallationsAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalAddOnInstallation(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, addOnInstallationValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalAddOnInstallation(body)
 	return err
 }
 func writeAddOnInstallationsAddRequest(request *AddOnInstallationsAddRequest, writer io.Writer) error {
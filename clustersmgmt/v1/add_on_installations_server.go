@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // AddOnInstallationsServer represents the interface the manages the 'add_on_installations' resource.
@@ -294,17 +294,17 @@ func (r *AddOnInstallationsListServerResponse) Status(value int) *AddOnInstallat
 // dispatchAddOnInstallations navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchAddOnInstallations(w http.ResponseWriter, r *http.Request, server AddOnInstallationsServer, segments []string) {
+func dispatchAddOnInstallations(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnInstallationsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptAddOnInstallationsAddRequest(w, r, server)
+			adaptAddOnInstallationsAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptAddOnInstallationsListRequest(w, r, server)
+			adaptAddOnInstallationsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -312,41 +312,34 @@ func dispatchAddOnInstallations(w http.ResponseWriter, r *http.Request, server A
 	default:
 		target := server.Addoninstallation(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAddOnInstallation(w, r, target, segments[1:])
+		dispatchAddOnInstallation(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptAddOnInstallationsAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnInstallationsAddRequest(w http.ResponseWriter, r *http.Request, server AddOnInstallationsServer) {
+func adaptAddOnInstallationsAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnInstallationsServer) {
 	request := &AddOnInstallationsAddServerRequest{}
 	err := readAddOnInstallationsAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnInstallationsAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnInstallationsAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -357,31 +350,24 @@ func adaptAddOnInstallationsAddRequest(w http.ResponseWriter, r *http.Request, s
 // adaptAddOnInstallationsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnInstallationsListRequest(w http.ResponseWriter, r *http.Request, server AddOnInstallationsServer) {
+func adaptAddOnInstallationsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnInstallationsServer) {
 	request := &AddOnInstallationsListServerRequest{}
 	err := readAddOnInstallationsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnInstallationsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnInstallationsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -21,7 +21,10 @@ package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readAddOnDeleteRequest(request *AddOnDeleteServerRequest, r *http.Request) error {
@@ -51,8 +54,15 @@ func writeAddOnGetResponse(response *AddOnGetServerResponse, w http.ResponseWrit
 	return MarshalAddOn(response.body, w)
 }
 func readAddOnUpdateRequest(request *AddOnUpdateServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalAddOn(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, addOnValidation, helpers.ValidateUpdate)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalAddOn(body)
 	return err
 }
 func writeAddOnUpdateRequest(request *AddOnUpdateRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // AddOnServer represents the interface the manages the 'add_on' resource.
@@ -138,26 +138,26 @@ func (r *AddOnUpdateServerResponse) Status(value int) *AddOnUpdateServerResponse
 // dispatchAddOn navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchAddOn(w http.ResponseWriter, r *http.Request, server AddOnServer, segments []string) {
+func dispatchAddOn(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptAddOnDeleteRequest(w, r, server)
+			adaptAddOnDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptAddOnGetRequest(w, r, server)
+			adaptAddOnGetRequest(w, r, logger, server)
 			return
 		case "PATCH":
-			adaptAddOnUpdateRequest(w, r, server)
+			adaptAddOnUpdateRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -165,31 +165,24 @@ func dispatchAddOn(w http.ResponseWriter, r *http.Request, server AddOnServer, s
 // adaptAddOnDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnDeleteRequest(w http.ResponseWriter, r *http.Request, server AddOnServer) {
+func adaptAddOnDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnServer) {
 	request := &AddOnDeleteServerRequest{}
 	err := readAddOnDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -200,31 +193,24 @@ func adaptAddOnDeleteRequest(w http.ResponseWriter, r *http.Request, server AddO
 // adaptAddOnGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnGetRequest(w http.ResponseWriter, r *http.Request, server AddOnServer) {
+func adaptAddOnGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnServer) {
 	request := &AddOnGetServerRequest{}
 	err := readAddOnGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -235,31 +221,24 @@ func adaptAddOnGetRequest(w http.ResponseWriter, r *http.Request, server AddOnSe
 // adaptAddOnUpdateRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnUpdateRequest(w http.ResponseWriter, r *http.Request, server AddOnServer) {
+func adaptAddOnUpdateRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnServer) {
 	request := &AddOnUpdateServerRequest{}
 	err := readAddOnUpdateRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnUpdateServerResponse{}
 	response.status = 204
 	err = server.Update(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnUpdateResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
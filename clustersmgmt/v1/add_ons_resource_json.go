@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readAddOnsAddRequest(request *AddOnsAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalAddOn(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, addOnValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalAddOn(body)
 	return err
 }
 func writeAddOnsAddRequest(request *AddOnsAddRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // AddOnsServer represents the interface the manages the 'add_ons' resource.
@@ -294,17 +294,17 @@ func (r *AddOnsListServerResponse) Status(value int) *AddOnsListServerResponse {
 // dispatchAddOns navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchAddOns(w http.ResponseWriter, r *http.Request, server AddOnsServer, segments []string) {
+func dispatchAddOns(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptAddOnsAddRequest(w, r, server)
+			adaptAddOnsAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptAddOnsListRequest(w, r, server)
+			adaptAddOnsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -312,41 +312,34 @@ func dispatchAddOns(w http.ResponseWriter, r *http.Request, server AddOnsServer,
 	default:
 		target := server.Addon(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAddOn(w, r, target, segments[1:])
+		dispatchAddOn(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptAddOnsAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnsAddRequest(w http.ResponseWriter, r *http.Request, server AddOnsServer) {
+func adaptAddOnsAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnsServer) {
 	request := &AddOnsAddServerRequest{}
 	err := readAddOnsAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnsAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnsAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -357,31 +350,24 @@ func adaptAddOnsAddRequest(w http.ResponseWriter, r *http.Request, server AddOns
 // adaptAddOnsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAddOnsListRequest(w http.ResponseWriter, r *http.Request, server AddOnsServer) {
+func adaptAddOnsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AddOnsServer) {
 	request := &AddOnsListServerRequest{}
 	err := readAddOnsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AddOnsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAddOnsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
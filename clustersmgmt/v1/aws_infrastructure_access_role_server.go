@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // AWSInfrastructureAccessRoleServer represents the interface the manages the 'AWS_infrastructure_access_role' resource.
@@ -64,20 +64,20 @@ func (r *AWSInfrastructureAccessRoleGetServerResponse) Status(value int) *AWSInf
 // dispatchAWSInfrastructureAccessRole navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchAWSInfrastructureAccessRole(w http.ResponseWriter, r *http.Request, server AWSInfrastructureAccessRoleServer, segments []string) {
+func dispatchAWSInfrastructureAccessRole(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AWSInfrastructureAccessRoleServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptAWSInfrastructureAccessRoleGetRequest(w, r, server)
+			adaptAWSInfrastructureAccessRoleGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -85,31 +85,24 @@ func dispatchAWSInfrastructureAccessRole(w http.ResponseWriter, r *http.Request,
 // adaptAWSInfrastructureAccessRoleGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAWSInfrastructureAccessRoleGetRequest(w http.ResponseWriter, r *http.Request, server AWSInfrastructureAccessRoleServer) {
+func adaptAWSInfrastructureAccessRoleGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AWSInfrastructureAccessRoleServer) {
 	request := &AWSInfrastructureAccessRoleGetServerRequest{}
 	err := readAWSInfrastructureAccessRoleGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AWSInfrastructureAccessRoleGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAWSInfrastructureAccessRoleGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
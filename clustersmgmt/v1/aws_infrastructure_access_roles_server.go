@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // AWSInfrastructureAccessRolesServer represents the interface the manages the 'AWS_infrastructure_access_roles' resource.
@@ -241,14 +241,14 @@ func (r *AWSInfrastructureAccessRolesListServerResponse) Status(value int) *AWSI
 // dispatchAWSInfrastructureAccessRoles navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchAWSInfrastructureAccessRoles(w http.ResponseWriter, r *http.Request, server AWSInfrastructureAccessRolesServer, segments []string) {
+func dispatchAWSInfrastructureAccessRoles(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AWSInfrastructureAccessRolesServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptAWSInfrastructureAccessRolesListRequest(w, r, server)
+			adaptAWSInfrastructureAccessRolesListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -256,41 +256,34 @@ func dispatchAWSInfrastructureAccessRoles(w http.ResponseWriter, r *http.Request
 	default:
 		target := server.AWSInfrastructureAccessRole(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAWSInfrastructureAccessRole(w, r, target, segments[1:])
+		dispatchAWSInfrastructureAccessRole(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptAWSInfrastructureAccessRolesListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptAWSInfrastructureAccessRolesListRequest(w http.ResponseWriter, r *http.Request, server AWSInfrastructureAccessRolesServer) {
+func adaptAWSInfrastructureAccessRolesListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server AWSInfrastructureAccessRolesServer) {
 	request := &AWSInfrastructureAccessRolesListServerRequest{}
 	err := readAWSInfrastructureAccessRolesListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &AWSInfrastructureAccessRolesListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeAWSInfrastructureAccessRolesListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
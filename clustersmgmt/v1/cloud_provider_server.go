@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // CloudProviderServer represents the interface the manages the 'cloud_provider' resource.
@@ -70,14 +70,14 @@ func (r *CloudProviderGetServerResponse) Status(value int) *CloudProviderGetServ
 // dispatchCloudProvider navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchCloudProvider(w http.ResponseWriter, r *http.Request, server CloudProviderServer, segments []string) {
+func dispatchCloudProvider(w http.ResponseWriter, r *http.Request, logger logging.Logger, server CloudProviderServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptCloudProviderGetRequest(w, r, server)
+			adaptCloudProviderGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -85,12 +85,12 @@ func dispatchCloudProvider(w http.ResponseWriter, r *http.Request, server CloudP
 	case "regions":
 		target := server.Regions()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchCloudRegions(w, r, target, segments[1:])
+		dispatchCloudRegions(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -98,31 +98,24 @@ func dispatchCloudProvider(w http.ResponseWriter, r *http.Request, server CloudP
 // adaptCloudProviderGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptCloudProviderGetRequest(w http.ResponseWriter, r *http.Request, server CloudProviderServer) {
+func adaptCloudProviderGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server CloudProviderServer) {
 	request := &CloudProviderGetServerRequest{}
 	err := readCloudProviderGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &CloudProviderGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeCloudProviderGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // CloudProvidersServer represents the interface the manages the 'cloud_providers' resource.
@@ -241,14 +241,14 @@ func (r *CloudProvidersListServerResponse) Status(value int) *CloudProvidersList
 // dispatchCloudProviders navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchCloudProviders(w http.ResponseWriter, r *http.Request, server CloudProvidersServer, segments []string) {
+func dispatchCloudProviders(w http.ResponseWriter, r *http.Request, logger logging.Logger, server CloudProvidersServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptCloudProvidersListRequest(w, r, server)
+			adaptCloudProvidersListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -256,41 +256,34 @@ func dispatchCloudProviders(w http.ResponseWriter, r *http.Request, server Cloud
 	default:
 		target := server.CloudProvider(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchCloudProvider(w, r, target, segments[1:])
+		dispatchCloudProvider(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptCloudProvidersListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptCloudProvidersListRequest(w http.ResponseWriter, r *http.Request, server CloudProvidersServer) {
+func adaptCloudProvidersListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server CloudProvidersServer) {
 	request := &CloudProvidersListServerRequest{}
 	err := readCloudProvidersListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &CloudProvidersListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeCloudProvidersListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
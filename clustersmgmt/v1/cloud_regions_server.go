@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // CloudRegionsServer represents the interface the manages the 'cloud_regions' resource.
@@ -157,14 +157,14 @@ func (r *CloudRegionsListServerResponse) Status(value int) *CloudRegionsListServ
 // dispatchCloudRegions navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchCloudRegions(w http.ResponseWriter, r *http.Request, server CloudRegionsServer, segments []string) {
+func dispatchCloudRegions(w http.ResponseWriter, r *http.Request, logger logging.Logger, server CloudRegionsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptCloudRegionsListRequest(w, r, server)
+			adaptCloudRegionsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -172,41 +172,34 @@ func dispatchCloudRegions(w http.ResponseWriter, r *http.Request, server CloudRe
 	default:
 		target := server.Region(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchCloudRegion(w, r, target, segments[1:])
+		dispatchCloudRegion(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptCloudRegionsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptCloudRegionsListRequest(w http.ResponseWriter, r *http.Request, server CloudRegionsServer) {
+func adaptCloudRegionsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server CloudRegionsServer) {
 	request := &CloudRegionsListServerRequest{}
 	err := readCloudRegionsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &CloudRegionsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeCloudRegionsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
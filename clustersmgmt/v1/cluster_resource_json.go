@@ -21,7 +21,10 @@ package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readClusterDeleteRequest(request *ClusterDeleteServerRequest, r *http.Request) error {
@@ -51,8 +54,15 @@ func writeClusterGetResponse(response *ClusterGetServerResponse, w http.Response
 	return MarshalCluster(response.body, w)
 }
 func readClusterUpdateRequest(request *ClusterUpdateServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalCluster(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, clusterValidation, helpers.ValidateUpdate)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalCluster(body)
 	return err
 }
 func writeClusterUpdateRequest(request *ClusterUpdateRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // ClusterServer represents the interface the manages the 'cluster' resource.
@@ -173,20 +173,20 @@ func (r *ClusterUpdateServerResponse) Status(value int) *ClusterUpdateServerResp
 // dispatchCluster navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchCluster(w http.ResponseWriter, r *http.Request, server ClusterServer, segments []string) {
+func dispatchCluster(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptClusterDeleteRequest(w, r, server)
+			adaptClusterDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptClusterGetRequest(w, r, server)
+			adaptClusterGetRequest(w, r, logger, server)
 			return
 		case "PATCH":
-			adaptClusterUpdateRequest(w, r, server)
+			adaptClusterUpdateRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -194,54 +194,54 @@ func dispatchCluster(w http.ResponseWriter, r *http.Request, server ClusterServe
 	case "addons":
 		target := server.Addons()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAddOnInstallations(w, r, target, segments[1:])
+		dispatchAddOnInstallations(w, r, logger, target, segments[1:])
 	case "credentials":
 		target := server.Credentials()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchCredentials(w, r, target, segments[1:])
+		dispatchCredentials(w, r, logger, target, segments[1:])
 	case "groups":
 		target := server.Groups()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchGroups(w, r, target, segments[1:])
+		dispatchGroups(w, r, logger, target, segments[1:])
 	case "identity_providers":
 		target := server.IdentityProviders()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchIdentityProviders(w, r, target, segments[1:])
+		dispatchIdentityProviders(w, r, logger, target, segments[1:])
 	case "logs":
 		target := server.Logs()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchLogs(w, r, target, segments[1:])
+		dispatchLogs(w, r, logger, target, segments[1:])
 	case "metric_queries":
 		target := server.MetricQueries()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchMetricQueries(w, r, target, segments[1:])
+		dispatchMetricQueries(w, r, logger, target, segments[1:])
 	case "status":
 		target := server.Status()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchClusterStatus(w, r, target, segments[1:])
+		dispatchClusterStatus(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -249,31 +249,24 @@ func dispatchCluster(w http.ResponseWriter, r *http.Request, server ClusterServe
 // adaptClusterDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptClusterDeleteRequest(w http.ResponseWriter, r *http.Request, server ClusterServer) {
+func adaptClusterDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterServer) {
 	request := &ClusterDeleteServerRequest{}
 	err := readClusterDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ClusterDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeClusterDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -284,31 +277,24 @@ func adaptClusterDeleteRequest(w http.ResponseWriter, r *http.Request, server Cl
 // adaptClusterGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptClusterGetRequest(w http.ResponseWriter, r *http.Request, server ClusterServer) {
+func adaptClusterGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterServer) {
 	request := &ClusterGetServerRequest{}
 	err := readClusterGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ClusterGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeClusterGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -319,31 +305,24 @@ func adaptClusterGetRequest(w http.ResponseWriter, r *http.Request, server Clust
 // adaptClusterUpdateRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptClusterUpdateRequest(w http.ResponseWriter, r *http.Request, server ClusterServer) {
+func adaptClusterUpdateRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterServer) {
 	request := &ClusterUpdateServerRequest{}
 	err := readClusterUpdateRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ClusterUpdateServerResponse{}
 	response.status = 204
 	err = server.Update(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeClusterUpdateResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
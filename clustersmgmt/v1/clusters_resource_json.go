@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readClustersAddRequest(request *ClustersAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalCluster(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, clusterValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalCluster(body)
 	return err
 }
 func writeClustersAddRequest(request *ClustersAddRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // ClustersServer represents the interface the manages the 'clusters' resource.
@@ -298,17 +298,17 @@ func (r *ClustersListServerResponse) Status(value int) *ClustersListServerRespon
 // dispatchClusters navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchClusters(w http.ResponseWriter, r *http.Request, server ClustersServer, segments []string) {
+func dispatchClusters(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClustersServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptClustersAddRequest(w, r, server)
+			adaptClustersAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptClustersListRequest(w, r, server)
+			adaptClustersListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -316,41 +316,34 @@ func dispatchClusters(w http.ResponseWriter, r *http.Request, server ClustersSer
 	default:
 		target := server.Cluster(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchCluster(w, r, target, segments[1:])
+		dispatchCluster(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptClustersAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptClustersAddRequest(w http.ResponseWriter, r *http.Request, server ClustersServer) {
+func adaptClustersAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClustersServer) {
 	request := &ClustersAddServerRequest{}
 	err := readClustersAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ClustersAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeClustersAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -361,31 +354,24 @@ func adaptClustersAddRequest(w http.ResponseWriter, r *http.Request, server Clus
 // adaptClustersListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptClustersListRequest(w http.ResponseWriter, r *http.Request, server ClustersServer) {
+func adaptClustersListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClustersServer) {
 	request := &ClustersListServerRequest{}
 	err := readClustersListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ClustersListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeClustersListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
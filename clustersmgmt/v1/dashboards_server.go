@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // DashboardsServer represents the interface the manages the 'dashboards' resource.
@@ -241,14 +241,14 @@ func (r *DashboardsListServerResponse) Status(value int) *DashboardsListServerRe
 // dispatchDashboards navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchDashboards(w http.ResponseWriter, r *http.Request, server DashboardsServer, segments []string) {
+func dispatchDashboards(w http.ResponseWriter, r *http.Request, logger logging.Logger, server DashboardsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptDashboardsListRequest(w, r, server)
+			adaptDashboardsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -256,41 +256,34 @@ func dispatchDashboards(w http.ResponseWriter, r *http.Request, server Dashboard
 	default:
 		target := server.Dashboard(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchDashboard(w, r, target, segments[1:])
+		dispatchDashboard(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptDashboardsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptDashboardsListRequest(w http.ResponseWriter, r *http.Request, server DashboardsServer) {
+func adaptDashboardsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server DashboardsServer) {
 	request := &DashboardsListServerRequest{}
 	err := readDashboardsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &DashboardsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeDashboardsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
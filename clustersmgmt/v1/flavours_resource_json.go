@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readFlavoursAddRequest(request *FlavoursAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalFlavour(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, flavourValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalFlavour(body)
 	return err
 }
 func writeFlavoursAddRequest(request *FlavoursAddRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // FlavoursServer represents the interface the manages the 'flavours' resource.
@@ -294,17 +294,17 @@ func (r *FlavoursListServerResponse) Status(value int) *FlavoursListServerRespon
 // dispatchFlavours navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchFlavours(w http.ResponseWriter, r *http.Request, server FlavoursServer, segments []string) {
+func dispatchFlavours(w http.ResponseWriter, r *http.Request, logger logging.Logger, server FlavoursServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptFlavoursAddRequest(w, r, server)
+			adaptFlavoursAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptFlavoursListRequest(w, r, server)
+			adaptFlavoursListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -312,41 +312,34 @@ func dispatchFlavours(w http.ResponseWriter, r *http.Request, server FlavoursSer
 	default:
 		target := server.Flavour(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchFlavour(w, r, target, segments[1:])
+		dispatchFlavour(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptFlavoursAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptFlavoursAddRequest(w http.ResponseWriter, r *http.Request, server FlavoursServer) {
+func adaptFlavoursAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server FlavoursServer) {
 	request := &FlavoursAddServerRequest{}
 	err := readFlavoursAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &FlavoursAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeFlavoursAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -357,31 +350,24 @@ func adaptFlavoursAddRequest(w http.ResponseWriter, r *http.Request, server Flav
 // adaptFlavoursListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptFlavoursListRequest(w http.ResponseWriter, r *http.Request, server FlavoursServer) {
+func adaptFlavoursListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server FlavoursServer) {
 	request := &FlavoursListServerRequest{}
 	err := readFlavoursListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &FlavoursListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeFlavoursListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // GroupServer represents the interface the manages the 'group' resource.
@@ -69,14 +69,14 @@ func (r *GroupGetServerResponse) Status(value int) *GroupGetServerResponse {
 // dispatchGroup navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchGroup(w http.ResponseWriter, r *http.Request, server GroupServer, segments []string) {
+func dispatchGroup(w http.ResponseWriter, r *http.Request, logger logging.Logger, server GroupServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptGroupGetRequest(w, r, server)
+			adaptGroupGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -84,12 +84,12 @@ func dispatchGroup(w http.ResponseWriter, r *http.Request, server GroupServer, s
 	case "users":
 		target := server.Users()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchUsers(w, r, target, segments[1:])
+		dispatchUsers(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -97,31 +97,24 @@ func dispatchGroup(w http.ResponseWriter, r *http.Request, server GroupServer, s
 // adaptGroupGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptGroupGetRequest(w http.ResponseWriter, r *http.Request, server GroupServer) {
+func adaptGroupGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server GroupServer) {
 	request := &GroupGetServerRequest{}
 	err := readGroupGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &GroupGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeGroupGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
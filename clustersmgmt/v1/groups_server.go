@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // GroupsServer represents the interface the manages the 'groups' resource.
@@ -142,14 +142,14 @@ func (r *GroupsListServerResponse) Status(value int) *GroupsListServerResponse {
 // dispatchGroups navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchGroups(w http.ResponseWriter, r *http.Request, server GroupsServer, segments []string) {
+func dispatchGroups(w http.ResponseWriter, r *http.Request, logger logging.Logger, server GroupsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptGroupsListRequest(w, r, server)
+			adaptGroupsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -157,41 +157,34 @@ func dispatchGroups(w http.ResponseWriter, r *http.Request, server GroupsServer,
 	default:
 		target := server.Group(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchGroup(w, r, target, segments[1:])
+		dispatchGroup(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptGroupsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptGroupsListRequest(w http.ResponseWriter, r *http.Request, server GroupsServer) {
+func adaptGroupsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server GroupsServer) {
 	request := &GroupsListServerRequest{}
 	err := readGroupsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &GroupsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeGroupsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
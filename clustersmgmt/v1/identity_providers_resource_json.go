@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readIdentityProvidersAddRequest(request *IdentityProvidersAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalIdentityProvider(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, identityProviderValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalIdentityProvider(body)
 	return err
 }
 func writeIdentityProvidersAddRequest(request *IdentityProvidersAddRequest, writer io.Writer) error {
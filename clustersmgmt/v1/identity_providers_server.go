@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // IdentityProvidersServer represents the interface the manages the 'identity_providers' resource.
@@ -195,17 +195,17 @@ func (r *IdentityProvidersListServerResponse) Status(value int) *IdentityProvide
 // dispatchIdentityProviders navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchIdentityProviders(w http.ResponseWriter, r *http.Request, server IdentityProvidersServer, segments []string) {
+func dispatchIdentityProviders(w http.ResponseWriter, r *http.Request, logger logging.Logger, server IdentityProvidersServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptIdentityProvidersAddRequest(w, r, server)
+			adaptIdentityProvidersAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptIdentityProvidersListRequest(w, r, server)
+			adaptIdentityProvidersListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -213,41 +213,34 @@ func dispatchIdentityProviders(w http.ResponseWriter, r *http.Request, server Id
 	default:
 		target := server.IdentityProvider(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchIdentityProvider(w, r, target, segments[1:])
+		dispatchIdentityProvider(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptIdentityProvidersAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptIdentityProvidersAddRequest(w http.ResponseWriter, r *http.Request, server IdentityProvidersServer) {
+func adaptIdentityProvidersAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server IdentityProvidersServer) {
 	request := &IdentityProvidersAddServerRequest{}
 	err := readIdentityProvidersAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &IdentityProvidersAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeIdentityProvidersAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -258,31 +251,24 @@ func adaptIdentityProvidersAddRequest(w http.ResponseWriter, r *http.Request, se
 // adaptIdentityProvidersListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptIdentityProvidersListRequest(w http.ResponseWriter, r *http.Request, server IdentityProvidersServer) {
+func adaptIdentityProvidersListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server IdentityProvidersServer) {
 	request := &IdentityProvidersListServerRequest{}
 	err := readIdentityProvidersListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &IdentityProvidersListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeIdentityProvidersListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // LogsServer represents the interface the manages the 'logs' resource.
@@ -142,14 +142,14 @@ func (r *LogsListServerResponse) Status(value int) *LogsListServerResponse {
 // dispatchLogs navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchLogs(w http.ResponseWriter, r *http.Request, server LogsServer, segments []string) {
+func dispatchLogs(w http.ResponseWriter, r *http.Request, logger logging.Logger, server LogsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptLogsListRequest(w, r, server)
+			adaptLogsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -157,41 +157,34 @@ func dispatchLogs(w http.ResponseWriter, r *http.Request, server LogsServer, seg
 	default:
 		target := server.Log(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchLog(w, r, target, segments[1:])
+		dispatchLog(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptLogsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptLogsListRequest(w http.ResponseWriter, r *http.Request, server LogsServer) {
+func adaptLogsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server LogsServer) {
 	request := &LogsListServerRequest{}
 	err := readLogsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &LogsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeLogsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // MachineTypeServer represents the interface the manages the 'machine_type' resource.
@@ -64,20 +64,20 @@ func (r *MachineTypeGetServerResponse) Status(value int) *MachineTypeGetServerRe
 // dispatchMachineType navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchMachineType(w http.ResponseWriter, r *http.Request, server MachineTypeServer, segments []string) {
+func dispatchMachineType(w http.ResponseWriter, r *http.Request, logger logging.Logger, server MachineTypeServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptMachineTypeGetRequest(w, r, server)
+			adaptMachineTypeGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -85,31 +85,24 @@ func dispatchMachineType(w http.ResponseWriter, r *http.Request, server MachineT
 // adaptMachineTypeGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptMachineTypeGetRequest(w http.ResponseWriter, r *http.Request, server MachineTypeServer) {
+func adaptMachineTypeGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server MachineTypeServer) {
 	request := &MachineTypeGetServerRequest{}
 	err := readMachineTypeGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &MachineTypeGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeMachineTypeGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // MachineTypesServer represents the interface the manages the 'machine_types' resource.
@@ -236,20 +236,20 @@ func (r *MachineTypesListServerResponse) Status(value int) *MachineTypesListServ
 // dispatchMachineTypes navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchMachineTypes(w http.ResponseWriter, r *http.Request, server MachineTypesServer, segments []string) {
+func dispatchMachineTypes(w http.ResponseWriter, r *http.Request, logger logging.Logger, server MachineTypesServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptMachineTypesListRequest(w, r, server)
+			adaptMachineTypesListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -257,31 +257,24 @@ func dispatchMachineTypes(w http.ResponseWriter, r *http.Request, server Machine
 // adaptMachineTypesListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptMachineTypesListRequest(w http.ResponseWriter, r *http.Request, server MachineTypesServer) {
+func adaptMachineTypesListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server MachineTypesServer) {
 	request := &MachineTypesListServerRequest{}
 	err := readMachineTypesListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &MachineTypesListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeMachineTypesListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // MetricQueriesServer represents the interface the manages the 'metric_queries' resource.
@@ -44,11 +45,11 @@ type MetricQueriesServer interface {
 // dispatchMetricQueries navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchMetricQueries(w http.ResponseWriter, r *http.Request, server MetricQueriesServer, segments []string) {
+func dispatchMetricQueries(w http.ResponseWriter, r *http.Request, logger logging.Logger, server MetricQueriesServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -56,19 +57,19 @@ func dispatchMetricQueries(w http.ResponseWriter, r *http.Request, server Metric
 	case "cpu_total_by_node_roles_os":
 		target := server.CPUTotalByNodeRolesOS()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchCPUTotalByNodeRolesOSMetricQuery(w, r, target, segments[1:])
+		dispatchCPUTotalByNodeRolesOSMetricQuery(w, r, logger, target, segments[1:])
 	case "socket_total_by_node_roles_os":
 		target := server.SocketTotalByNodeRolesOS()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchSocketTotalByNodeRolesOSMetricQuery(w, r, target, segments[1:])
+		dispatchSocketTotalByNodeRolesOSMetricQuery(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
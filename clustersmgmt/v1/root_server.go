@@ -23,6 +23,8 @@ import (
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // Server represents the interface the manages the 'root' resource.
@@ -73,11 +75,14 @@ type Server interface {
 // Dispatch navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []string) {
+func Dispatch(w http.ResponseWriter, r *http.Request, logger logging.Logger, server Server, segments []string) {
+	w, r, end := helpers.StartServerSpan(w, r, "/api/clusters_mgmt/v1")
+	defer end()
+	defer errors.RecoverPanic(w, r, logger)
 	if len(segments) == 0 {
 		switch r.Method {
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -85,61 +90,61 @@ func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []
 	case "aws_infrastructure_access_roles":
 		target := server.AWSInfrastructureAccessRoles()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAWSInfrastructureAccessRoles(w, r, target, segments[1:])
+		dispatchAWSInfrastructureAccessRoles(w, r, logger, target, segments[1:])
 	case "addons":
 		target := server.Addons()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchAddOns(w, r, target, segments[1:])
+		dispatchAddOns(w, r, logger, target, segments[1:])
 	case "cloud_providers":
 		target := server.CloudProviders()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchCloudProviders(w, r, target, segments[1:])
+		dispatchCloudProviders(w, r, logger, target, segments[1:])
 	case "clusters":
 		target := server.Clusters()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchClusters(w, r, target, segments[1:])
+		dispatchClusters(w, r, logger, target, segments[1:])
 	case "dashboards":
 		target := server.Dashboards()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchDashboards(w, r, target, segments[1:])
+		dispatchDashboards(w, r, logger, target, segments[1:])
 	case "flavours":
 		target := server.Flavours()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchFlavours(w, r, target, segments[1:])
+		dispatchFlavours(w, r, logger, target, segments[1:])
 	case "machine_types":
 		target := server.MachineTypes()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchMachineTypes(w, r, target, segments[1:])
+		dispatchMachineTypes(w, r, logger, target, segments[1:])
 	case "versions":
 		target := server.Versions()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchVersions(w, r, target, segments[1:])
+		dispatchVersions(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
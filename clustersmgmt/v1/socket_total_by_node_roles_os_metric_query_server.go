@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // SocketTotalByNodeRolesOSMetricQueryServer represents the interface the manages the 'socket_total_by_node_roles_OS_metric_query' resource.
@@ -64,20 +64,20 @@ func (r *SocketTotalByNodeRolesOSMetricQueryGetServerResponse) Status(value int)
 // dispatchSocketTotalByNodeRolesOSMetricQuery navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchSocketTotalByNodeRolesOSMetricQuery(w http.ResponseWriter, r *http.Request, server SocketTotalByNodeRolesOSMetricQueryServer, segments []string) {
+func dispatchSocketTotalByNodeRolesOSMetricQuery(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SocketTotalByNodeRolesOSMetricQueryServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptSocketTotalByNodeRolesOSMetricQueryGetRequest(w, r, server)
+			adaptSocketTotalByNodeRolesOSMetricQueryGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -85,31 +85,24 @@ func dispatchSocketTotalByNodeRolesOSMetricQuery(w http.ResponseWriter, r *http.
 // adaptSocketTotalByNodeRolesOSMetricQueryGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptSocketTotalByNodeRolesOSMetricQueryGetRequest(w http.ResponseWriter, r *http.Request, server SocketTotalByNodeRolesOSMetricQueryServer) {
+func adaptSocketTotalByNodeRolesOSMetricQueryGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server SocketTotalByNodeRolesOSMetricQueryServer) {
 	request := &SocketTotalByNodeRolesOSMetricQueryGetServerRequest{}
 	err := readSocketTotalByNodeRolesOSMetricQueryGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &SocketTotalByNodeRolesOSMetricQueryGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeSocketTotalByNodeRolesOSMetricQueryGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
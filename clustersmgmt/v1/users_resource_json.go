@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readUsersAddRequest(request *UsersAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalUser(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, userValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalUser(body)
 	return err
 }
 func writeUsersAddRequest(request *UsersAddRequest, writer io.Writer) error {
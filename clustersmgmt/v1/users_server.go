@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // UsersServer represents the interface the manages the 'users' resource.
@@ -195,17 +195,17 @@ func (r *UsersListServerResponse) Status(value int) *UsersListServerResponse {
 // dispatchUsers navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchUsers(w http.ResponseWriter, r *http.Request, server UsersServer, segments []string) {
+func dispatchUsers(w http.ResponseWriter, r *http.Request, logger logging.Logger, server UsersServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptUsersAddRequest(w, r, server)
+			adaptUsersAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptUsersListRequest(w, r, server)
+			adaptUsersListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -213,41 +213,34 @@ func dispatchUsers(w http.ResponseWriter, r *http.Request, server UsersServer, s
 	default:
 		target := server.User(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchUser(w, r, target, segments[1:])
+		dispatchUser(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptUsersAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptUsersAddRequest(w http.ResponseWriter, r *http.Request, server UsersServer) {
+func adaptUsersAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server UsersServer) {
 	request := &UsersAddServerRequest{}
 	err := readUsersAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &UsersAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeUsersAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -258,31 +251,24 @@ func adaptUsersAddRequest(w http.ResponseWriter, r *http.Request, server UsersSe
 // adaptUsersListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptUsersListRequest(w http.ResponseWriter, r *http.Request, server UsersServer) {
+func adaptUsersListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server UsersServer) {
 	request := &UsersListServerRequest{}
 	err := readUsersListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &UsersListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeUsersListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -0,0 +1,1198 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// IMPORTANT: This file has been generated automatically, refrain from modifying it manually as all
+// your changes will be lost when the file is generated again.
+
+package v1 // github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1
+
+import (
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+)
+
+// clusterStateValidation describes the 'cluster_state' enumerated type.
+var clusterStateValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationEnum,
+	Values: []string{
+		"error",
+		"installing",
+		"pending",
+		"pending_account",
+		"ready",
+		"uninstalling",
+		"unknown",
+	},
+}
+
+// identityProviderMappingMethodValidation describes the 'identity_provider_mapping_method' enumerated type.
+var identityProviderMappingMethodValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationEnum,
+	Values: []string{
+		"add",
+		"claim",
+		"generate",
+		"lookup",
+	},
+}
+
+// identityProviderTypeValidation describes the 'identity_provider_type' enumerated type.
+var identityProviderTypeValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationEnum,
+	Values: []string{
+		"ldap",
+		"github",
+		"gitlab",
+		"google",
+		"open_id",
+	},
+}
+
+// awsValidation describes the 'AWS' type.
+var awsValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// awsFlavourValidation describes the 'AWS_flavour' type.
+var awsFlavourValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// awsInfrastructureAccessRoleValidation describes the 'AWS_infrastructure_access_role' type.
+var awsInfrastructureAccessRoleValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// awsVolumeValidation describes the 'AWS_volume' type.
+var awsVolumeValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// cpuTotalNodeRoleOSMetricNodeValidation describes the 'CPU_total_node_role_OS_metric_node' type.
+var cpuTotalNodeRoleOSMetricNodeValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// cpuTotalsNodeRoleOSMetricNodeValidation describes the 'CPU_totals_node_role_OS_metric_node' type.
+var cpuTotalsNodeRoleOSMetricNodeValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// dnsValidation describes the 'DNS' type.
+var dnsValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// gcpFlavourValidation describes the 'GCP_flavour' type.
+var gcpFlavourValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// ldapAttributesValidation describes the 'LDAP_attributes' type.
+var ldapAttributesValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// ldapIdentityProviderValidation describes the 'LDAP_identity_provider' type.
+var ldapIdentityProviderValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// sshCredentialsValidation describes the 'SSH_credentials' type.
+var sshCredentialsValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// addOnValidation describes the 'add_on' type.
+var addOnValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// addOnInstallationValidation describes the 'add_on_installation' type.
+var addOnInstallationValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// adminCredentialsValidation describes the 'admin_credentials' type.
+var adminCredentialsValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// cloudProviderValidation describes the 'cloud_provider' type.
+var cloudProviderValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// cloudRegionValidation describes the 'cloud_region' type.
+var cloudRegionValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterValidation describes the 'cluster' type.
+var clusterValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterAPIValidation describes the 'cluster_API' type.
+var clusterAPIValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterConsoleValidation describes the 'cluster_console' type.
+var clusterConsoleValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterCredentialsValidation describes the 'cluster_credentials' type.
+var clusterCredentialsValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterMetricValidation describes the 'cluster_metric' type.
+var clusterMetricValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterMetricsValidation describes the 'cluster_metrics' type.
+var clusterMetricsValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterNodesValidation describes the 'cluster_nodes' type.
+var clusterNodesValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterRegistrationValidation describes the 'cluster_registration' type.
+var clusterRegistrationValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// clusterStatusValidation describes the 'cluster_status' type.
+var clusterStatusValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// dashboardValidation describes the 'dashboard' type.
+var dashboardValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// flavourValidation describes the 'flavour' type.
+var flavourValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// flavourNodesValidation describes the 'flavour_nodes' type.
+var flavourNodesValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// githubIdentityProviderValidation describes the 'github_identity_provider' type.
+var githubIdentityProviderValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// gitlabIdentityProviderValidation describes the 'gitlab_identity_provider' type.
+var gitlabIdentityProviderValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// googleIdentityProviderValidation describes the 'google_identity_provider' type.
+var googleIdentityProviderValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// groupValidation describes the 'group' type.
+var groupValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// identityProviderValidation describes the 'identity_provider' type.
+var identityProviderValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// logValidation describes the 'log' type.
+var logValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// machineTypeValidation describes the 'machine_type' type.
+var machineTypeValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// metricValidation describes the 'metric' type.
+var metricValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// networkValidation describes the 'network' type.
+var networkValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// openIDClaimsValidation describes the 'open_ID_claims' type.
+var openIDClaimsValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// openIDIdentityProviderValidation describes the 'open_ID_identity_provider' type.
+var openIDIdentityProviderValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// openIDURLsValidation describes the 'open_IDURLs' type.
+var openIDURLsValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// sampleValidation describes the 'sample' type.
+var sampleValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// socketTotalNodeRoleOSMetricNodeValidation describes the 'socket_total_node_role_OS_metric_node' type.
+var socketTotalNodeRoleOSMetricNodeValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// socketTotalsNodeRoleOSMetricNodeValidation describes the 'socket_totals_node_role_OS_metric_node' type.
+var socketTotalsNodeRoleOSMetricNodeValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// subscriptionValidation describes the 'subscription' type.
+var subscriptionValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// userValidation describes the 'user' type.
+var userValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// valueValidation describes the 'value' type.
+var valueValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// versionValidation describes the 'version' type.
+var versionValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// init sets the attributes of the descriptions of the object types. This isn't done in the
+// declarations because the types may refer to each other.
+func init() {
+	awsValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"access_key_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"account_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"secret_access_key": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	awsFlavourValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"compute_instance_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"infra_instance_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"infra_volume": {
+			Type: awsVolumeValidation,
+		},
+		"master_instance_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"master_volume": {
+			Type: awsVolumeValidation,
+		},
+		"worker_volume": {
+			Type: awsVolumeValidation,
+		},
+	}
+	awsInfrastructureAccessRoleValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"description": {
+			Type: helpers.ValidationStringType,
+		},
+		"display_name": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	awsVolumeValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"iops": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"size": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"type": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	cpuTotalNodeRoleOSMetricNodeValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"cpu_total": {
+			Type: helpers.ValidationFloatType,
+		},
+		"node_roles": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"operating_system": {
+			Type: helpers.ValidationStringType,
+		},
+		"time": {
+			Type: helpers.ValidationDateType,
+		},
+	}
+	cpuTotalsNodeRoleOSMetricNodeValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"cpu_totals": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: cpuTotalNodeRoleOSMetricNodeValidation,
+			},
+		},
+	}
+	dnsValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"base_domain": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	gcpFlavourValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"compute_instance_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"infra_instance_type": {
+			Type: helpers.ValidationStringType,
+		},
+		"master_instance_type": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	ldapAttributesValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"id": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"email": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"name": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"preferred_username": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+	}
+	ldapIdentityProviderValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"ca": {
+			Type: helpers.ValidationStringType,
+		},
+		"ldap_attributes": {
+			Type: ldapAttributesValidation,
+		},
+		"url": {
+			Type: helpers.ValidationStringType,
+		},
+		"bind_dn": {
+			Type: helpers.ValidationStringType,
+		},
+		"bind_password": {
+			Type: helpers.ValidationStringType,
+		},
+		"insecure": {
+			Type: helpers.ValidationBooleanType,
+		},
+	}
+	sshCredentialsValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"private_key": {
+			Type: helpers.ValidationStringType,
+		},
+		"public_key": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	addOnValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type: helpers.ValidationStringType,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"description": {
+			Type: helpers.ValidationStringType,
+		},
+		"enabled": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"icon": {
+			Type: helpers.ValidationStringType,
+		},
+		"label": {
+			Type: helpers.ValidationStringType,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+		"resource_cost": {
+			Type: helpers.ValidationFloatType,
+		},
+		"resource_name": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	addOnInstallationValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"addon": {
+			Type: addOnValidation,
+		},
+		"cluster": {
+			Type: clusterValidation,
+		},
+	}
+	adminCredentialsValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"password": {
+			Type: helpers.ValidationStringType,
+		},
+		"user": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	cloudProviderValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"display_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	cloudRegionValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"cloud_provider": {
+			Type: cloudProviderValidation,
+		},
+		"display_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	clusterValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"api": {
+			Type: clusterAPIValidation,
+		},
+		"aws": {
+			Type: awsValidation,
+		},
+		"byoc": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"dns": {
+			Type: dnsValidation,
+		},
+		"addons": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationObject,
+				Attributes: map[string]*helpers.ValidationAttribute{
+					"kind": {
+						Type: helpers.ValidationStringType,
+					},
+					"href": {
+						Type: helpers.ValidationStringType,
+					},
+					"items": {
+						Type: &helpers.ValidationType{
+							Kind: helpers.ValidationList,
+							Item: addOnInstallationValidation,
+						},
+					},
+				},
+			},
+		},
+		"cloud_provider": {
+			Type: cloudProviderValidation,
+		},
+		"console": {
+			Type: clusterConsoleValidation,
+		},
+		"creation_timestamp": {
+			Type:     helpers.ValidationDateType,
+			ReadOnly: true,
+		},
+		"display_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"expiration_timestamp": {
+			Type: helpers.ValidationDateType,
+		},
+		"external_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"flavour": {
+			Type: flavourValidation,
+		},
+		"groups": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationObject,
+				Attributes: map[string]*helpers.ValidationAttribute{
+					"kind": {
+						Type: helpers.ValidationStringType,
+					},
+					"href": {
+						Type: helpers.ValidationStringType,
+					},
+					"items": {
+						Type: &helpers.ValidationType{
+							Kind: helpers.ValidationList,
+							Item: groupValidation,
+						},
+					},
+				},
+			},
+		},
+		"identity_providers": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationObject,
+				Attributes: map[string]*helpers.ValidationAttribute{
+					"kind": {
+						Type: helpers.ValidationStringType,
+					},
+					"href": {
+						Type: helpers.ValidationStringType,
+					},
+					"items": {
+						Type: &helpers.ValidationType{
+							Kind: helpers.ValidationList,
+							Item: identityProviderValidation,
+						},
+					},
+				},
+			},
+		},
+		"load_balancer_quota": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"managed": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"metrics": {
+			Type: clusterMetricsValidation,
+		},
+		"multi_az": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+		"network": {
+			Type: networkValidation,
+		},
+		"nodes": {
+			Type: clusterNodesValidation,
+		},
+		"openshift_version": {
+			Type: helpers.ValidationStringType,
+		},
+		"properties": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationMap,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"region": {
+			Type:     cloudRegionValidation,
+			Required: true,
+		},
+		"state": {
+			Type: clusterStateValidation,
+		},
+		"storage_quota": {
+			Type: valueValidation,
+		},
+		"subscription": {
+			Type: subscriptionValidation,
+		},
+		"version": {
+			Type: versionValidation,
+		},
+	}
+	clusterAPIValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"url": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	clusterConsoleValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"url": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	clusterCredentialsValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"ssh": {
+			Type: sshCredentialsValidation,
+		},
+		"admin": {
+			Type: adminCredentialsValidation,
+		},
+		"kubeconfig": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	clusterMetricValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"total": {
+			Type: valueValidation,
+		},
+		"updated_timestamp": {
+			Type: helpers.ValidationDateType,
+		},
+		"used": {
+			Type: valueValidation,
+		},
+	}
+	clusterMetricsValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"cpu": {
+			Type: clusterMetricValidation,
+		},
+		"compute_nodes_cpu": {
+			Type: clusterMetricValidation,
+		},
+		"compute_nodes_memory": {
+			Type: clusterMetricValidation,
+		},
+		"memory": {
+			Type: clusterMetricValidation,
+		},
+		"nodes": {
+			Type: clusterNodesValidation,
+		},
+		"storage": {
+			Type: clusterMetricValidation,
+		},
+	}
+	clusterNodesValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"compute": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"infra": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"master": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"total": {
+			Type: helpers.ValidationIntegerType,
+		},
+	}
+	clusterRegistrationValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"external_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"subscription_id": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	clusterStatusValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"description": {
+			Type: helpers.ValidationStringType,
+		},
+		"state": {
+			Type: clusterStateValidation,
+		},
+	}
+	dashboardValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"metrics": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: metricValidation,
+			},
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	flavourValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type: helpers.ValidationStringType,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"aws": {
+			Type: awsFlavourValidation,
+		},
+		"gcp": {
+			Type: gcpFlavourValidation,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+		"network": {
+			Type: networkValidation,
+		},
+		"nodes": {
+			Type: flavourNodesValidation,
+		},
+	}
+	flavourNodesValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"compute": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"infra": {
+			Type: helpers.ValidationIntegerType,
+		},
+		"master": {
+			Type: helpers.ValidationIntegerType,
+		},
+	}
+	githubIdentityProviderValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"ca": {
+			Type: helpers.ValidationStringType,
+		},
+		"client_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"hostname": {
+			Type: helpers.ValidationStringType,
+		},
+		"teams": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+	}
+	gitlabIdentityProviderValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"ca": {
+			Type: helpers.ValidationStringType,
+		},
+		"url": {
+			Type: helpers.ValidationStringType,
+		},
+		"client_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"client_secret": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	googleIdentityProviderValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"client_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"client_secret": {
+			Type: helpers.ValidationStringType,
+		},
+		"hosted_domain": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	groupValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"users": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationObject,
+				Attributes: map[string]*helpers.ValidationAttribute{
+					"kind": {
+						Type: helpers.ValidationStringType,
+					},
+					"href": {
+						Type: helpers.ValidationStringType,
+					},
+					"items": {
+						Type: &helpers.ValidationType{
+							Kind: helpers.ValidationList,
+							Item: userValidation,
+						},
+					},
+				},
+			},
+		},
+	}
+	identityProviderValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"ldap": {
+			Type: ldapIdentityProviderValidation,
+		},
+		"challenge": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"github": {
+			Type: githubIdentityProviderValidation,
+		},
+		"gitlab": {
+			Type: gitlabIdentityProviderValidation,
+		},
+		"google": {
+			Type: googleIdentityProviderValidation,
+		},
+		"login": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"mapping_method": {
+			Type: identityProviderMappingMethodValidation,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+		"open_id": {
+			Type: openIDIdentityProviderValidation,
+		},
+		"type": {
+			Type: identityProviderTypeValidation,
+		},
+	}
+	logValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"content": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	machineTypeValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"cpu": {
+			Type: valueValidation,
+		},
+		"cloud_provider": {
+			Type: cloudProviderValidation,
+		},
+		"memory": {
+			Type: valueValidation,
+		},
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	metricValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"name": {
+			Type: helpers.ValidationStringType,
+		},
+		"vector": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: sampleValidation,
+			},
+		},
+	}
+	networkValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"machine_cidr": {
+			Type: helpers.ValidationStringType,
+		},
+		"pod_cidr": {
+			Type: helpers.ValidationStringType,
+		},
+		"service_cidr": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	openIDClaimsValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"email": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"name": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"preferred_username": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+	}
+	openIDIdentityProviderValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"ca": {
+			Type: helpers.ValidationStringType,
+		},
+		"urls": {
+			Type: openIDURLsValidation,
+		},
+		"claims": {
+			Type: openIDClaimsValidation,
+		},
+		"client_id": {
+			Type: helpers.ValidationStringType,
+		},
+		"client_secret": {
+			Type: helpers.ValidationStringType,
+		},
+		"extra_authorize_parameters": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationMap,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"extra_scopes": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+	}
+	openIDURLsValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"authorize": {
+			Type: helpers.ValidationStringType,
+		},
+		"token": {
+			Type: helpers.ValidationStringType,
+		},
+		"user_info": {
+			Type: helpers.ValidationStringType,
+		},
+	}
+	sampleValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"time": {
+			Type: helpers.ValidationDateType,
+		},
+		"value": {
+			Type: helpers.ValidationFloatType,
+		},
+	}
+	socketTotalNodeRoleOSMetricNodeValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"node_roles": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: helpers.ValidationStringType,
+			},
+		},
+		"operating_system": {
+			Type: helpers.ValidationStringType,
+		},
+		"socket_total": {
+			Type: helpers.ValidationFloatType,
+		},
+		"time": {
+			Type: helpers.ValidationDateType,
+		},
+	}
+	socketTotalsNodeRoleOSMetricNodeValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"socket_totals": {
+			Type: &helpers.ValidationType{
+				Kind: helpers.ValidationList,
+				Item: socketTotalNodeRoleOSMetricNodeValidation,
+			},
+		},
+	}
+	subscriptionValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+	}
+	userValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			Required: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+	}
+	valueValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"unit": {
+			Type: helpers.ValidationStringType,
+		},
+		"value": {
+			Type: helpers.ValidationFloatType,
+		},
+	}
+	versionValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"default": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"enabled": {
+			Type: helpers.ValidationBooleanType,
+		},
+	}
+}
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // VersionsServer represents the interface the manages the 'versions' resource.
@@ -247,14 +247,14 @@ func (r *VersionsListServerResponse) Status(value int) *VersionsListServerRespon
 // dispatchVersions navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchVersions(w http.ResponseWriter, r *http.Request, server VersionsServer, segments []string) {
+func dispatchVersions(w http.ResponseWriter, r *http.Request, logger logging.Logger, server VersionsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "GET":
-			adaptVersionsListRequest(w, r, server)
+			adaptVersionsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -262,41 +262,34 @@ func dispatchVersions(w http.ResponseWriter, r *http.Request, server VersionsSer
 	default:
 		target := server.Version(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchVersion(w, r, target, segments[1:])
+		dispatchVersion(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptVersionsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptVersionsListRequest(w http.ResponseWriter, r *http.Request, server VersionsServer) {
+func adaptVersionsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server VersionsServer) {
 	request := &VersionsListServerRequest{}
 	err := readVersionsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &VersionsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeVersionsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -20,20 +20,25 @@ package sdk
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/propagators"
 
 	"github.com/openshift-online/ocm-sdk-go/accountsmgmt"
 	"github.com/openshift-online/ocm-sdk-go/authorizations"
 	"github.com/openshift-online/ocm-sdk-go/clustersmgmt"
+	"github.com/openshift-online/ocm-sdk-go/internal"
 	"github.com/openshift-online/ocm-sdk-go/servicelogs"
 )
 
@@ -45,6 +50,16 @@ const (
 	DefaultClientSecret = ""
 	DefaultURL          = "https://api.openshift.com"
 	DefaultAgent        = "OCM/" + Version
+
+	DefaultRetryLimit       = 0
+	DefaultRetryInterval    = 1 * time.Second
+	DefaultRetryMaxInterval = 30 * time.Second
+	DefaultRetryJitter      = 0.2
+
+	DefaultDialTimeout         = 30 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultMaxIdleConnsPerHost = 2
 )
 
 // DefaultScopes is the ser of scopes used by default:
@@ -69,9 +84,56 @@ type ConnectionBuilder struct {
 	password     string
 	tokens       []string
 	scopes       []string
+	tokenSource  TokenSource
+	tokenCache   string
+	verifyTokens bool
+	issuer       string
+
+	// Device authorization grant:
+	deviceHandler DeviceAuthorizationHandler
+	deviceURL     string
+
+	// Authorization code grant:
+	codeHandler AuthorizationCodeHandler
+	codeURL     string
+
+	// Retry:
+	retryLimit         int
+	retryInterval      time.Duration
+	retryMaxInterval   time.Duration
+	retryJitter        float64
+	retryNonIdempotent bool
+
+	// Token renewal:
+	tokenRenewalFraction float64
 
 	// Metrics:
 	subsystem string
+
+	// Bearer token forwarding:
+	bearerForwarder BearerFunc
+
+	// Transport:
+	transport             http.RoundTripper
+	timeout               time.Duration
+	dialTimeout           time.Duration
+	tlsHandshakeTimeout   time.Duration
+	responseHeaderTimeout time.Duration
+	idleConnTimeout       time.Duration
+	maxIdleConnsPerHost   int
+	proxyURL              string
+	proxyFromEnvironment  bool
+	clientCertFile        string
+	clientKeyFile         string
+	transportWrappers     []TransportWrapper
+
+	// Limits:
+	rateLimits        map[string]rateLimit
+	concurrencyLimits map[string]int
+
+	// Tracing:
+	tracerProvider  trace.TracerProvider
+	tracePropagator otel.TextMapPropagator
 }
 
 // Connection contains the data needed to connect to the `api.openshift.com`. Don't create instances
@@ -83,6 +145,7 @@ type Connection struct {
 	trustedCAs   *x509.CertPool
 	insecure     bool
 	client       *http.Client
+	transport    http.RoundTripper
 	tokenURL     *url.URL
 	clientID     string
 	clientSecret string
@@ -91,22 +154,73 @@ type Connection struct {
 	user         string
 	password     string
 	tokenMutex   *sync.Mutex
+	tokenCall    *tokenCall
 	tokenParser  *jwt.Parser
 	accessToken  *jwt.Token
 	refreshToken *jwt.Token
 	scopes       []string
+	tokenSource  TokenSource
+
+	// Token cache and verification:
+	tokenCache     *tokenCache
+	tokenCachePath string
+	tokenVerifier  *tokenVerifier
+
+	// Device authorization grant:
+	deviceHandler DeviceAuthorizationHandler
+	deviceURL     *url.URL
+
+	// Authorization code grant:
+	codeHandler AuthorizationCodeHandler
+	codeURL     *url.URL
+
+	// Retry:
+	retryLimit         int
+	retryInterval      time.Duration
+	retryMaxInterval   time.Duration
+	retryJitter        float64
+	retryNonIdempotent bool
+
+	// Token renewal:
+	tokenRenewalFraction float64
+	renewerStop          chan struct{}
+	renewerWake          chan struct{}
+	renewerDone          chan struct{}
+	renewerCancel        context.CancelFunc
 
 	// Metrics:
 	tokenCountMetric    *prometheus.CounterVec
+	tokenEventMetric    *prometheus.CounterVec
 	tokenDurationMetric *prometheus.HistogramVec
 	callCountMetric     *prometheus.CounterVec
 	callDurationMetric  *prometheus.HistogramVec
+	waitDurationMetric  *prometheus.HistogramVec
+
+	// Bearer token forwarding:
+	bearerForwarder BearerFunc
+
+	// Limits:
+	limiters map[string]*requestLimiter
+
+	// Tracing:
+	tracer          trace.Tracer
+	tracePropagator otel.TextMapPropagator
 }
 
 // NewConnectionBuilder creates an builder that knows how to create connections with the default
 // configuration.
 func NewConnectionBuilder() *ConnectionBuilder {
-	return new(ConnectionBuilder)
+	return &ConnectionBuilder{
+		retryLimit:       DefaultRetryLimit,
+		retryInterval:    DefaultRetryInterval,
+		retryMaxInterval: DefaultRetryMaxInterval,
+		retryJitter:      DefaultRetryJitter,
+
+		dialTimeout:         DefaultDialTimeout,
+		tlsHandshakeTimeout: DefaultTLSHandshakeTimeout,
+		idleConnTimeout:     DefaultIdleConnTimeout,
+		maxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+	}
 }
 
 // Logger sets the logger that will be used by the connection. By default it uses the Go `log`
@@ -240,6 +354,143 @@ func (b *ConnectionBuilder) Tokens(tokens ...string) *ConnectionBuilder {
 	return b
 }
 
+// TokenSource sets the object that will be used to obtain access tokens, replacing the built-in
+// support for the OpenID grants. This is useful when tokens are managed by some other component,
+// for example a secrets vault, a sidecar that rotates a file or an external command. The SDK
+// provides token sources for some of these cases, for example, to use a token that is stored in a
+// file that is periodically rotated:
+//
+//	// Create a connection that reads the token from a file:
+//	connection, err := client.NewConnectionBuilder().
+//		TokenSource(client.NewFileTokenSource("/var/run/secrets/ocm/token")).
+//		Build()
+//
+// When a token source is used the user name, password, client credentials and tokens given to
+// the builder are ignored. The tokens returned by the source are handled like the tokens obtained
+// with the OpenID grants: they are saved to the token cache, verified and renewed in the background
+// if those features are enabled.
+func (b *ConnectionBuilder) TokenSource(value TokenSource) *ConnectionBuilder {
+	b.tokenSource = value
+	return b
+}
+
+// Issuer sets the URL of the OpenID provider. When this is set the connection will use OpenID
+// discovery, loading the `/.well-known/openid-configuration` document of the issuer, to find the
+// token, authorization, device authorization and keys endpoints, and to check that the grants that
+// it needs are supported. This makes it possible to switch between SSO servers or realms changing
+// only this setting. For example:
+//
+//	// Create a connection that uses the staging SSO realm:
+//	connection, err := client.NewConnectionBuilder().
+//		Issuer("https://sso.stage.redhat.com/auth/realms/redhat-external").
+//		Tokens(token).
+//		Build()
+//
+// URLs explicitly set with other methods, like TokenURL, take precedence over the discovered ones.
+// The discovery documents are cached, so creating multiple connections for the same issuer sends
+// only one request.
+func (b *ConnectionBuilder) Issuer(url string) *ConnectionBuilder {
+	b.issuer = url
+	return b
+}
+
+// TokenCache sets the path of a file where the connection will store the tokens that it obtains,
+// so that they can be reused by other connections created with the same token URL, client
+// identifier, user name, scopes and, when tokens are given to the builder, subject of those tokens,
+// even if they are in different processes. This is useful for
+// tools that create many short lived processes, as it avoids requesting new tokens in each of
+// them. For example:
+//
+//	// Create a connection that shares tokens with other processes:
+//	connection, err := client.NewConnectionBuilder().
+//		Client("myclient", "mysecret").
+//		TokenCache("/home/myuser/.cache/ocm/tokens.json").
+//		Build()
+//
+// The file is created with permissions that allow access only to the owner, as it contains
+// credentials. Concurrent access from multiple processes is serialized using file locks. When a
+// token source is used the tokens are shared with other connections that obtained tokens for the
+// same subject.
+func (b *ConnectionBuilder) TokenCache(path string) *ConnectionBuilder {
+	b.tokenCache = path
+	return b
+}
+
+// VerifyTokens enables or disables the verification of the access tokens obtained by the
+// connection. When enabled the connection will download the JSON web key set of the issuer, using
+// OpenID discovery, and will check the signature, the issuer, the audience and the expiration time
+// of the access tokens given to the builder and of the access tokens returned by the token
+// endpoint. This protects against misconfigured or spoofed token endpoints. The issuer is
+// calculated from the token URL, removing the `/protocol/openid-connect/token` suffix. The default
+// is to not verify tokens.
+//
+// The access tokens given to the builder with the Tokens method are verified when the connection
+// is built, but their expiration time isn't checked: an expired token given to the builder is
+// accepted, and will be replaced with a new one when it is needed.
+//
+// Note that refresh tokens are never verified, as they are intended only for the server and are
+// frequently signed with keys that aren't published.
+func (b *ConnectionBuilder) VerifyTokens(value bool) *ConnectionBuilder {
+	b.verifyTokens = value
+	return b
+}
+
+// DeviceCode enables the OAuth device authorization grant, intended for interactive command line
+// tools that run in environments where a browser isn't available. When the connection needs new
+// tokens and it doesn't have a valid refresh token it will request a device code and call the given
+// handler, which should show the user the verification URL and the user code. For example:
+//
+//	// Create a connection that uses the device authorization grant:
+//	connection, err := client.NewConnectionBuilder().
+//		DeviceCode(func(ctx context.Context, auth *client.DeviceAuthorization) error {
+//			fmt.Printf("Open %s and enter code %s\n", auth.VerificationURI, auth.UserCode)
+//			return nil
+//		}).
+//		Build()
+//
+// After calling the handler the connection polls the token endpoint till the user completes the
+// verification, till the device code expires or till the context is cancelled.
+func (b *ConnectionBuilder) DeviceCode(handler DeviceAuthorizationHandler) *ConnectionBuilder {
+	b.deviceHandler = handler
+	return b
+}
+
+// DeviceAuthorizationURL sets the URL of the endpoint used to request device codes. If this isn't
+// explicitly specified it will be calculated from the token URL, replacing the trailing `/token`
+// with `/auth/device`, as that is what the SSO server uses.
+func (b *ConnectionBuilder) DeviceAuthorizationURL(url string) *ConnectionBuilder {
+	b.deviceURL = url
+	return b
+}
+
+// AuthorizationCode enables the OAuth authorization code grant with proof key for code exchange,
+// intended for developer tools that can open a browser. When the connection needs new tokens and it
+// doesn't have a valid refresh token it will start a temporary HTTP listener on the loopback
+// interface and call the given handler with the authorization URL. The handler should open that
+// URL with a browser. For example:
+//
+//	// Create a connection that uses the authorization code grant:
+//	connection, err := client.NewConnectionBuilder().
+//		AuthorizationCode(func(ctx context.Context, url string) error {
+//			return exec.Command("xdg-open", url).Run()
+//		}).
+//		Build()
+//
+// When the user completes the login the authorization server redirects the browser to the
+// listener, and the connection exchanges the received code for tokens.
+func (b *ConnectionBuilder) AuthorizationCode(handler AuthorizationCodeHandler) *ConnectionBuilder {
+	b.codeHandler = handler
+	return b
+}
+
+// AuthorizationURL sets the URL of the authorization endpoint used by the authorization code
+// grant. If this isn't explicitly specified it will be calculated from the token URL, replacing
+// the trailing `/token` with `/auth`, as that is what the SSO server uses.
+func (b *ConnectionBuilder) AuthorizationURL(url string) *ConnectionBuilder {
+	b.codeURL = url
+	return b
+}
+
 // TrustedCAs sets the certificate pool that contains the certificate authorities that will be
 // trusted by the connection. If this isn't explicitly specified then the client will trust the
 // certificate authorities trusted by default by the system.
@@ -255,6 +506,190 @@ func (b *ConnectionBuilder) Insecure(flag bool) *ConnectionBuilder {
 	return b
 }
 
+// Timeout sets the maximum time that a single attempt to send a request and read the response can
+// take, including reading the response body. The default is zero, which means no limit. Note that
+// the context passed to the SendContext methods can also be used to limit the time of requests.
+func (b *ConnectionBuilder) Timeout(value time.Duration) *ConnectionBuilder {
+	b.timeout = value
+	return b
+}
+
+// DialTimeout sets the maximum time to wait for a TCP connection to be established. The default is
+// thirty seconds.
+func (b *ConnectionBuilder) DialTimeout(value time.Duration) *ConnectionBuilder {
+	b.dialTimeout = value
+	return b
+}
+
+// TLSHandshakeTimeout sets the maximum time to wait for the TLS handshake to complete. The default
+// is ten seconds.
+func (b *ConnectionBuilder) TLSHandshakeTimeout(value time.Duration) *ConnectionBuilder {
+	b.tlsHandshakeTimeout = value
+	return b
+}
+
+// ResponseHeaderTimeout sets the maximum time to wait for the headers of the response after
+// sending the request. The default is zero, which means no limit.
+func (b *ConnectionBuilder) ResponseHeaderTimeout(value time.Duration) *ConnectionBuilder {
+	b.responseHeaderTimeout = value
+	return b
+}
+
+// IdleConnTimeout sets the maximum time that idle connections will be kept open. The default is
+// ninety seconds.
+func (b *ConnectionBuilder) IdleConnTimeout(value time.Duration) *ConnectionBuilder {
+	b.idleConnTimeout = value
+	return b
+}
+
+// MaxIdleConnsPerHost sets the maximum number of idle connections that will be kept open for each
+// host. The default is two.
+func (b *ConnectionBuilder) MaxIdleConnsPerHost(value int) *ConnectionBuilder {
+	b.maxIdleConnsPerHost = value
+	return b
+}
+
+// ProxyURL sets the URL of the proxy server that will be used for all the requests, for example
+// `http://proxy.example.com:3128`. This takes precedence over the environment variables.
+func (b *ConnectionBuilder) ProxyURL(value string) *ConnectionBuilder {
+	b.proxyURL = value
+	return b
+}
+
+// ProxyFromEnvironment sets the flag that indicates if the proxy server should be taken from the
+// `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables. The default is false, so
+// the environment variables are ignored unless this is explicitly enabled.
+func (b *ConnectionBuilder) ProxyFromEnvironment(flag bool) *ConnectionBuilder {
+	b.proxyFromEnvironment = flag
+	return b
+}
+
+// ClientCertificate sets the files containing the PEM encoded certificate and private key that the
+// connection will present to servers that require mutual TLS authentication.
+func (b *ConnectionBuilder) ClientCertificate(certFile, keyFile string) *ConnectionBuilder {
+	b.clientCertFile = certFile
+	b.clientKeyFile = keyFile
+	return b
+}
+
+// Transport sets the HTTP round tripper that will be used to send requests, instead of the one
+// created by the connection. This is intended for tests and for environments with special
+// transport requirements. When this is used the options that configure the transport, like the
+// trusted certificate authorities, the timeouts, the proxy and the client certificate, are
+// ignored, with the exception of the Timeout option. The connection doesn't close the idle
+// connections of this round tripper when it is closed, that is the responsibility of the caller.
+func (b *ConnectionBuilder) Transport(value http.RoundTripper) *ConnectionBuilder {
+	b.transport = value
+	return b
+}
+
+// TransportWrapper adds a function that will be used to wrap the HTTP round tripper used by the
+// connection. This can be used to intercept all the requests sent by the connection, including the
+// requests used to obtain tokens. The wrapped round tripper receives the requests after the
+// authentication and default headers have been added, and once for each retry attempt. For
+// example, to add a request identifier to all the requests:
+//
+//	// Round tripper that adds the request identifier:
+//	type requestIDTransport struct {
+//		next http.RoundTripper
+//	}
+//
+//	func (t *requestIDTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+//		request.Header.Set("X-Request-Id", uuid.New().String())
+//		return t.next.RoundTrip(request)
+//	}
+//
+//	// Create a connection that adds a request identifier:
+//	connection, err := client.NewConnectionBuilder().
+//		Tokens(token).
+//		TransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+//			return &requestIDTransport{next: next}
+//		}).
+//		Build()
+//
+// This method can be called multiple times, and the first wrapper added will be the first one to
+// receive the requests. The anonymized path used for metrics is available to the wrappers via the
+// MetricPathFromContext function.
+func (b *ConnectionBuilder) TransportWrapper(value TransportWrapper) *ConnectionBuilder {
+	if value != nil {
+		b.transportWrappers = append(b.transportWrappers, value)
+	}
+	return b
+}
+
+// RetryLimit sets the maximum number of times that a request will be sent. The default is zero,
+// which means that requests will be sent only once and never retried. For example, to send each
+// request at most three times:
+//
+//	// Create a connection that retries requests:
+//	connection, err := client.NewConnectionBuilder().
+//		Tokens(token).
+//		RetryLimit(3).
+//		Build()
+//
+// Requests are retried when sending them fails, for example because the connection was reset, or
+// when the server responds with one of the 429, 502, 503 or 504 status codes. Only idempotent
+// requests (GET and DELETE) are retried, unless the RetryNonIdempotent method is used to change
+// that.
+func (b *ConnectionBuilder) RetryLimit(value int) *ConnectionBuilder {
+	b.retryLimit = value
+	return b
+}
+
+// RetryInterval sets the time to wait before the first retry. This time is doubled for each
+// additional retry, up to the maximum set with the RetryMaxInterval method. If the server responds
+// with a `Retry-After` header then the value of that header is used instead, but never more than
+// the maximum. The default is one second.
+func (b *ConnectionBuilder) RetryInterval(value time.Duration) *ConnectionBuilder {
+	b.retryInterval = value
+	return b
+}
+
+// RetryMaxInterval sets the maximum time to wait between retries. This also limits the delays
+// requested by the server with the `Retry-After` header. The default is thirty seconds.
+func (b *ConnectionBuilder) RetryMaxInterval(value time.Duration) *ConnectionBuilder {
+	b.retryMaxInterval = value
+	return b
+}
+
+// RetryJitter sets the fraction of the wait time that will be randomly added or subtracted, so
+// that clients that failed at the same time don't retry at the same time. For example, if the
+// value is 0.2 and the wait time is ten seconds the actual wait time will be between eight and
+// twelve seconds. The default is 0.2.
+func (b *ConnectionBuilder) RetryJitter(value float64) *ConnectionBuilder {
+	b.retryJitter = value
+	return b
+}
+
+// RetryNonIdempotent enables retrying requests that aren't idempotent, like POST and PATCH. The
+// default is false, as sending these requests multiple times may have undesired side effects, for
+// example creating two clusters instead of one.
+func (b *ConnectionBuilder) RetryNonIdempotent(flag bool) *ConnectionBuilder {
+	b.retryNonIdempotent = flag
+	return b
+}
+
+// TokenRenewalFraction enables the renewal of tokens in the background, before they expire. The
+// value is the fraction of the life of the access token after which it will be renewed. For
+// example, if the value is 0.8 and the access token is valid for ten minutes, it will be renewed
+// eight minutes after it was issued. This way requests don't need to wait for the token to be
+// renewed when it is about to expire. For example:
+//
+//	// Create a connection that renews tokens in the background:
+//	connection, err := client.NewConnectionBuilder().
+//		Client("myclientid", "myclientsecret").
+//		TokenRenewalFraction(0.8).
+//		Build()
+//
+// The default is zero, which means that tokens are only renewed when a request finds that the
+// access token is expired or about to expire. The renewal is done by the token source of the
+// connection, which is the built-in OpenID token source unless a different one is given with the
+// TokenSource method.
+func (b *ConnectionBuilder) TokenRenewalFraction(value float64) *ConnectionBuilder {
+	b.tokenRenewalFraction = value
+	return b
+}
+
 // Metrics sets the name of the subsystem that will be used by the connection to register metrics
 // with Prometheus. If this isn't explicitly specified, or if it is an empty string, then no metrics
 // will be registered. For example, if the value is `api_outbound` then the following metrics will
@@ -264,10 +699,14 @@ func (b *ConnectionBuilder) Insecure(flag bool) *ConnectionBuilder {
 //	api_outbound_request_duration_sum - Total time to send API requests, in seconds.
 //	api_outbound_request_duration_count - Total number of API requests measured.
 //	api_outbound_request_duration_bucket - Number of API requests organized in buckets.
+//	api_outbound_request_wait_duration_sum - Total time waiting for limits, in seconds.
+//	api_outbound_request_wait_duration_count - Total number of waits measured.
+//	api_outbound_request_wait_duration_bucket - Number of waits organized in buckets.
 //	api_outbound_token_request_count - Number of token requests sent.
 //	api_outbound_token_request_duration_sum - Total time to send token requests, in seconds.
 //	api_outbound_token_request_duration_count - Total number of token requests measured.
 //	api_outbound_token_request_duration_bucket - Number of token requests organized in buckets.
+//	api_outbound_token_event_count - Number of token events.
 //
 // The duration buckets metrics contain an `le` label that indicates the upper bound. For example if
 // the `le` label is `1` then the value will be the number of requests that were processed in less
@@ -275,6 +714,7 @@ func (b *ConnectionBuilder) Insecure(flag bool) *ConnectionBuilder {
 //
 // The API request metrics have the following labels:
 //
+//	attempt - Number of the attempt, starting with 1, for example 2 for the first retry.
 //	method - Name of the HTTP method, for example GET or POST.
 //	path - Request path, for example /api/clusters_mgmt/v1/clusters.
 //	code - HTTP response code, for example 200 or 500.
@@ -289,15 +729,30 @@ func (b *ConnectionBuilder) Insecure(flag bool) *ConnectionBuilder {
 // be replaced by .../clusters/-, and the values will be accumulated. The line returned by the
 // metrics server will be like this:
 //
-//      api_outbound_request_count{code="200",method="GET",path="/api/clusters_mgmt/v1/clusters/-"} 56
+//      api_outbound_request_count{attempt="1",code="200",method="GET",path="/api/clusters_mgmt/v1/clusters/-"} 56
 //
 // The meaning of that is that there were a total of 56 requests to get specific clusters,
 // independently of the specific identifier of the cluster.
 //
+// The wait metrics are only updated when rate or concurrency limits are configured, and they will
+// contain the following labels:
+//
+//      limit - Kind of limit, either `rate` or `concurrency`.
+//      path - Request path, anonymized like in the API request metrics.
+//
 // The token request metrics will contain the following labels:
 //
 //      code - HTTP response code, for example 200 or 500.
 //
+// The token event metrics will contain the following labels:
+//
+//      event - Name of the event, for example `renewal`.
+//
+// The possible events are `renewal` when the tokens have been renewed in the background,
+// `renewal_failure` when renewing them in the background failed, and `shared` when a request
+// needed new tokens and used the results of a token request that was already in progress instead
+// of sending a new one.
+//
 // The value of the `code` label will be zero when sending the request failed without a response
 // code, for example if it wasn't possible to open the connection, or if there was a timeout waiting
 // for the response.
@@ -309,6 +764,111 @@ func (b *ConnectionBuilder) Metrics(value string) *ConnectionBuilder {
 	return b
 }
 
+// ForwardBearer sets a function that will be used to extract from the context of each request a
+// bearer token that will be sent instead of the token of the connection. This is intended for
+// services that receive requests authenticated by the authentication handler and need to send
+// requests to the API on behalf of the caller. For example:
+//
+//	// Create a connection that forwards the bearer token of the caller:
+//	connection, err := client.NewConnectionBuilder().
+//		ForwardBearer(authentication.BearerFromContext).
+//		Build()
+//
+// When the function returns an empty string the token of the connection will be used. If the
+// connection doesn't have its own credentials then requests sent with a context that doesn't
+// contain a bearer token will fail.
+//
+// To forward the bearer token only for some requests use the ContextWithBearer function instead.
+func (b *ConnectionBuilder) ForwardBearer(value BearerFunc) *ConnectionBuilder {
+	b.bearerForwarder = value
+	return b
+}
+
+// RateLimit limits the rate of requests sent by the connection to the given number of requests per
+// second, allowing bursts of up to the given number of requests. Requests that exceed the limit
+// wait till they are allowed, or till their context is cancelled. For example, to send at most ten
+// requests per second, with bursts of up to twenty:
+//
+//	// Create a connection that limits the rate of requests:
+//	connection, err := client.NewConnectionBuilder().
+//		Tokens(token).
+//		RateLimit(10, 20).
+//		Build()
+//
+// Each retry counts as a separate request. By default there is no rate limit.
+func (b *ConnectionBuilder) RateLimit(rate float64, burst int) *ConnectionBuilder {
+	return b.PathRateLimit("", rate, burst)
+}
+
+// PathRateLimit is like RateLimit, but applies only to the requests whose path starts with the
+// given prefix, for example `/api/clusters_mgmt`. This can be called multiple times to configure
+// different limits for different services. When several prefixes match a request only the longest
+// one is used. The limit for all requests, if configured, applies as well.
+func (b *ConnectionBuilder) PathRateLimit(path string, rate float64,
+	burst int) *ConnectionBuilder {
+	if b.rateLimits == nil {
+		b.rateLimits = map[string]rateLimit{}
+	}
+	b.rateLimits[path] = rateLimit{
+		rate:  rate,
+		burst: burst,
+	}
+	return b
+}
+
+// MaxConcurrentRequests limits the number of requests that the connection sends concurrently.
+// Requests that exceed the limit wait till other requests finish, or till their context is
+// cancelled. A request is finished when the body of its response is closed. For example:
+//
+//	// Create a connection that sends at most five requests at the same time:
+//	connection, err := client.NewConnectionBuilder().
+//		Tokens(token).
+//		MaxConcurrentRequests(5).
+//		Build()
+//
+// By default there is no limit.
+func (b *ConnectionBuilder) MaxConcurrentRequests(value int) *ConnectionBuilder {
+	return b.PathMaxConcurrentRequests("", value)
+}
+
+// PathMaxConcurrentRequests is like MaxConcurrentRequests, but applies only to the requests whose
+// path starts with the given prefix, for example `/api/accounts_mgmt`. This can be called multiple
+// times to configure different limits for different services. When several prefixes match a
+// request only the longest one is used. The limit for all requests, if configured, applies as well.
+func (b *ConnectionBuilder) PathMaxConcurrentRequests(path string, value int) *ConnectionBuilder {
+	if b.concurrencyLimits == nil {
+		b.concurrencyLimits = map[string]int{}
+	}
+	b.concurrencyLimits[path] = value
+	return b
+}
+
+// TracerProvider sets the OpenTelemetry tracer provider that will be used to create spans for the
+// requests sent by the connection. There will be one span for each attempt to send an API request,
+// named after the anonymized path used for metrics, and one span for each token request. For
+// example:
+//
+//	// Create a connection that creates spans with a specific provider:
+//	connection, err := client.NewConnectionBuilder().
+//		Tokens(token).
+//		TracerProvider(provider).
+//		Build()
+//
+// The default is to use the global tracer provider, which doesn't create any spans unless it has
+// been configured with the SetTracerProvider function of the OpenTelemetry global package.
+func (b *ConnectionBuilder) TracerProvider(value trace.TracerProvider) *ConnectionBuilder {
+	b.tracerProvider = value
+	return b
+}
+
+// TracePropagator sets the OpenTelemetry propagator that will be used to add the trace context to
+// the headers of the requests sent by the connection. The default is to use the W3C trace context
+// propagator, which adds the `traceparent` and `tracestate` headers.
+func (b *ConnectionBuilder) TracePropagator(value otel.TextMapPropagator) *ConnectionBuilder {
+	b.tracePropagator = value
+	return b
+}
+
 // Build uses the configuration stored in the builder to create a new connection. The builder can be
 // reused to create multiple connections with the same configuration. It returns a pointer to the
 // connection, and an error if something fails when trying to create it.
@@ -324,13 +884,19 @@ func (b *ConnectionBuilder) Build() (connection *Connection, err error) {
 // the connection, and an error if something fails when trying to create it.
 func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Connection, err error) {
 	// Check that we have some kind of credentials or a token:
+	haveSource := b.tokenSource != nil
 	haveTokens := len(b.tokens) > 0
 	havePassword := b.user != "" && b.password != ""
 	haveSecret := b.clientID != "" && b.clientSecret != ""
-	if !haveTokens && !havePassword && !haveSecret {
+	haveDevice := b.deviceHandler != nil
+	haveCode := b.codeHandler != nil
+	haveForwarder := b.bearerForwarder != nil
+	if !haveSource && !haveTokens && !havePassword && !haveSecret && !haveDevice && !haveCode &&
+		!haveForwarder {
 		err = fmt.Errorf(
-			"either a token, and user name and password or a client identifier and secret are " +
-				"necessary, but none has been provided",
+			"either a token source, a token, a device code or authorization code " +
+				"handler, a user name and password, a client identifier and secret " +
+				"or a bearer forwarder are necessary, but none has been provided",
 		)
 		return
 	}
@@ -339,7 +905,11 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 	tokenParser := new(jwt.Parser)
 	var accessToken *jwt.Token
 	var refreshToken *jwt.Token
-	for i, text := range b.tokens {
+	tokens := b.tokens
+	if b.tokenSource != nil {
+		tokens = nil
+	}
+	for i, text := range tokens {
 		var token *jwt.Token
 		token, _, err = tokenParser.ParseUnverified(text, jwt.MapClaims{})
 		if err != nil {
@@ -390,8 +960,34 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 		logger.Debug(ctx, "Logger wasn't provided, will use Go log")
 	}
 
+	// Create the HTTP client:
+	baseTransport, transport, err := b.createTransport()
+	if err != nil {
+		return
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   b.timeout,
+	}
+
+	// Discover the endpoints of the OpenID provider, if the issuer has been provided:
+	var discovery *internal.DiscoveryDocument
+	if b.issuer != "" {
+		discovery, err = internal.Discover(ctx, client, b.issuer)
+		if err != nil {
+			err = fmt.Errorf(
+				"can't discover OpenID configuration of issuer '%s': %v",
+				b.issuer, err,
+			)
+			return
+		}
+	}
+
 	// Set the default authentication details, if needed:
 	rawTokenURL := b.tokenURL
+	if rawTokenURL == "" && discovery != nil && discovery.TokenEndpoint != "" {
+		rawTokenURL = discovery.TokenEndpoint
+	}
 	if rawTokenURL == "" {
 		rawTokenURL = DefaultTokenURL
 		logger.Debug(
@@ -405,6 +1001,44 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 		err = fmt.Errorf("can't parse token URL '%s': %v", rawTokenURL, err)
 		return
 	}
+	var deviceURL *url.URL
+	if b.deviceURL != "" {
+		deviceURL, err = url.Parse(b.deviceURL)
+		if err != nil {
+			err = fmt.Errorf("can't parse device authorization URL '%s': %v", b.deviceURL, err)
+			return
+		}
+	} else if discovery != nil && discovery.DeviceAuthorizationEndpoint != "" {
+		deviceURL, err = url.Parse(discovery.DeviceAuthorizationEndpoint)
+		if err != nil {
+			err = fmt.Errorf(
+				"can't parse discovered device authorization URL '%s': %v",
+				discovery.DeviceAuthorizationEndpoint, err,
+			)
+			return
+		}
+	} else {
+		deviceURL = defaultDeviceURL(tokenURL)
+	}
+	var codeURL *url.URL
+	if b.codeURL != "" {
+		codeURL, err = url.Parse(b.codeURL)
+		if err != nil {
+			err = fmt.Errorf("can't parse authorization URL '%s': %v", b.codeURL, err)
+			return
+		}
+	} else if discovery != nil && discovery.AuthorizationEndpoint != "" {
+		codeURL, err = url.Parse(discovery.AuthorizationEndpoint)
+		if err != nil {
+			err = fmt.Errorf(
+				"can't parse discovered authorization URL '%s': %v",
+				discovery.AuthorizationEndpoint, err,
+			)
+			return
+		}
+	} else {
+		codeURL = defaultCodeURL(tokenURL)
+	}
 	clientID := b.clientID
 	if clientID == "" {
 		clientID = DefaultClientID
@@ -453,15 +1087,114 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 		agent = DefaultAgent
 	}
 
-	// Create the HTTP client:
-	// #nosec 402
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: b.insecure,
-				RootCAs:            b.trustedCAs,
-			},
-		},
+	// Check the retry configuration:
+	if b.retryLimit < 0 {
+		err = fmt.Errorf("retry limit %d isn't valid, it must be zero or greater", b.retryLimit)
+		return
+	}
+	if b.retryInterval < 0 {
+		err = fmt.Errorf("retry interval %s isn't valid, it must be positive", b.retryInterval)
+		return
+	}
+	if b.retryJitter < 0 || b.retryJitter > 1 {
+		err = fmt.Errorf("retry jitter %f isn't valid, it must be between 0 and 1", b.retryJitter)
+		return
+	}
+
+	// Check the token renewal configuration:
+	if b.tokenRenewalFraction < 0 || b.tokenRenewalFraction >= 1 {
+		err = fmt.Errorf(
+			"token renewal fraction %f isn't valid, it must be between 0 and 1",
+			b.tokenRenewalFraction,
+		)
+		return
+	}
+
+	// Create the tracer, using the global provider if no provider has been configured, and set
+	// the default propagator if needed:
+	tracerProvider := b.tracerProvider
+	if tracerProvider == nil {
+		tracerProvider = global.TracerProvider()
+	}
+	tracer := tracerProvider.Tracer(
+		internal.TracerName,
+		trace.WithInstrumentationVersion(Version),
+	)
+	tracePropagator := b.tracePropagator
+	if tracePropagator == nil {
+		tracePropagator = propagators.TraceContext{}
+	}
+
+	// Create the limiters:
+	limiters, err := b.createLimiters()
+	if err != nil {
+		return
+	}
+
+	// Check that the grants that we may need are supported by the OpenID provider:
+	if discovery != nil {
+		grants := []string{}
+		if havePassword {
+			grants = append(grants, "password")
+		}
+		if haveSecret {
+			grants = append(grants, "client_credentials")
+		}
+		if haveDevice {
+			grants = append(grants, deviceGrantType)
+		}
+		if haveCode {
+			grants = append(grants, "authorization_code")
+		}
+		for _, grant := range grants {
+			if !discovery.SupportsGrant(grant) {
+				err = fmt.Errorf(
+					"grant type '%s' isn't supported by issuer '%s'",
+					grant, b.issuer,
+				)
+				return
+			}
+		}
+	}
+
+	// Create the token cache. When tokens are given to the builder the key includes their subject,
+	// so that connections created with tokens for different identities don't share the cache
+	// entry. When a token source is used the subject isn't known till the source returns the first
+	// token, so the cache will be created then.
+	var cache *tokenCache
+	if b.tokenCache != "" && b.tokenSource == nil {
+		var subject string
+		switch {
+		case refreshToken != nil:
+			subject = tokenSubject(refreshToken)
+		case accessToken != nil:
+			subject = tokenSubject(accessToken)
+		}
+		cache = newTokenCache(
+			b.tokenCache,
+			tokenURL.String(), clientID, b.user, scopes, subject,
+		)
+	}
+
+	// Verify the access token given to the builder. Note that it may be expired, and that isn't
+	// an error because it will be replaced.
+	var verifier *tokenVerifier
+	if b.verifyTokens {
+		issuer := defaultIssuer(tokenURL.String())
+		if discovery != nil {
+			issuer = discovery.Issuer
+		}
+		verifier = newTokenVerifier(logger, client, issuer, clientID)
+		if discovery != nil {
+			verifier.keysURL = discovery.JWKSURI
+		}
+		if accessToken != nil {
+			err = verifier.verify(ctx, accessToken, false)
+			if err != nil {
+				err = fmt.Errorf("can't verify access token: %v", err)
+				return
+			}
+		}
 	}
 
 	// Allocate and populate the connection object:
@@ -470,6 +1203,7 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 		trustedCAs:   b.trustedCAs,
 		insecure:     b.insecure,
 		client:       client,
+		transport:    baseTransport,
 		tokenURL:     tokenURL,
 		clientID:     clientID,
 		clientSecret: clientSecret,
@@ -481,11 +1215,44 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 		accessToken:  accessToken,
 		refreshToken: refreshToken,
 		scopes:       scopes,
+		tokenSource:  b.tokenSource,
+
+		tokenCache:     cache,
+		tokenCachePath: b.tokenCache,
+		tokenVerifier:  verifier,
+
+		deviceHandler: b.deviceHandler,
+		deviceURL:     deviceURL,
+
+		codeHandler: b.codeHandler,
+		codeURL:     codeURL,
+
+		retryLimit:         b.retryLimit,
+		retryInterval:      b.retryInterval,
+		retryMaxInterval:   b.retryMaxInterval,
+		retryJitter:        b.retryJitter,
+		retryNonIdempotent: b.retryNonIdempotent,
+
+		tokenRenewalFraction: b.tokenRenewalFraction,
+
+		bearerForwarder: b.bearerForwarder,
+
+		limiters: limiters,
+
+		tracer:          tracer,
+		tracePropagator: tracePropagator,
 	}
 
 	// Create the mutex that protects token manipulations:
 	connection.tokenMutex = &sync.Mutex{}
 
+	// Use the OpenID grants to obtain tokens if no other token source has been provided:
+	if connection.tokenSource == nil {
+		connection.tokenSource = &openIDTokenSource{
+			connection: connection,
+		}
+	}
+
 	// Register metrics:
 	if b.subsystem != "" {
 		err = connection.registerMetrics(b.subsystem)
@@ -495,6 +1262,11 @@ func (b *ConnectionBuilder) BuildContext(ctx context.Context) (connection *Conne
 		}
 	}
 
+	// Start the goroutine that renews the tokens in the background:
+	if connection.tokenRenewalFraction > 0 {
+		connection.startRenewer()
+	}
+
 	return
 }
 
@@ -576,6 +1348,18 @@ func (c *Connection) Close() error {
 		return err
 	}
 	c.closed = true
+	c.stopRenewer()
+
+	// Close the idle connections of the base transport directly, as the transport wrappers may
+	// not pass the call to it. Note that this is done only when the transport has been created
+	// by the connection, a transport provided by the caller may be shared with other connections.
+	if c.transport != nil {
+		c.client.CloseIdleConnections()
+		if closer, ok := c.transport.(interface{ CloseIdleConnections() }); ok {
+			closer.CloseIdleConnections()
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,195 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the OpenID discovery support of the connection.
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Discovery", func() {
+	// Server used during the tests:
+	var oidServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the server:
+		oidServer = ghttp.NewServer()
+		oidServer.RouteToHandler(
+			http.MethodGet,
+			"/.well-known/openid-configuration",
+			RespondWithJSONTemplate(
+				http.StatusOK,
+				`{
+					"issuer": "{{ .Issuer }}",
+					"authorization_endpoint": "{{ .Issuer }}/myauth",
+					"token_endpoint": "{{ .Issuer }}/mytoken",
+					"device_authorization_endpoint": "{{ .Issuer }}/mydevice",
+					"jwks_uri": "{{ .Issuer }}/mycerts",
+					"grant_types_supported": [
+						"refresh_token",
+						"password",
+						"urn:ietf:params:oauth:grant-type:device_code"
+					]
+				}`,
+				"Issuer", oidServer.URL(),
+			),
+		)
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		oidServer.Close()
+	})
+
+	It("Uses the discovered token URL", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.RouteToHandler(
+			http.MethodPost,
+			"/mytoken",
+			RespondWithTokens(accessToken, refreshToken),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Issuer(oidServer.URL()).
+			User("myuser", "mypassword").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+		Expect(connection.TokenURL()).To(Equal(oidServer.URL() + "/mytoken"))
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+	})
+
+	It("Uses the discovered device authorization URL", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.RouteToHandler(
+			http.MethodPost,
+			"/mydevice",
+			RespondWithDeviceCode(0),
+		)
+		oidServer.RouteToHandler(
+			http.MethodPost,
+			"/mytoken",
+			RespondWithTokens(accessToken, refreshToken),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Issuer(oidServer.URL()).
+			DeviceCode(func(ctx context.Context, authorization *DeviceAuthorization) error {
+				return nil
+			}).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+	})
+
+	It("Gives precedence to the explicit token URL", func() {
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Issuer(oidServer.URL()).
+			TokenURL("https://sso.example.com/token").
+			User("myuser", "mypassword").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+		Expect(connection.TokenURL()).To(Equal("https://sso.example.com/token"))
+	})
+
+	It("Caches the discovery document", func() {
+		for i := 0; i < 3; i++ {
+			connection, err := NewConnectionBuilder().
+				Logger(logger).
+				Issuer(oidServer.URL()).
+				User("myuser", "mypassword").
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			err = connection.Close()
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(oidServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Fails if the grant isn't supported", func() {
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Issuer(oidServer.URL()).
+			Client("myclient", "mysecret").
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("client_credentials"))
+		Expect(connection).To(BeNil())
+	})
+
+	It("Fails if the issuer doesn't match", func() {
+		other := ghttp.NewServer()
+		defer other.Close()
+		other.AppendHandlers(
+			RespondWithJSON(http.StatusOK, `{
+				"issuer": "https://sso.example.com",
+				"token_endpoint": "https://sso.example.com/token"
+			}`),
+		)
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Issuer(other.URL()).
+			User("myuser", "mypassword").
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("doesn't match"))
+		Expect(connection).To(BeNil())
+	})
+})
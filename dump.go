@@ -20,9 +20,11 @@ limitations under the License.
 package sdk
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 )
@@ -36,10 +38,12 @@ const (
 var redactFields = []string{
 	"access_token",
 	"admin",
+	"device_code",
 	"id_token",
 	"refresh_token",
 	"password",
 	"client_secret",
+	"code_verifier",
 	"kubeconfig",
 	"ssh",
 }
@@ -141,6 +145,29 @@ func (c *Connection) redactSensitive(body map[string]interface{}) {
 	}
 }
 
+// censorForm encodes the given form replacing the values of sensitive fields with redactionStr.
+func censorForm(form url.Values) []byte {
+	var censoredBody bytes.Buffer
+	// Unlike real url.Values.Encode(), this doesn't sort keys.
+	for name, values := range form {
+		for _, value := range values {
+			// Buffer.Write*() don't require error checking but golangci-lint v1.10.2
+			// on Jenkins flags them (maybe https://github.com/securego/gosec/issues/267).
+			if censoredBody.Len() > 0 {
+				censoredBody.WriteByte('&') // #nosec G104
+			}
+			censoredBody.WriteString(url.QueryEscape(name) + "=") // #nosec G104
+
+			if isRedactField(name) {
+				censoredBody.WriteString(redactionStr) // #nosec G104
+			} else {
+				censoredBody.WriteString(url.QueryEscape(value)) // #nosec G104
+			}
+		}
+	}
+	return censoredBody.Bytes()
+}
+
 // isRedactField checks if f is a field that should be redacted.
 func isRedactField(f string) bool {
 	for _, redactField := range redactFields {
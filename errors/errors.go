@@ -20,14 +20,19 @@ limitations under the License.
 package errors // github.com/openshift-online/ocm-sdk-go/errors
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
+	"runtime/debug"
 	"strconv"
+	"time"
 
 	"github.com/golang/glog"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/openshift-online/ocm-sdk-go/helpers"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // Error kind is the name of the type used to represent errors.
@@ -38,18 +43,22 @@ const ErrorNilKind = "ErrorNil"
 
 // ErrorBuilder is a builder for the error type.
 type ErrorBuilder struct {
-	id     *string
-	href   *string
-	code   *string
-	reason *string
+	id          *string
+	href        *string
+	code        *string
+	reason      *string
+	operationID *string
+	status      int
 }
 
 // Error represents errors.
 type Error struct {
-	id     *string
-	href   *string
-	code   *string
-	reason *string
+	id          *string
+	href        *string
+	code        *string
+	reason      *string
+	operationID *string
+	status      int
 }
 
 // NewError returns a new ErrorBuilder
@@ -81,6 +90,19 @@ func (e *ErrorBuilder) Reason(reason string) *ErrorBuilder {
 	return e
 }
 
+// OperationID sets the operation identifier field for the ErrorBuilder
+func (e *ErrorBuilder) OperationID(operationID string) *ErrorBuilder {
+	e.operationID = &operationID
+	return e
+}
+
+// Status sets the HTTP status code that will be used when the error is sent to the client. This
+// isn't part of the JSON representation of the error.
+func (e *ErrorBuilder) Status(status int) *ErrorBuilder {
+	e.status = status
+	return e
+}
+
 // Build builds a new error type or returns an error.
 func (e *ErrorBuilder) Build() (*Error, error) {
 	err := new(Error)
@@ -88,6 +110,8 @@ func (e *ErrorBuilder) Build() (*Error, error) {
 	err.code = e.code
 	err.id = e.id
 	err.href = e.href
+	err.operationID = e.operationID
+	err.status = e.status
 	return err, nil
 }
 
@@ -171,6 +195,44 @@ func (e *Error) GetReason() (value string, ok bool) {
 	return
 }
 
+// OperationID returns the identifier of the operation that caused the error. The server writes
+// this identifier to its log, so it can be used to find the details of the error.
+func (e *Error) OperationID() string {
+	if e != nil && e.operationID != nil {
+		return *e.operationID
+	}
+	return ""
+}
+
+// GetOperationID returns the operation identifier of the error and a flag indicating if the
+// operation identifier has a value.
+func (e *Error) GetOperationID() (value string, ok bool) {
+	ok = e != nil && e.operationID != nil
+	if ok {
+		value = *e.operationID
+	}
+	return
+}
+
+// Status returns the HTTP status code that should be used when the error is sent to the client. If
+// it hasn't been explicitly set it is calculated from the identifier, if that is a valid status
+// code. Otherwise it is zero.
+func (e *Error) Status() int {
+	if e == nil {
+		return 0
+	}
+	if e.status != 0 {
+		return e.status
+	}
+	if e.id != nil {
+		status, err := strconv.Atoi(*e.id)
+		if err == nil && status >= 100 && status <= 599 {
+			return status
+		}
+	}
+	return 0
+}
+
 // Error is the implementation of the error interface.
 func (e *Error) Error() string {
 	if e.reason != nil {
@@ -216,6 +278,9 @@ func readError(iterator *jsoniter.Iterator) *Error {
 		case "reason":
 			value := iterator.ReadString()
 			object.reason = &value
+		case "operation_id":
+			value := iterator.ReadString()
+			object.operationID = &value
 		default:
 			iterator.ReadAny()
 		}
@@ -254,50 +319,153 @@ func writeError(e *Error, stream *jsoniter.Stream) {
 		stream.WriteObjectField("reason")
 		stream.WriteString(*e.reason)
 	}
+	if e.operationID != nil {
+		stream.WriteMore()
+		stream.WriteObjectField("operation_id")
+		stream.WriteString(*e.operationID)
+	}
 	stream.WriteObjectEnd()
 }
 
 var panicID = "1000"
-var panicError, _ = NewError().
-	ID(panicID).
-	Reason("An unexpected error happened, please check the log of the service " +
-		"for details").
-	Build()
+var panicReason = "An unexpected error happened, please check the log of the service " +
+	"for details"
+
+// newOperationID generates a random identifier for an operation that failed, so that the error
+// sent to the client can be correlated with the messages written to the log.
+func newOperationID() string {
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	if err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(data)
+}
+
+// LogError writes an error message to the given logger. If the logger is nil the message is
+// written using the glog package.
+// This method is used internally and no backwards compatibility is guaranteed.
+func LogError(logger logging.Logger, r *http.Request, format string, args ...interface{}) {
+	if logger == nil {
+		glog.Errorf(format, args...)
+		return
+	}
+	logger.Error(r.Context(), format, args...)
+}
 
 // SendError writes a given error and status code to a response writer.
 // if an error occurred it will log the error and exit.
 // This methods is used internaly and no backwards compatibily is guaranteed.
 func SendError(w http.ResponseWriter, r *http.Request, object *Error) {
-	status, err := strconv.Atoi(object.ID())
-	if err != nil {
-		SendPanic(w, r)
+	SendErrorWithLogger(w, r, nil, object)
+}
+
+// SendErrorWithLogger is like SendError, but it writes the errors to the given logger. If the
+// logger is nil the errors are written using the glog package.
+// This methods is used internaly and no backwards compatibily is guaranteed.
+func SendErrorWithLogger(w http.ResponseWriter, r *http.Request, logger logging.Logger,
+	object *Error) {
+	status := object.Status()
+	if status == 0 {
+		SendPanicWithLogger(w, r, logger)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	err = MarshalError(object, w)
+	err := MarshalError(object, w)
 	if err != nil {
-		glog.Errorf("Can't send response body for request '%s'", r.URL.Path)
+		LogError(
+			logger, r,
+			"Can't send response body for request '%s': %v",
+			r.URL.Path, err,
+		)
 		return
 	}
 }
 
-// SendPanic sends a panic error response to the client, but it doesn't end the process.
+// SendPanic sends a panic error response to the client, but it doesn't end the process. The
+// response uses the 500 status code and contains a generated operation identifier that is also
+// written to the log.
 // This methods is used internaly and no backwards compatibily is guaranteed.
 func SendPanic(w http.ResponseWriter, r *http.Request) {
+	SendPanicWithLogger(w, r, nil)
+}
+
+// SendPanicWithLogger is like SendPanic, but it writes the operation identifier and the errors to
+// the given logger. If the logger is nil they are written using the glog package.
+// This methods is used internaly and no backwards compatibily is guaranteed.
+func SendPanicWithLogger(w http.ResponseWriter, r *http.Request, logger logging.Logger) {
+	operationID := newOperationID()
+	LogError(
+		logger, r,
+		"Sending panic response for method '%s' and path '%s' with operation "+
+			"identifier '%s'",
+		r.Method, r.URL.Path, operationID,
+	)
+	sendPanic(w, r, logger, operationID)
+}
+
+// RecoverPanic recovers from a panic that happened while processing a request, writes the details
+// to the given logger and sends a panic error response to the client. If the logger is nil the
+// details are written using the glog package. It must be called using defer, for example:
+//
+//	defer errors.RecoverPanic(w, r, logger)
+//
+// The http.ErrAbortHandler value is panicked again, as it is used to abort the processing of the
+// request on purpose. If the response writer has a Written method and it returns true
+// then the response has already been started, so only the details are written to the log.
+// This methods is used internaly and no backwards compatibily is guaranteed.
+func RecoverPanic(w http.ResponseWriter, r *http.Request, logger logging.Logger) {
+	cause := recover()
+	if cause == nil {
+		return
+	}
+	if cause == http.ErrAbortHandler {
+		panic(cause)
+	}
+	operationID := newOperationID()
+	LogError(
+		logger, r,
+		"Panic while processing request for method '%s' and path '%s' with operation "+
+			"identifier '%s': %v\n%s",
+		r.Method, r.URL.Path, operationID, cause, debug.Stack(),
+	)
+	tracker, ok := w.(interface{ Written() bool })
+	if ok && tracker.Written() {
+		return
+	}
+	sendPanic(w, r, logger, operationID)
+}
+
+// sendPanic sends the panic error response with the given operation identifier.
+func sendPanic(w http.ResponseWriter, r *http.Request, logger logging.Logger,
+	operationID string) {
+	body := &Error{
+		id:          &panicID,
+		reason:      &panicReason,
+		operationID: &operationID,
+		status:      http.StatusInternalServerError,
+	}
 	w.Header().Set("Content-Type", "application/json")
-	err := MarshalError(panicError, w)
+	w.WriteHeader(body.status)
+	err := MarshalError(body, w)
 	if err != nil {
-		glog.Errorf(
-			"Can't send panic response for request '%s': %s",
-			r.URL.Path,
-			err.Error(),
+		LogError(
+			logger, r,
+			"Can't send panic response for request '%s': %v",
+			r.URL.Path, err,
 		)
 	}
 }
 
 // SendNotFound sends a generic 404 error.
 func SendNotFound(w http.ResponseWriter, r *http.Request) {
+	SendNotFoundWithLogger(w, r, nil)
+}
+
+// SendNotFoundWithLogger is like SendNotFound, but it writes the errors to the given logger. If
+// the logger is nil the errors are written using the glog package.
+func SendNotFoundWithLogger(w http.ResponseWriter, r *http.Request, logger logging.Logger) {
 	reason := fmt.Sprintf(
 		"Can't find resource for path '%s''",
 		r.URL.Path,
@@ -307,14 +475,21 @@ func SendNotFound(w http.ResponseWriter, r *http.Request) {
 		Reason(reason).
 		Build()
 	if err != nil {
-		SendPanic(w, r)
+		SendPanicWithLogger(w, r, logger)
 		return
 	}
-	SendError(w, r, body)
+	SendErrorWithLogger(w, r, logger, body)
 }
 
 // SendMethodNotAllowed sends a generic 405 error.
 func SendMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	SendMethodNotAllowedWithLogger(w, r, nil)
+}
+
+// SendMethodNotAllowedWithLogger is like SendMethodNotAllowed, but it writes the errors to the
+// given logger. If the logger is nil the errors are written using the glog package.
+func SendMethodNotAllowedWithLogger(w http.ResponseWriter, r *http.Request,
+	logger logging.Logger) {
 	reason := fmt.Sprintf(
 		"Method '%s' isn't supported for path '%s''",
 		r.Method, r.URL.Path,
@@ -324,14 +499,21 @@ func SendMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
 		Reason(reason).
 		Build()
 	if err != nil {
-		SendPanic(w, r)
+		SendPanicWithLogger(w, r, logger)
 		return
 	}
-	SendError(w, r, body)
+	SendErrorWithLogger(w, r, logger, body)
 }
 
 // SendInternalServerError sends a generic 500 error.
 func SendInternalServerError(w http.ResponseWriter, r *http.Request) {
+	SendInternalServerErrorWithLogger(w, r, nil)
+}
+
+// SendInternalServerErrorWithLogger is like SendInternalServerError, but it writes the errors to
+// the given logger. If the logger is nil the errors are written using the glog package.
+func SendInternalServerErrorWithLogger(w http.ResponseWriter, r *http.Request,
+	logger logging.Logger) {
 	reason := fmt.Sprintf(
 		"Can't process '%s' request for path '%s' due to an internal"+
 			"server error",
@@ -342,8 +524,77 @@ func SendInternalServerError(w http.ResponseWriter, r *http.Request) {
 		Reason(reason).
 		Build()
 	if err != nil {
-		SendPanic(w, r)
+		SendPanicWithLogger(w, r, logger)
 		return
 	}
-	SendError(w, r, body)
+	SendErrorWithLogger(w, r, logger, body)
+}
+
+// newStatusError creates an error with the given status code, using it also as the identifier, and
+// with a reason composed using the given format and arguments as the fmt.Sprintf function does.
+func newStatusError(status int, format string, args ...interface{}) *Error {
+	id := strconv.Itoa(status)
+	reason := fmt.Sprintf(format, args...)
+	return &Error{
+		id:     &id,
+		reason: &reason,
+		status: status,
+	}
+}
+
+// NewBadRequest creates an error that will be sent to the client with the 400 status code. Servers
+// can return it to indicate that the request isn't valid. For example:
+//
+//	if request.Body().Name() == "" {
+//		return errors.NewBadRequest("Cluster name is mandatory")
+//	}
+func NewBadRequest(format string, args ...interface{}) *Error {
+	return newStatusError(http.StatusBadRequest, format, args...)
+}
+
+// NewNotFound creates an error that will be sent to the client with the 404 status code. Servers
+// can return it to indicate that the requested object doesn't exist.
+func NewNotFound(format string, args ...interface{}) *Error {
+	return newStatusError(http.StatusNotFound, format, args...)
+}
+
+// NewConflict creates an error that will be sent to the client with the 409 status code. Servers
+// can return it to indicate that the request conflicts with the current state of the object, for
+// example when trying to create an object that already exists.
+func NewConflict(format string, args ...interface{}) *Error {
+	return newStatusError(http.StatusConflict, format, args...)
+}
+
+// NewUnprocessableEntity creates an error that will be sent to the client with the 422 status
+// code. Servers can return it to indicate that the request is well formed but can't be processed,
+// for example because it contains values that aren't acceptable.
+func NewUnprocessableEntity(format string, args ...interface{}) *Error {
+	return newStatusError(http.StatusUnprocessableEntity, format, args...)
+}
+
+// SendBadRequest sends a 400 error with the given reason. It is used by the generated servers when
+// the request can't be parsed. If the logger is nil the errors are written using the glog package.
+// This methods is used internaly and no backwards compatibily is guaranteed.
+func SendBadRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger,
+	reason string) {
+	SendErrorWithLogger(w, r, logger, newStatusError(http.StatusBadRequest, "%s", reason))
+}
+
+// SendServerError sends the error returned by a method of a server. If it is an *Error with an
+// HTTP status code it is sent to the client as is. Any other error is written to the log and a
+// generic 500 error is sent instead, so that internal details aren't revealed to the client. If
+// the logger is nil the errors are written using the glog package.
+// This methods is used internaly and no backwards compatibily is guaranteed.
+func SendServerError(w http.ResponseWriter, r *http.Request, logger logging.Logger, err error) {
+	object, ok := err.(*Error)
+	if ok && object != nil && object.Status() != 0 {
+		SendErrorWithLogger(w, r, logger, object)
+		return
+	}
+	LogError(
+		logger, r,
+		"Can't process request for method '%s' and path '%s': %v",
+		r.Method, r.URL.Path, err,
+	)
+	SendInternalServerErrorWithLogger(w, r, logger)
 }
@@ -25,6 +25,7 @@ import (
 
 	"github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/search"
 )
 
 func main() {
@@ -62,7 +63,7 @@ func main() {
 	for {
 		// Retrieve the page:
 		response, err := collection.List().
-			Search("name like 'my%'").
+			Search(search.Like("name", "my%").String()).
 			Size(size).
 			Page(page).
 			SendContext(ctx)
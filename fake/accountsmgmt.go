@@ -0,0 +1,399 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the fake implementation of the accounts management service.
+
+package fake
+
+import (
+	"context"
+	"io"
+
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+)
+
+type accountsMgmtServer struct {
+	server *Server
+}
+
+func (s *accountsMgmtServer) V1() amv1.Server {
+	return &accountsMgmtV1Server{
+		server: s.server,
+	}
+}
+
+type accountsMgmtV1Server struct {
+	server *Server
+}
+
+func (s *accountsMgmtV1Server) SKUS() amv1.SKUSServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) AccessToken() amv1.AccessTokenServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) Accounts() amv1.AccountsServer {
+	return &accountsServer{
+		server: s.server,
+	}
+}
+
+func (s *accountsMgmtV1Server) ClusterAuthorizations() amv1.ClusterAuthorizationsServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) ClusterRegistrations() amv1.ClusterRegistrationsServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) CurrentAccess() amv1.RolesServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) CurrentAccount() amv1.CurrentAccountServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) Organizations() amv1.OrganizationsServer {
+	return &organizationsServer{
+		server: s.server,
+	}
+}
+
+func (s *accountsMgmtV1Server) Permissions() amv1.PermissionsServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) Registries() amv1.RegistriesServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) RegistryCredentials() amv1.RegistryCredentialsServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) ResourceQuota() amv1.ResourceQuotasServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) RoleBindings() amv1.RoleBindingsServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) Roles() amv1.RolesServer {
+	return nil
+}
+
+func (s *accountsMgmtV1Server) Subscriptions() amv1.SubscriptionsServer {
+	return &subscriptionsServer{
+		server: s.server,
+	}
+}
+
+type accountsServer struct {
+	server *Server
+}
+
+func (s *accountsServer) Add(ctx context.Context, request *amv1.AccountsAddServerRequest,
+	response *amv1.AccountsAddServerResponse) error {
+	value, err := encode(func(w io.Writer) error {
+		return amv1.MarshalAccount(request.Body(), w)
+	})
+	if err != nil {
+		return err
+	}
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value = s.server.accounts.add(value)
+	body, err := amv1.UnmarshalAccount(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *accountsServer) List(ctx context.Context, request *amv1.AccountsListServerRequest,
+	response *amv1.AccountsListServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	values, total, err := s.server.accounts.list(
+		request.Search(), request.Order(), request.Page(), request.Size(),
+	)
+	if err != nil {
+		return err
+	}
+	items, err := amv1.UnmarshalAccountList(decode(values))
+	if err != nil {
+		return err
+	}
+	builders := make([]*amv1.AccountBuilder, len(items))
+	for i, item := range items {
+		builders[i] = amv1.NewAccount().Copy(item)
+	}
+	list, err := amv1.NewAccountList().Items(builders...).Build()
+	if err != nil {
+		return err
+	}
+	response.Items(list)
+	response.Page(request.Page())
+	response.Size(len(items))
+	response.Total(total)
+	return nil
+}
+
+func (s *accountsServer) Account(id string) amv1.AccountServer {
+	return &accountServer{
+		server: s.server,
+		id:     id,
+	}
+}
+
+type accountServer struct {
+	server *Server
+	id     string
+}
+
+func (s *accountServer) Get(ctx context.Context, request *amv1.AccountGetServerRequest,
+	response *amv1.AccountGetServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.server.accounts.get(s.id)
+	if err != nil {
+		return err
+	}
+	body, err := amv1.UnmarshalAccount(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *accountServer) Update(ctx context.Context, request *amv1.AccountUpdateServerRequest,
+	response *amv1.AccountUpdateServerResponse) error {
+	patch, err := encode(func(w io.Writer) error {
+		return amv1.MarshalAccount(request.Body(), w)
+	})
+	if err != nil {
+		return err
+	}
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.server.accounts.update(s.id, patch)
+	if err != nil {
+		return err
+	}
+	body, err := amv1.UnmarshalAccount(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+type organizationsServer struct {
+	server *Server
+}
+
+func (s *organizationsServer) Add(ctx context.Context,
+	request *amv1.OrganizationsAddServerRequest,
+	response *amv1.OrganizationsAddServerResponse) error {
+	value, err := encode(func(w io.Writer) error {
+		return amv1.MarshalOrganization(request.Body(), w)
+	})
+	if err != nil {
+		return err
+	}
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value = s.server.organizations.add(value)
+	body, err := amv1.UnmarshalOrganization(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *organizationsServer) List(ctx context.Context,
+	request *amv1.OrganizationsListServerRequest,
+	response *amv1.OrganizationsListServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	values, total, err := s.server.organizations.list(
+		request.Search(), "", request.Page(), request.Size(),
+	)
+	if err != nil {
+		return err
+	}
+	items, err := amv1.UnmarshalOrganizationList(decode(values))
+	if err != nil {
+		return err
+	}
+	builders := make([]*amv1.OrganizationBuilder, len(items))
+	for i, item := range items {
+		builders[i] = amv1.NewOrganization().Copy(item)
+	}
+	list, err := amv1.NewOrganizationList().Items(builders...).Build()
+	if err != nil {
+		return err
+	}
+	response.Items(list)
+	response.Page(request.Page())
+	response.Size(len(items))
+	response.Total(total)
+	return nil
+}
+
+func (s *organizationsServer) Organization(id string) amv1.OrganizationServer {
+	return &organizationServer{
+		server: s.server,
+		id:     id,
+	}
+}
+
+type organizationServer struct {
+	server *Server
+	id     string
+}
+
+func (s *organizationServer) Get(ctx context.Context,
+	request *amv1.OrganizationGetServerRequest,
+	response *amv1.OrganizationGetServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.server.organizations.get(s.id)
+	if err != nil {
+		return err
+	}
+	body, err := amv1.UnmarshalOrganization(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *organizationServer) Update(ctx context.Context,
+	request *amv1.OrganizationUpdateServerRequest,
+	response *amv1.OrganizationUpdateServerResponse) error {
+	patch, err := encode(func(w io.Writer) error {
+		return amv1.MarshalOrganization(request.Body(), w)
+	})
+	if err != nil {
+		return err
+	}
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.server.organizations.update(s.id, patch)
+	if err != nil {
+		return err
+	}
+	body, err := amv1.UnmarshalOrganization(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *organizationServer) QuotaSummary() amv1.QuotaSummaryServer {
+	return nil
+}
+
+func (s *organizationServer) ResourceQuota() amv1.ResourceQuotasServer {
+	return nil
+}
+
+// subscriptionsServer implements the collection of subscriptions. Subscriptions can't be added
+// directly, they are created when clusters are added.
+type subscriptionsServer struct {
+	server *Server
+}
+
+func (s *subscriptionsServer) List(ctx context.Context,
+	request *amv1.SubscriptionsListServerRequest,
+	response *amv1.SubscriptionsListServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	values, total, err := s.server.subscriptions.list(
+		request.Search(), request.Order(), request.Page(), request.Size(),
+	)
+	if err != nil {
+		return err
+	}
+	items, err := amv1.UnmarshalSubscriptionList(decode(values))
+	if err != nil {
+		return err
+	}
+	builders := make([]*amv1.SubscriptionBuilder, len(items))
+	for i, item := range items {
+		builders[i] = amv1.NewSubscription().Copy(item)
+	}
+	list, err := amv1.NewSubscriptionList().Items(builders...).Build()
+	if err != nil {
+		return err
+	}
+	response.Items(list)
+	response.Page(request.Page())
+	response.Size(len(items))
+	response.Total(total)
+	return nil
+}
+
+func (s *subscriptionsServer) Subscription(id string) amv1.SubscriptionServer {
+	return &subscriptionServer{
+		server: s.server,
+		id:     id,
+	}
+}
+
+type subscriptionServer struct {
+	server *Server
+	id     string
+}
+
+func (s *subscriptionServer) Delete(ctx context.Context,
+	request *amv1.SubscriptionDeleteServerRequest,
+	response *amv1.SubscriptionDeleteServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	return s.server.subscriptions.remove(s.id)
+}
+
+func (s *subscriptionServer) Get(ctx context.Context,
+	request *amv1.SubscriptionGetServerRequest,
+	response *amv1.SubscriptionGetServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.server.subscriptions.get(s.id)
+	if err != nil {
+		return err
+	}
+	body, err := amv1.UnmarshalSubscription(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *subscriptionServer) ReservedResources() amv1.SubscriptionReservedResourcesServer {
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the fake implementation of the authorizations service. The fake server
+// doesn't enforce permissions, so all the reviews allow the requested actions.
+
+package fake
+
+import (
+	"context"
+
+	azv1 "github.com/openshift-online/ocm-sdk-go/authorizations/v1"
+)
+
+type authorizationsServer struct {
+	server *Server
+}
+
+func (s *authorizationsServer) V1() azv1.Server {
+	return &authorizationsV1Server{
+		server: s.server,
+	}
+}
+
+type authorizationsV1Server struct {
+	server *Server
+}
+
+func (s *authorizationsV1Server) AccessReview() azv1.AccessReviewServer {
+	return &accessReviewServer{}
+}
+
+func (s *authorizationsV1Server) ExportControlReview() azv1.ExportControlReviewServer {
+	return &exportControlReviewServer{}
+}
+
+func (s *authorizationsV1Server) ResourceReview() azv1.ResourceReviewServer {
+	return &resourceReviewServer{
+		server: s.server,
+	}
+}
+
+func (s *authorizationsV1Server) SelfAccessReview() azv1.SelfAccessReviewServer {
+	return &selfAccessReviewServer{}
+}
+
+type accessReviewServer struct {
+}
+
+func (s *accessReviewServer) Post(ctx context.Context, request *azv1.AccessReviewPostServerRequest,
+	response *azv1.AccessReviewPostServerResponse) error {
+	review := request.Request()
+	result, err := azv1.NewAccessReviewResponse().
+		AccountUsername(review.AccountUsername()).
+		Action(review.Action()).
+		ResourceType(review.ResourceType()).
+		ClusterID(review.ClusterID()).
+		ClusterUUID(review.ClusterUUID()).
+		OrganizationID(review.OrganizationID()).
+		SubscriptionID(review.SubscriptionID()).
+		Allowed(true).
+		Build()
+	if err != nil {
+		return err
+	}
+	response.Response(result)
+	return nil
+}
+
+type selfAccessReviewServer struct {
+}
+
+func (s *selfAccessReviewServer) Post(ctx context.Context,
+	request *azv1.SelfAccessReviewPostServerRequest,
+	response *azv1.SelfAccessReviewPostServerResponse) error {
+	review := request.Request()
+	result, err := azv1.NewSelfAccessReviewResponse().
+		Action(review.Action()).
+		ResourceType(review.ResourceType()).
+		ClusterID(review.ClusterID()).
+		ClusterUUID(review.ClusterUUID()).
+		OrganizationID(review.OrganizationID()).
+		SubscriptionID(review.SubscriptionID()).
+		Allowed(true).
+		Build()
+	if err != nil {
+		return err
+	}
+	response.Response(result)
+	return nil
+}
+
+type exportControlReviewServer struct {
+}
+
+func (s *exportControlReviewServer) Post(ctx context.Context,
+	request *azv1.ExportControlReviewPostServerRequest,
+	response *azv1.ExportControlReviewPostServerResponse) error {
+	result, err := azv1.NewExportControlReviewResponse().
+		Restricted(false).
+		Build()
+	if err != nil {
+		return err
+	}
+	response.Response(result)
+	return nil
+}
+
+// resourceReviewServer returns all the objects of the server, as the fake server doesn't
+// enforce permissions.
+type resourceReviewServer struct {
+	server *Server
+}
+
+func (s *resourceReviewServer) Post(ctx context.Context,
+	request *azv1.ResourceReviewPostServerRequest,
+	response *azv1.ResourceReviewPostServerResponse) error {
+	review := request.Request()
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	s.server.refreshClusters()
+	var clusterIDs, clusterUUIDs []string
+	for _, id := range s.server.clusters.ids {
+		clusterIDs = append(clusterIDs, id)
+		uuid, ok := s.server.clusters.objects[id]["external_id"].(string)
+		if ok {
+			clusterUUIDs = append(clusterUUIDs, uuid)
+		}
+	}
+	result, err := azv1.NewResourceReview().
+		AccountUsername(review.AccountUsername()).
+		Action(review.Action()).
+		ResourceType(review.ResourceType()).
+		ClusterIDs(clusterIDs...).
+		ClusterUUIDs(clusterUUIDs...).
+		OrganizationIDs(s.server.organizations.ids...).
+		SubscriptionIDs(s.server.subscriptions.ids...).
+		Build()
+	if err != nil {
+		return err
+	}
+	response.Review(result)
+	return nil
+}
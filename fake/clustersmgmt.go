@@ -0,0 +1,507 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the fake implementation of the clusters management service.
+
+package fake
+
+import (
+	"context"
+	"io"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+type clustersMgmtServer struct {
+	server *Server
+}
+
+func (s *clustersMgmtServer) V1() cmv1.Server {
+	return &clustersMgmtV1Server{
+		server: s.server,
+	}
+}
+
+type clustersMgmtV1Server struct {
+	server *Server
+}
+
+func (s *clustersMgmtV1Server) AWSInfrastructureAccessRoles() cmv1.AWSInfrastructureAccessRolesServer {
+	return nil
+}
+
+func (s *clustersMgmtV1Server) Addons() cmv1.AddOnsServer {
+	return nil
+}
+
+func (s *clustersMgmtV1Server) CloudProviders() cmv1.CloudProvidersServer {
+	return &cloudProvidersServer{
+		server: s.server,
+	}
+}
+
+func (s *clustersMgmtV1Server) Clusters() cmv1.ClustersServer {
+	return &clustersServer{
+		server: s.server,
+	}
+}
+
+func (s *clustersMgmtV1Server) Dashboards() cmv1.DashboardsServer {
+	return nil
+}
+
+func (s *clustersMgmtV1Server) Flavours() cmv1.FlavoursServer {
+	return nil
+}
+
+func (s *clustersMgmtV1Server) MachineTypes() cmv1.MachineTypesServer {
+	return nil
+}
+
+func (s *clustersMgmtV1Server) Versions() cmv1.VersionsServer {
+	return &versionsServer{
+		server: s.server,
+	}
+}
+
+// clustersServer implements the collection of clusters. New clusters start in the 'installing'
+// state, and deleted clusters move to the 'uninstalling' state. The transitions to the 'ready'
+// state and the final removal happen when the clusters are retrieved after the delays configured
+// in the server.
+type clustersServer struct {
+	server *Server
+}
+
+func (s *clustersServer) Add(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+	response *cmv1.ClustersAddServerResponse) error {
+	value, err := encode(func(w io.Writer) error {
+		return cmv1.MarshalCluster(request.Body(), w)
+	})
+	if err != nil {
+		return err
+	}
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	s.server.refreshClusters()
+
+	// Check that the name isn't in use:
+	name, _ := value["name"].(string)
+	for _, existing := range s.server.clusters.objects {
+		if name != "" && existing["name"] == name {
+			return errors.NewConflict("Cluster name '%s' is already in use", name)
+		}
+	}
+
+	// Check the cloud provider and the region, using AWS by default:
+	providerID := "aws"
+	if provider, ok := value["cloud_provider"].(object); ok {
+		if id, ok := provider["id"].(string); ok {
+			providerID = id
+		}
+	}
+	provider, err := s.server.cloudProviders.get(providerID)
+	if err != nil {
+		return errors.NewBadRequest("Cloud provider '%s' doesn't exist", providerID)
+	}
+	regionID, _ := lookup(value, []string{"region", "id"}).(string)
+	region, err := s.server.cloudRegions[providerID].get(regionID)
+	if err != nil {
+		return errors.NewBadRequest(
+			"Region '%s' doesn't exist for cloud provider '%s'",
+			regionID, providerID,
+		)
+	}
+	value["cloud_provider"] = link(provider)
+	value["region"] = link(region)
+
+	// Use the default version if needed:
+	if _, ok := value["version"]; !ok {
+		for _, version := range s.server.versions.objects {
+			if version["default"] == true {
+				value["version"] = link(version)
+			}
+		}
+	}
+
+	// Add the cluster and the subscription:
+	value["state"] = string(cmv1.ClusterStateInstalling)
+	value["external_id"] = newUUID()
+	value = s.server.clusters.add(value)
+	subscription := s.server.subscriptions.add(object{
+		"cluster_id":          value["id"],
+		"external_cluster_id": value["external_id"],
+		"display_name":        value["name"],
+	})
+	value["subscription"] = link(subscription)
+	s.server.transitions[value["id"].(string)] = time.Now()
+
+	body, err := cmv1.UnmarshalCluster(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *clustersServer) List(ctx context.Context, request *cmv1.ClustersListServerRequest,
+	response *cmv1.ClustersListServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	s.server.refreshClusters()
+	values, total, err := s.server.clusters.list(
+		request.Search(), request.Order(), request.Page(), request.Size(),
+	)
+	if err != nil {
+		return err
+	}
+	items, err := cmv1.UnmarshalClusterList(decode(values))
+	if err != nil {
+		return err
+	}
+	builders := make([]*cmv1.ClusterBuilder, len(items))
+	for i, item := range items {
+		builders[i] = cmv1.NewCluster().Copy(item)
+	}
+	list, err := cmv1.NewClusterList().Items(builders...).Build()
+	if err != nil {
+		return err
+	}
+	response.Items(list)
+	response.Page(request.Page())
+	response.Size(len(items))
+	response.Total(total)
+	return nil
+}
+
+func (s *clustersServer) Cluster(id string) cmv1.ClusterServer {
+	return &clusterServer{
+		server: s.server,
+		id:     id,
+	}
+}
+
+type clusterServer struct {
+	server *Server
+	id     string
+}
+
+func (s *clusterServer) Delete(ctx context.Context, request *cmv1.ClusterDeleteServerRequest,
+	response *cmv1.ClusterDeleteServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	s.server.refreshClusters()
+	value, err := s.server.clusters.get(s.id)
+	if err != nil {
+		return err
+	}
+	if value["state"] != string(cmv1.ClusterStateUninstalling) {
+		value["state"] = string(cmv1.ClusterStateUninstalling)
+		s.server.transitions[s.id] = time.Now()
+	}
+	s.server.refreshClusters()
+	return nil
+}
+
+func (s *clusterServer) Get(ctx context.Context, request *cmv1.ClusterGetServerRequest,
+	response *cmv1.ClusterGetServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	s.server.refreshClusters()
+	value, err := s.server.clusters.get(s.id)
+	if err != nil {
+		return err
+	}
+	body, err := cmv1.UnmarshalCluster(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *clusterServer) Update(ctx context.Context, request *cmv1.ClusterUpdateServerRequest,
+	response *cmv1.ClusterUpdateServerResponse) error {
+	patch, err := encode(func(w io.Writer) error {
+		return cmv1.MarshalCluster(request.Body(), w)
+	})
+	if err != nil {
+		return err
+	}
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	s.server.refreshClusters()
+	value, err := s.server.clusters.update(s.id, patch)
+	if err != nil {
+		return err
+	}
+	body, err := cmv1.UnmarshalCluster(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *clusterServer) Addons() cmv1.AddOnInstallationsServer {
+	return nil
+}
+
+func (s *clusterServer) Credentials() cmv1.CredentialsServer {
+	return nil
+}
+
+func (s *clusterServer) Groups() cmv1.GroupsServer {
+	return nil
+}
+
+func (s *clusterServer) IdentityProviders() cmv1.IdentityProvidersServer {
+	return nil
+}
+
+func (s *clusterServer) Logs() cmv1.LogsServer {
+	return nil
+}
+
+func (s *clusterServer) MetricQueries() cmv1.MetricQueriesServer {
+	return nil
+}
+
+func (s *clusterServer) Status() cmv1.ClusterStatusServer {
+	return nil
+}
+
+// refreshClusters moves the clusters that have been installing or uninstalling for longer than
+// the configured delays to their next state. Must be called with the lock acquired.
+func (s *Server) refreshClusters() {
+	now := time.Now()
+	for id, value := range s.clusters.objects {
+		started := s.transitions[id]
+		switch value["state"] {
+		case string(cmv1.ClusterStateInstalling):
+			if now.Sub(started) >= s.installDelay {
+				value["state"] = string(cmv1.ClusterStateReady)
+				delete(s.transitions, id)
+			}
+		case string(cmv1.ClusterStateUninstalling):
+			if now.Sub(started) >= s.uninstallDelay {
+				_ = s.clusters.remove(id)
+				delete(s.transitions, id)
+			}
+		}
+	}
+}
+
+type cloudProvidersServer struct {
+	server *Server
+}
+
+func (s *cloudProvidersServer) List(ctx context.Context,
+	request *cmv1.CloudProvidersListServerRequest,
+	response *cmv1.CloudProvidersListServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	values, total, err := s.server.cloudProviders.list(
+		request.Search(), request.Order(), request.Page(), request.Size(),
+	)
+	if err != nil {
+		return err
+	}
+	items, err := cmv1.UnmarshalCloudProviderList(decode(values))
+	if err != nil {
+		return err
+	}
+	builders := make([]*cmv1.CloudProviderBuilder, len(items))
+	for i, item := range items {
+		builders[i] = cmv1.NewCloudProvider().Copy(item)
+	}
+	list, err := cmv1.NewCloudProviderList().Items(builders...).Build()
+	if err != nil {
+		return err
+	}
+	response.Items(list)
+	response.Page(request.Page())
+	response.Size(len(items))
+	response.Total(total)
+	return nil
+}
+
+func (s *cloudProvidersServer) CloudProvider(id string) cmv1.CloudProviderServer {
+	return &cloudProviderServer{
+		server: s.server,
+		id:     id,
+	}
+}
+
+type cloudProviderServer struct {
+	server *Server
+	id     string
+}
+
+func (s *cloudProviderServer) Get(ctx context.Context,
+	request *cmv1.CloudProviderGetServerRequest,
+	response *cmv1.CloudProviderGetServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.server.cloudProviders.get(s.id)
+	if err != nil {
+		return err
+	}
+	body, err := cmv1.UnmarshalCloudProvider(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *cloudProviderServer) Regions() cmv1.CloudRegionsServer {
+	regions, ok := s.server.cloudRegions[s.id]
+	if !ok {
+		return nil
+	}
+	return &cloudRegionsServer{
+		server:  s.server,
+		regions: regions,
+	}
+}
+
+type cloudRegionsServer struct {
+	server  *Server
+	regions *collection
+}
+
+func (s *cloudRegionsServer) List(ctx context.Context,
+	request *cmv1.CloudRegionsListServerRequest,
+	response *cmv1.CloudRegionsListServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	values, total, err := s.regions.list(
+		"", "", request.Page(), request.Size(),
+	)
+	if err != nil {
+		return err
+	}
+	items, err := cmv1.UnmarshalCloudRegionList(decode(values))
+	if err != nil {
+		return err
+	}
+	builders := make([]*cmv1.CloudRegionBuilder, len(items))
+	for i, item := range items {
+		builders[i] = cmv1.NewCloudRegion().Copy(item)
+	}
+	list, err := cmv1.NewCloudRegionList().Items(builders...).Build()
+	if err != nil {
+		return err
+	}
+	response.Items(list)
+	response.Page(request.Page())
+	response.Size(len(items))
+	response.Total(total)
+	return nil
+}
+
+func (s *cloudRegionsServer) Region(id string) cmv1.CloudRegionServer {
+	return &cloudRegionServer{
+		server:  s.server,
+		regions: s.regions,
+		id:      id,
+	}
+}
+
+type cloudRegionServer struct {
+	server  *Server
+	regions *collection
+	id      string
+}
+
+func (s *cloudRegionServer) Get(ctx context.Context, request *cmv1.CloudRegionGetServerRequest,
+	response *cmv1.CloudRegionGetServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.regions.get(s.id)
+	if err != nil {
+		return err
+	}
+	body, err := cmv1.UnmarshalCloudRegion(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+type versionsServer struct {
+	server *Server
+}
+
+func (s *versionsServer) List(ctx context.Context, request *cmv1.VersionsListServerRequest,
+	response *cmv1.VersionsListServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	values, total, err := s.server.versions.list(
+		request.Search(), request.Order(), request.Page(), request.Size(),
+	)
+	if err != nil {
+		return err
+	}
+	items, err := cmv1.UnmarshalVersionList(decode(values))
+	if err != nil {
+		return err
+	}
+	builders := make([]*cmv1.VersionBuilder, len(items))
+	for i, item := range items {
+		builders[i] = cmv1.NewVersion().Copy(item)
+	}
+	list, err := cmv1.NewVersionList().Items(builders...).Build()
+	if err != nil {
+		return err
+	}
+	response.Items(list)
+	response.Page(request.Page())
+	response.Size(len(items))
+	response.Total(total)
+	return nil
+}
+
+func (s *versionsServer) Version(id string) cmv1.VersionServer {
+	return &versionServer{
+		server: s.server,
+		id:     id,
+	}
+}
+
+type versionServer struct {
+	server *Server
+	id     string
+}
+
+func (s *versionServer) Get(ctx context.Context, request *cmv1.VersionGetServerRequest,
+	response *cmv1.VersionGetServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.server.versions.get(s.id)
+	if err != nil {
+		return err
+	}
+	body, err := cmv1.UnmarshalVersion(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
@@ -0,0 +1,339 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the generic in-memory collection of objects used by all the resources of the
+// fake server.
+
+package fake
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/search"
+)
+
+// object is the generic representation of the objects stored in the collections, the same that
+// results from decoding their JSON representation.
+type object = map[string]interface{}
+
+// collection stores in memory the objects of one type. Objects are stored using their generic
+// representation, so that the same code can be used for all the types. Collections aren't safe for
+// concurrent use, the server protects them with a lock.
+type collection struct {
+	// Kind of the objects, for example 'Cluster':
+	kind string
+
+	// Prefix of the links of the objects, for example '/api/clusters_mgmt/v1/clusters':
+	prefix string
+
+	// Names of the attributes that contain the creation and update times, if the type has
+	// them:
+	created string
+	updated string
+
+	// Objects indexed by identifier, and identifiers in the order that the objects were added:
+	objects map[string]object
+	ids     []string
+}
+
+// newCollection creates a collection for objects of the given kind, with links that start with
+// the given prefix.
+func newCollection(kind, prefix string) *collection {
+	return &collection{
+		kind:    kind,
+		prefix:  prefix,
+		objects: map[string]object{},
+	}
+}
+
+// timestamps sets the names of the attributes that contain the creation and update times of the
+// objects. Empty names mean that the objects don't have that attribute.
+func (c *collection) timestamps(created, updated string) *collection {
+	c.created = created
+	c.updated = updated
+	return c
+}
+
+// add adds an object to the collection. If it doesn't have an identifier a new one is generated.
+// The kind, link and timestamps are always calculated. It returns the stored object.
+func (c *collection) add(value object) object {
+	id, _ := value["id"].(string)
+	if id == "" {
+		id = newID()
+	}
+	value["kind"] = c.kind
+	value["id"] = id
+	value["href"] = c.prefix + "/" + id
+	now := time.Now().UTC().Format(time.RFC3339)
+	if c.created != "" {
+		value[c.created] = now
+	}
+	if c.updated != "" {
+		value[c.updated] = now
+	}
+	if _, ok := c.objects[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.objects[id] = value
+	return value
+}
+
+// get returns the object with the given identifier. If it doesn't exist it returns a not found
+// error.
+func (c *collection) get(id string) (object, error) {
+	value, ok := c.objects[id]
+	if !ok {
+		return nil, errors.NewNotFound("%s '%s' not found", c.kind, id)
+	}
+	return value, nil
+}
+
+// update merges the attributes of the given patch into the object with the given identifier, and
+// returns the updated object.
+func (c *collection) update(id string, patch object) (object, error) {
+	value, err := c.get(id)
+	if err != nil {
+		return nil, err
+	}
+	for name, attribute := range patch {
+		switch name {
+		case "kind", "id", "href":
+		default:
+			value[name] = attribute
+		}
+	}
+	if c.updated != "" {
+		value[c.updated] = time.Now().UTC().Format(time.RFC3339)
+	}
+	return value, nil
+}
+
+// remove removes the object with the given identifier.
+func (c *collection) remove(id string) error {
+	_, err := c.get(id)
+	if err != nil {
+		return err
+	}
+	delete(c.objects, id)
+	for i, current := range c.ids {
+		if current == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// list returns the page of objects that match the given search criteria, sorted according to the
+// given order. It also returns the total number of objects that match the search criteria.
+func (c *collection) list(query, order string, page, size int) (items []object, total int,
+	err error) {
+	filter, err := search.Parse(query)
+	if err != nil {
+		err = errors.NewBadRequest("Can't parse search query '%s': %v", query, err)
+		return
+	}
+	sorter, err := parseOrder(order)
+	if err != nil {
+		err = errors.NewBadRequest("Can't parse order '%s': %v", order, err)
+		return
+	}
+	items = []object{}
+	for _, id := range c.ids {
+		value := c.objects[id]
+		var matched bool
+		matched, err = search.Match(filter, value)
+		if err != nil {
+			err = errors.NewBadRequest("Can't evaluate search query '%s': %v", query, err)
+			return
+		}
+		if matched {
+			items = append(items, value)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return sorter(items[i], items[j])
+	})
+	total = len(items)
+	if page < 1 {
+		page = 1
+	}
+	if size < 0 {
+		size = 0
+	}
+	first := (page - 1) * size
+	if first > total {
+		first = total
+	}
+	last := first + size
+	if last > total {
+		last = total
+	}
+	items = items[first:last]
+	return
+}
+
+// parseOrder parses an order criteria like 'name asc, creation_timestamp desc' and returns a
+// function that returns true if the first object goes before the second.
+func parseOrder(text string) (func(a, b object) bool, error) {
+	type criteria struct {
+		path []string
+		desc bool
+	}
+	var criterias []criteria
+	for _, chunk := range strings.Split(text, ",") {
+		fields := strings.Fields(chunk)
+		if len(fields) == 0 {
+			continue
+		}
+		item := criteria{
+			path: strings.Split(fields[0], "."),
+		}
+		if len(fields) > 1 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				item.desc = true
+			default:
+				return nil, fmt.Errorf("direction '%s' should be 'asc' or 'desc'", fields[1])
+			}
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("unexpected text '%s'", strings.Join(fields[2:], " "))
+		}
+		criterias = append(criterias, item)
+	}
+	return func(a, b object) bool {
+		for _, item := range criterias {
+			result := compare(lookup(a, item.path), lookup(b, item.path))
+			if result == 0 {
+				continue
+			}
+			if item.desc {
+				return result > 0
+			}
+			return result < 0
+		}
+		return false
+	}, nil
+}
+
+// lookup returns the value of the attribute of the given object that is located in the given
+// path, or nil if there is no such attribute.
+func lookup(value interface{}, path []string) interface{} {
+	for _, name := range path {
+		current, ok := value.(object)
+		if !ok {
+			return nil
+		}
+		value = current[name]
+	}
+	return value
+}
+
+// compare compares two attribute values. Missing values go before any other value, and values of
+// different types are compared using their text representation.
+func compare(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			}
+			return 1
+		}
+	}
+	return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
+
+// encode converts an object of the model to its generic representation, using the given function
+// to marshal it.
+func encode(marshal func(writer io.Writer) error) (object, error) {
+	buffer := &bytes.Buffer{}
+	err := marshal(buffer)
+	if err != nil {
+		return nil, err
+	}
+	var result object
+	err = json.Unmarshal(buffer.Bytes(), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decode converts the generic representation of an object, or a list of them, to JSON, so that
+// it can be unmarshalled into an object of the model.
+func decode(value interface{}) []byte {
+	data, err := json.Marshal(value)
+	if err != nil {
+		// This can't happen, as the values were obtained decoding JSON.
+		panic(err)
+	}
+	return data
+}
+
+// newUUID generates a random UUID, used for the external identifiers of clusters.
+func newUUID() string {
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	if err != nil {
+		panic(err)
+	}
+	data[6] = data[6]&0x0f | 0x40
+	data[8] = data[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:])
+}
+
+// newID generates a random identifier for a new object.
+func newID() string {
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	if err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(data)
+}
@@ -0,0 +1,249 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the HTTP server that exposes the fake server and the fake token endpoint.
+
+package fake
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/internal"
+)
+
+// Life of the tokens issued by the fake token endpoint:
+const (
+	accessTokenLife  = 15 * time.Minute
+	refreshTokenLife = 10 * time.Hour
+)
+
+// HTTPServer is a running HTTP server that exposes a fake server. It also implements a token
+// endpoint that accepts any credentials. Don't create instances of this type directly, use the
+// Start method of the fake server instead.
+type HTTPServer struct {
+	server *Server
+	secret []byte
+	http   *httptest.Server
+}
+
+// Start starts an HTTP server listening in a random local port that exposes the API under the
+// '/api' path and the token endpoint under the '/token' path. Requests to the API need an
+// 'Authorization' header containing a bearer token, but the token isn't checked. Remember to call
+// the Close method when the server is no longer needed.
+func (s *Server) Start() *HTTPServer {
+	// Generate the secret used to sign the tokens:
+	secret := make([]byte, 32)
+	_, err := rand.Read(secret)
+	if err != nil {
+		panic(err)
+	}
+
+	// Create the server:
+	result := &HTTPServer{
+		server: s,
+		secret: secret,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", result.serveToken)
+	mux.Handle("/api/", result.checkBearer(
+		http.StripPrefix("/api", sdk.NewAdapter(s, s.logger)),
+	))
+	result.http = httptest.NewServer(mux)
+
+	return result
+}
+
+// URL returns the base URL of the server, for example 'http://127.0.0.1:34567'.
+func (s *HTTPServer) URL() string {
+	return s.http.URL
+}
+
+// TokenURL returns the URL of the token endpoint of the server.
+func (s *HTTPServer) TokenURL() string {
+	return s.http.URL + "/token"
+}
+
+// Connection returns a connection builder already configured to use the server and its token
+// endpoint. The caller can change other settings before building the connection. For example:
+//
+//	connection, err := api.Connection().
+//		Logger(logger).
+//		Build()
+func (s *HTTPServer) Connection() *sdk.ConnectionBuilder {
+	return sdk.NewConnectionBuilder().
+		URL(s.URL()).
+		TokenURL(s.TokenURL()).
+		Client("fake", "fake")
+}
+
+// Close stops the server and releases the resources it uses.
+func (s *HTTPServer) Close() {
+	s.http.Close()
+}
+
+// checkBearer wraps the given handler with another one that rejects requests that don't contain a
+// bearer token.
+func (s *HTTPServer) checkBearer(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		fields := strings.Fields(header)
+		if len(fields) != 2 || !strings.EqualFold(fields[0], "Bearer") {
+			s.sendUnauthorized(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// sendUnauthorized sends the error response used when the request doesn't contain a bearer token.
+func (s *HTTPServer) sendUnauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer realm=\"fake\"")
+	response, err := errors.NewError().
+		ID(fmt.Sprintf("%d", http.StatusUnauthorized)).
+		HREF(fmt.Sprintf("/api/fake/errors/%d", http.StatusUnauthorized)).
+		Code(fmt.Sprintf("FAKE-%d", http.StatusUnauthorized)).
+		Reason("Request doesn't contain the 'Authorization' header or it isn't a bearer token").
+		Status(http.StatusUnauthorized).
+		Build()
+	if err != nil {
+		s.server.logger.Error(r.Context(), "Can't build error response: %v", err)
+		errors.SendPanicWithLogger(w, r, s.server.logger)
+		return
+	}
+	errors.SendErrorWithLogger(w, r, s.server.logger, response)
+}
+
+// serveToken implements the token endpoint. It accepts any credentials, and returns new access and
+// refresh tokens for the 'client_credentials', 'password' and 'refresh_token' grants.
+func (s *HTTPServer) serveToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.sendTokenError(w, r, http.StatusMethodNotAllowed, "invalid_request",
+			fmt.Sprintf("Method '%s' isn't supported", r.Method))
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		s.sendTokenError(w, r, http.StatusBadRequest, "invalid_request",
+			fmt.Sprintf("Can't parse form: %v", err))
+		return
+	}
+	var subject string
+	grant := r.PostForm.Get("grant_type")
+	switch grant {
+	case "client_credentials":
+		subject = r.PostForm.Get("client_id")
+	case "password":
+		subject = r.PostForm.Get("username")
+	case "refresh_token":
+		subject, err = s.parseRefresh(r.PostForm.Get("refresh_token"))
+		if err != nil {
+			s.sendTokenError(w, r, http.StatusBadRequest, "invalid_grant", err.Error())
+			return
+		}
+	default:
+		s.sendTokenError(w, r, http.StatusBadRequest, "unsupported_grant_type",
+			fmt.Sprintf("Grant type '%s' isn't supported", grant))
+		return
+	}
+	accessToken, err := s.issueToken("Bearer", subject, accessTokenLife)
+	if err != nil {
+		s.server.logger.Error(r.Context(), "Can't issue access token: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	refreshToken, err := s.issueToken("Refresh", subject, refreshTokenLife)
+	if err != nil {
+		s.server.logger.Error(r.Context(), "Can't issue refresh token: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	tokenType := "bearer"
+	s.sendTokenResponse(w, r, http.StatusOK, &internal.TokenResponse{
+		AccessToken:  &accessToken,
+		RefreshToken: &refreshToken,
+		TokenType:    &tokenType,
+	})
+}
+
+// issueToken generates a token of the given type, for the given subject and with the given life.
+func (s *HTTPServer) issueToken(typ, subject string, life time.Duration) (result string,
+	err error) {
+	iat := time.Now()
+	exp := iat.Add(life)
+	claims := jwt.MapClaims{
+		"typ": typ,
+		"sub": subject,
+		"iat": iat.Unix(),
+		"exp": exp.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	result, err = token.SignedString(s.secret)
+	return
+}
+
+// parseRefresh checks that the given refresh token was issued by this server and that it hasn't
+// expired, and returns its subject.
+func (s *HTTPServer) parseRefresh(text string) (subject string, err error) {
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(text, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method '%s'", token.Method.Alg())
+		}
+		return s.secret, nil
+	})
+	if err != nil {
+		err = fmt.Errorf("Refresh token isn't valid: %v", err)
+		return
+	}
+	typ, _ := claims["typ"].(string)
+	if !strings.EqualFold(typ, "Refresh") {
+		err = fmt.Errorf("Token type is '%s' but it should be 'Refresh'", typ)
+		return
+	}
+	subject, _ = claims["sub"].(string)
+	return
+}
+
+// sendTokenError sends an error response from the token endpoint, using the format defined in the
+// OpenID specification.
+func (s *HTTPServer) sendTokenError(w http.ResponseWriter, r *http.Request, status int,
+	code, description string) {
+	s.sendTokenResponse(w, r, status, &internal.TokenResponse{
+		Error:            &code,
+		ErrorDescription: &description,
+	})
+}
+
+// sendTokenResponse sends a response from the token endpoint.
+func (s *HTTPServer) sendTokenResponse(w http.ResponseWriter, r *http.Request, status int,
+	body *internal.TokenResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		s.server.logger.Error(r.Context(), "Can't send token response: %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake contains an in-memory implementation of the API that can be used to test code
+// that uses the SDK without a real server. For example:
+//
+//	// Create the fake server and start it:
+//	server, err := fake.NewServer().Build()
+//	if err != nil {
+//		...
+//	}
+//	api := server.Start()
+//	defer api.Close()
+//
+//	// Create a connection that uses it:
+//	connection, err := api.Connection().Build()
+//	if err != nil {
+//		...
+//	}
+//	defer connection.Close()
+//
+// The fake server supports adding, listing, retrieving, updating and deleting the most commonly
+// used objects, like clusters, accounts, organizations, subscriptions and service logs. Lists
+// support the search, order, page and size parameters. Resources that aren't supported respond
+// with the 404 status code.
+package fake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift-online/ocm-sdk-go"
+	"github.com/openshift-online/ocm-sdk-go/accountsmgmt"
+	"github.com/openshift-online/ocm-sdk-go/authorizations"
+	"github.com/openshift-online/ocm-sdk-go/clustersmgmt"
+	"github.com/openshift-online/ocm-sdk-go/servicelogs"
+)
+
+// ServerBuilder contains the data and logic needed to create a fake server. Don't create instances
+// of this type directly, use the NewServer function instead.
+type ServerBuilder struct {
+	logger         sdk.Logger
+	installDelay   time.Duration
+	uninstallDelay time.Duration
+}
+
+// Server is an in-memory implementation of the sdk.Server interface. It is safe for concurrent
+// use.
+type Server struct {
+	logger         sdk.Logger
+	installDelay   time.Duration
+	uninstallDelay time.Duration
+
+	// Lock that protects all the collections:
+	lock *sync.Mutex
+
+	// Collections of the clusters management service:
+	clusters       *collection
+	cloudProviders *collection
+	cloudRegions   map[string]*collection
+	versions       *collection
+
+	// Time when each cluster started the installation or uninstallation, indexed by
+	// cluster identifier:
+	transitions map[string]time.Time
+
+	// Collections of the accounts management service:
+	accounts      *collection
+	organizations *collection
+	subscriptions *collection
+
+	// Collections of the service logs service:
+	logs *collection
+}
+
+// Make sure that we implement the interface:
+var _ sdk.Server = &Server{}
+
+// NewServer creates a builder that can then be used to configure and create a fake server.
+func NewServer() *ServerBuilder {
+	return &ServerBuilder{}
+}
+
+// Logger sets the logger that the server will use to write errors and panics. The default is to
+// use a logger that writes to the standard output and error streams.
+func (b *ServerBuilder) Logger(value sdk.Logger) *ServerBuilder {
+	b.logger = value
+	return b
+}
+
+// InstallDelay sets the time that new clusters stay in the 'installing' state before moving to
+// the 'ready' state. The default is zero, so clusters will be ready the next time that they are
+// retrieved.
+func (b *ServerBuilder) InstallDelay(value time.Duration) *ServerBuilder {
+	b.installDelay = value
+	return b
+}
+
+// UninstallDelay sets the time that deleted clusters stay in the 'uninstalling' state before they
+// are removed. The default is zero, so clusters will be removed the next time that they are
+// retrieved.
+func (b *ServerBuilder) UninstallDelay(value time.Duration) *ServerBuilder {
+	b.uninstallDelay = value
+	return b
+}
+
+// Build uses the configuration stored in the builder to create a new fake server. The server
+// already contains the cloud providers, regions and versions that are needed to create clusters.
+func (b *ServerBuilder) Build() (result *Server, err error) {
+	// Check parameters:
+	if b.installDelay < 0 {
+		err = fmt.Errorf("install delay %s should not be negative", b.installDelay)
+		return
+	}
+	if b.uninstallDelay < 0 {
+		err = fmt.Errorf("uninstall delay %s should not be negative", b.uninstallDelay)
+		return
+	}
+
+	// Create the default logger, if needed:
+	logger := b.logger
+	if logger == nil {
+		logger, err = sdk.NewStdLoggerBuilder().Build()
+		if err != nil {
+			err = fmt.Errorf("can't create default logger: %v", err)
+			return
+		}
+	}
+
+	// Create and populate the object:
+	result = &Server{
+		logger:         logger,
+		installDelay:   b.installDelay,
+		uninstallDelay: b.uninstallDelay,
+		lock:           &sync.Mutex{},
+		clusters: newCollection(
+			"Cluster",
+			"/api/clusters_mgmt/v1/clusters",
+		).timestamps("creation_timestamp", ""),
+		cloudProviders: newCollection(
+			"CloudProvider",
+			"/api/clusters_mgmt/v1/cloud_providers",
+		),
+		cloudRegions: map[string]*collection{},
+		versions: newCollection(
+			"Version",
+			"/api/clusters_mgmt/v1/versions",
+		),
+		transitions: map[string]time.Time{},
+		accounts: newCollection(
+			"Account",
+			"/api/accounts_mgmt/v1/accounts",
+		),
+		organizations: newCollection(
+			"Organization",
+			"/api/accounts_mgmt/v1/organizations",
+		),
+		subscriptions: newCollection(
+			"Subscription",
+			"/api/accounts_mgmt/v1/subscriptions",
+		).timestamps("created_at", "updated_at"),
+		logs: newCollection(
+			"LogEntry",
+			"/api/service_logs/v1/cluster_logs",
+		),
+	}
+	result.populate()
+
+	return
+}
+
+// populate adds to the server the objects that are needed to create clusters.
+func (s *Server) populate() {
+	providers := []struct {
+		id      string
+		name    string
+		regions []string
+	}{
+		{
+			id:      "aws",
+			name:    "AWS",
+			regions: []string{"us-east-1", "us-west-2", "eu-west-1"},
+		},
+		{
+			id:      "gcp",
+			name:    "GCP",
+			regions: []string{"us-east1", "europe-west1"},
+		},
+	}
+	for _, provider := range providers {
+		added := s.cloudProviders.add(object{
+			"id":           provider.id,
+			"name":         provider.id,
+			"display_name": provider.name,
+		})
+		regions := newCollection(
+			"CloudRegion",
+			fmt.Sprintf("/api/clusters_mgmt/v1/cloud_providers/%s/regions", provider.id),
+		)
+		for _, region := range provider.regions {
+			regions.add(object{
+				"id":             region,
+				"name":           region,
+				"display_name":   region,
+				"cloud_provider": link(added),
+			})
+		}
+		s.cloudRegions[provider.id] = regions
+	}
+	versions := []string{"openshift-v4.4.27", "openshift-v4.5.16"}
+	for i, version := range versions {
+		s.versions.add(object{
+			"id":      version,
+			"enabled": true,
+			"default": i == len(versions)-1,
+		})
+	}
+}
+
+// link returns the generic representation of a link to the given object.
+func link(value object) object {
+	return object{
+		"kind": fmt.Sprintf("%sLink", value["kind"]),
+		"id":   value["id"],
+		"href": value["href"],
+	}
+}
+
+// Logger returns the logger used by the server.
+func (s *Server) Logger() sdk.Logger {
+	return s.logger
+}
+
+// AccountsMgmt returns the server for the accounts management service.
+func (s *Server) AccountsMgmt() accountsmgmt.Server {
+	return &accountsMgmtServer{
+		server: s,
+	}
+}
+
+// Authorizations returns the server for the authorizations service.
+func (s *Server) Authorizations() authorizations.Server {
+	return &authorizationsServer{
+		server: s,
+	}
+}
+
+// ClustersMgmt returns the server for the clusters management service.
+func (s *Server) ClustersMgmt() clustersmgmt.Server {
+	return &clustersMgmtServer{
+		server: s,
+	}
+}
+
+// ServiceLogs returns the server for the service logs service.
+func (s *Server) ServiceLogs() servicelogs.Server {
+	return &serviceLogsServer{
+		server: s,
+	}
+}
@@ -0,0 +1,305 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the fake server.
+
+package fake
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/openshift-online/ocm-sdk-go"
+	amv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	azv1 "github.com/openshift-online/ocm-sdk-go/authorizations/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+var _ = Describe("Server", func() {
+	var api *HTTPServer
+	var connection *sdk.Connection
+
+	BeforeEach(func() {
+		// Create the logger:
+		logger, err := sdk.NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Create and start the server:
+		server, err := NewServer().
+			Logger(logger).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		api = server.Start()
+
+		// Create the connection:
+		connection, err = api.Connection().
+			Logger(logger).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Close the connection:
+		err := connection.Close()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Stop the server:
+		api.Close()
+	})
+
+	// addCluster adds a cluster with the given name and region, and returns it:
+	addCluster := func(name, region string) *cmv1.Cluster {
+		body, err := cmv1.NewCluster().
+			Name(name).
+			Region(cmv1.NewCloudRegion().ID(region)).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		response, err := connection.ClustersMgmt().V1().Clusters().Add().
+			Body(body).
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		return response.Body()
+	}
+
+	It("Rejects requests without a bearer token", func() {
+		response, err := http.Get(api.URL() + "/api/clusters_mgmt/v1/clusters")
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("Rejects unsupported grant types", func() {
+		response, err := http.PostForm(api.TokenURL(), map[string][]string{
+			"grant_type": {"device_code"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Accepts user name and password", func() {
+		other, err := sdk.NewConnectionBuilder().
+			URL(api.URL()).
+			TokenURL(api.TokenURL()).
+			User("myuser", "mypassword").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer other.Close()
+		_, err = other.ClustersMgmt().V1().Clusters().List().Send()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Returns not found for unsupported resources", func() {
+		response, err := connection.ClustersMgmt().V1().Dashboards().List().Send()
+		Expect(err).To(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusNotFound))
+	})
+
+	It("Contains the default cloud providers, regions and versions", func() {
+		providers, err := connection.ClustersMgmt().V1().CloudProviders().List().Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(providers.Total()).To(Equal(2))
+
+		regions, err := connection.ClustersMgmt().V1().CloudProviders().
+			CloudProvider("aws").Regions().List().Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(regions.Total()).To(Equal(3))
+
+		versions, err := connection.ClustersMgmt().V1().Versions().List().
+			Search("default = true").
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(versions.Items().Len()).To(Equal(1))
+		Expect(versions.Items().Get(0).ID()).To(Equal("openshift-v4.5.16"))
+	})
+
+	It("Installs and uninstalls clusters", func() {
+		// Add the cluster:
+		cluster := addCluster("mycluster", "us-east-1")
+		Expect(cluster.ID()).ToNot(BeEmpty())
+		Expect(cluster.HREF()).To(Equal("/api/clusters_mgmt/v1/clusters/" + cluster.ID()))
+		Expect(cluster.ExternalID()).ToNot(BeEmpty())
+		Expect(cluster.State()).To(Equal(cmv1.ClusterStateInstalling))
+		Expect(cluster.CloudProvider().ID()).To(Equal("aws"))
+		Expect(cluster.Version().ID()).To(Equal("openshift-v4.5.16"))
+		Expect(cluster.Subscription().ID()).ToNot(BeEmpty())
+
+		// Check that it is ready:
+		resource := connection.ClustersMgmt().V1().Clusters().Cluster(cluster.ID())
+		get, err := resource.Get().Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(get.Body().State()).To(Equal(cmv1.ClusterStateReady))
+
+		// Check that the subscription was created:
+		subscription, err := connection.AccountsMgmt().V1().Subscriptions().
+			Subscription(cluster.Subscription().ID()).
+			Get().
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subscription.Body().ExternalClusterID()).To(Equal(cluster.ExternalID()))
+
+		// Delete it and check that it no longer exists:
+		_, err = resource.Delete().Send()
+		Expect(err).ToNot(HaveOccurred())
+		get, err = resource.Get().Send()
+		Expect(err).To(HaveOccurred())
+		Expect(get.Status()).To(Equal(http.StatusNotFound))
+	})
+
+	It("Rejects duplicated cluster names", func() {
+		addCluster("mycluster", "us-east-1")
+		body, err := cmv1.NewCluster().
+			Name("mycluster").
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		response, err := connection.ClustersMgmt().V1().Clusters().Add().
+			Body(body).
+			Send()
+		Expect(err).To(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusConflict))
+	})
+
+	It("Rejects unknown regions", func() {
+		body, err := cmv1.NewCluster().
+			Name("mycluster").
+			Region(cmv1.NewCloudRegion().ID("junk")).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		response, err := connection.ClustersMgmt().V1().Clusters().Add().
+			Body(body).
+			Send()
+		Expect(err).To(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Searches, sorts and pages clusters", func() {
+		addCluster("a", "us-east-1")
+		addCluster("b", "us-west-2")
+		addCluster("c", "us-east-1")
+		addCluster("d", "us-east-1")
+
+		response, err := connection.ClustersMgmt().V1().Clusters().List().
+			Search("region.id = 'us-east-1'").
+			Order("name desc").
+			Page(1).
+			Size(2).
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Total()).To(Equal(3))
+		Expect(response.Size()).To(Equal(2))
+		items := response.Items().Slice()
+		Expect(items).To(HaveLen(2))
+		Expect(items[0].Name()).To(Equal("d"))
+		Expect(items[1].Name()).To(Equal("c"))
+
+		response, err = connection.ClustersMgmt().V1().Clusters().List().
+			Search("region.id = 'us-east-1'").
+			Order("name desc").
+			Page(2).
+			Size(2).
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		items = response.Items().Slice()
+		Expect(items).To(HaveLen(1))
+		Expect(items[0].Name()).To(Equal("a"))
+	})
+
+	It("Rejects invalid search queries", func() {
+		response, err := connection.ClustersMgmt().V1().Clusters().List().
+			Search("name = ").
+			Send()
+		Expect(err).To(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Adds, updates and retrieves accounts", func() {
+		body, err := amv1.NewAccount().
+			Username("myuser").
+			FirstName("My").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		add, err := connection.AccountsMgmt().V1().Accounts().Add().
+			Body(body).
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		id := add.Body().ID()
+		Expect(id).ToNot(BeEmpty())
+
+		patch, err := amv1.NewAccount().
+			LastName("User").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		resource := connection.AccountsMgmt().V1().Accounts().Account(id)
+		_, err = resource.Update().
+			Body(patch).
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+
+		get, err := resource.Get().Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(get.Body().Username()).To(Equal("myuser"))
+		Expect(get.Body().FirstName()).To(Equal("My"))
+		Expect(get.Body().LastName()).To(Equal("User"))
+	})
+
+	It("Allows all access reviews", func() {
+		request, err := azv1.NewAccessReviewRequest().
+			AccountUsername("myuser").
+			Action("delete").
+			ResourceType("Cluster").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		response, err := connection.Authorizations().V1().AccessReview().Post().
+			Request(request).
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Response().Allowed()).To(BeTrue())
+		Expect(response.Response().AccountUsername()).To(Equal("myuser"))
+	})
+
+	It("Adds, lists and deletes service logs", func() {
+		cluster := addCluster("mycluster", "us-east-1")
+		body, err := slv1.NewLogEntry().
+			ClusterUUID(cluster.ExternalID()).
+			Summary("Cluster created").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		add, err := connection.ServiceLogs().V1().ClusterLogs().Add().
+			Body(body).
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(add.Body().Timestamp().IsZero()).To(BeFalse())
+
+		list, err := connection.ServiceLogs().V1().ClusterLogs().List().
+			Search("cluster_uuid = '" + cluster.ExternalID() + "'").
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.Total()).To(Equal(1))
+
+		resource := connection.ServiceLogs().V1().ClusterLogs().LogEntry(add.Body().ID())
+		_, err = resource.Delete().Send()
+		Expect(err).ToNot(HaveOccurred())
+		get, err := resource.Get().Send()
+		Expect(err).To(HaveOccurred())
+		Expect(get.Status()).To(Equal(http.StatusNotFound))
+	})
+})
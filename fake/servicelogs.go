@@ -0,0 +1,137 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the fake implementation of the service logs service.
+
+package fake
+
+import (
+	"context"
+	"io"
+	"time"
+
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+)
+
+type serviceLogsServer struct {
+	server *Server
+}
+
+func (s *serviceLogsServer) V1() slv1.Server {
+	return &serviceLogsV1Server{
+		server: s.server,
+	}
+}
+
+type serviceLogsV1Server struct {
+	server *Server
+}
+
+func (s *serviceLogsV1Server) ClusterLogs() slv1.ClusterLogsServer {
+	return &clusterLogsServer{
+		server: s.server,
+	}
+}
+
+type clusterLogsServer struct {
+	server *Server
+}
+
+func (s *clusterLogsServer) Add(ctx context.Context, request *slv1.ClusterLogsAddServerRequest,
+	response *slv1.ClusterLogsAddServerResponse) error {
+	value, err := encode(func(w io.Writer) error {
+		return slv1.MarshalLogEntry(request.Body(), w)
+	})
+	if err != nil {
+		return err
+	}
+	if _, ok := value["timestamp"]; !ok {
+		value["timestamp"] = time.Now().UTC().Format(time.RFC3339)
+	}
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value = s.server.logs.add(value)
+	body, err := slv1.UnmarshalLogEntry(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
+
+func (s *clusterLogsServer) List(ctx context.Context, request *slv1.ClusterLogsListServerRequest,
+	response *slv1.ClusterLogsListServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	values, total, err := s.server.logs.list(
+		request.Search(), request.Order(), request.Page(), request.Size(),
+	)
+	if err != nil {
+		return err
+	}
+	items, err := slv1.UnmarshalLogEntryList(decode(values))
+	if err != nil {
+		return err
+	}
+	builders := make([]*slv1.LogEntryBuilder, len(items))
+	for i, item := range items {
+		builders[i] = slv1.NewLogEntry().Copy(item)
+	}
+	list, err := slv1.NewLogEntryList().Items(builders...).Build()
+	if err != nil {
+		return err
+	}
+	response.Items(list)
+	response.Page(request.Page())
+	response.Size(len(items))
+	response.Total(total)
+	return nil
+}
+
+func (s *clusterLogsServer) LogEntry(id string) slv1.LogEntryServer {
+	return &logEntryServer{
+		server: s.server,
+		id:     id,
+	}
+}
+
+type logEntryServer struct {
+	server *Server
+	id     string
+}
+
+func (s *logEntryServer) Delete(ctx context.Context, request *slv1.LogEntryDeleteServerRequest,
+	response *slv1.LogEntryDeleteServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	return s.server.logs.remove(s.id)
+}
+
+func (s *logEntryServer) Get(ctx context.Context, request *slv1.LogEntryGetServerRequest,
+	response *slv1.LogEntryGetServerResponse) error {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	value, err := s.server.logs.get(s.id)
+	if err != nil {
+		return err
+	}
+	body, err := slv1.UnmarshalLogEntry(decode(value))
+	if err != nil {
+		return err
+	}
+	response.Body(body)
+	return nil
+}
@@ -26,6 +26,10 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/openshift-online/ocm-sdk-go/internal"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/propagators"
 )
 
 // AddValue creates the given set of query parameters if needed, an then adds
@@ -173,3 +177,16 @@ func evalPredicates(predicates []func(interface{}) bool, object interface{}) boo
 
 // Name of the header used to contain the metrics path:
 const metricHeader = "X-Metric"
+
+// StartServerSpan starts a span for a request received by a generated server, using the global
+// tracer provider. If the request hasn't been traced by a previous handler, the W3C trace context
+// is extracted from the request headers. It returns the response writer and the request that
+// should be used to process the request, and a function that must be called to end the span.
+func StartServerSpan(w http.ResponseWriter, r *http.Request, name string) (http.ResponseWriter,
+	*http.Request, func()) {
+	return internal.StartServerSpan(
+		global.Tracer(internal.TracerName),
+		propagators.TraceContext{},
+		w, r, name,
+	)
+}
@@ -0,0 +1,326 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// IMPORTANT: This file has been generated automatically, refrain from modifying it manually as all
+// your changes will be lost when the file is generated again.
+
+package helpers // github.com/openshift-online/ocm-sdk-go/helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationKind indicates the kind of a type described for validation.
+type ValidationKind int
+
+const (
+	// ValidationBoolean accepts JSON booleans.
+	ValidationBoolean ValidationKind = iota
+	// ValidationInteger accepts JSON numbers without fractional part.
+	ValidationInteger
+	// ValidationFloat accepts any JSON number.
+	ValidationFloat
+	// ValidationString accepts JSON strings.
+	ValidationString
+	// ValidationDate accepts JSON strings containing RFC3339 dates.
+	ValidationDate
+	// ValidationEnum accepts JSON strings containing one of the values of the type.
+	ValidationEnum
+	// ValidationList accepts JSON arrays whose items are of the item type.
+	ValidationList
+	// ValidationMap accepts JSON objects whose values are of the item type.
+	ValidationMap
+	// ValidationObject accepts JSON objects whose attributes are of the types of the
+	// attributes of the type. Attributes that the type doesn't have are only rejected
+	// in strict mode.
+	ValidationObject
+)
+
+// ValidationType describes a type of the model for the purpose of validating the bodies of
+// requests. The generated servers contain one of these descriptions for each type of the model.
+type ValidationType struct {
+	// Kind is the kind of the type.
+	Kind ValidationKind
+
+	// Values contains the valid values of enumerated types.
+	Values []string
+
+	// Item is the type of the items of lists and of the values of maps.
+	Item *ValidationType
+
+	// Attributes contains the attributes of object types, indexed by JSON field name.
+	Attributes map[string]*ValidationAttribute
+}
+
+// ValidationAttribute describes an attribute of an object type.
+type ValidationAttribute struct {
+	// Type is the type of the attribute.
+	Type *ValidationType
+
+	// Required indicates that the attribute must be present in the body of requests that
+	// add new objects.
+	Required bool
+
+	// ReadOnly indicates that the attribute is calculated by the server, and that it can't
+	// be present in the body of requests that add or update objects.
+	ReadOnly bool
+}
+
+// Descriptions of the scalar types, shared by all the generated servers:
+var (
+	ValidationBooleanType = &ValidationType{Kind: ValidationBoolean}
+	ValidationIntegerType = &ValidationType{Kind: ValidationInteger}
+	ValidationFloatType   = &ValidationType{Kind: ValidationFloat}
+	ValidationStringType  = &ValidationType{Kind: ValidationString}
+	ValidationDateType    = &ValidationType{Kind: ValidationDate}
+)
+
+// ValidationMode indicates which checks should be applied to the attributes of the object
+// contained in the body of a request, in addition to the checks of types and enumerated values
+// that are always applied.
+type ValidationMode int
+
+const (
+	// ValidateAction doesn't apply additional checks. It is used for requests that aren't
+	// adding or updating objects.
+	ValidateAction ValidationMode = iota
+	// ValidateAdd checks that required attributes are present and that read only
+	// attributes aren't. It is used for requests that add objects.
+	ValidateAdd
+	// ValidateUpdate checks that read only attributes aren't present. It is used for
+	// requests that update objects.
+	ValidateUpdate
+)
+
+// ContextWithStrictValidation returns a copy of the given context that indicates that the
+// bodies of requests should be validated in strict mode. In strict mode attributes that aren't
+// part of the model are rejected. By default they are ignored, like the JSON decoders of the
+// generated servers do.
+func ContextWithStrictValidation(parent context.Context) context.Context {
+	return context.WithValue(parent, strictValidationKey, true)
+}
+
+// ValidationError is the error returned when the body of a request isn't valid. It contains the
+// complete list of violations found.
+type ValidationError struct {
+	violations []string
+}
+
+// Violations returns the descriptions of the violations found.
+func (e *ValidationError) Violations() []string {
+	return e.violations
+}
+
+// Error is the implementation of the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("request is invalid: %s", strings.Join(e.violations, ", "))
+}
+
+// Validate checks that the given body of a request is valid according to the given type and
+// validation mode. The validation is strict if the given context has been created with the
+// ContextWithStrictValidation function. If the body isn't valid it returns a
+// *ValidationError containing all the violations found.
+func Validate(ctx context.Context, body []byte, root *ValidationType, mode ValidationMode) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	if err != nil {
+		return fmt.Errorf("can't parse request body: %v", err)
+	}
+	strict, _ := ctx.Value(strictValidationKey).(bool)
+	validator := &validator{
+		mode:   mode,
+		strict: strict,
+	}
+	validator.checkRoot(value, root)
+	if len(validator.violations) > 0 {
+		return &ValidationError{
+			violations: validator.violations,
+		}
+	}
+	return nil
+}
+
+// validator contains the state of the validation of the body of one request.
+type validator struct {
+	mode       ValidationMode
+	strict     bool
+	violations []string
+}
+
+// checkRoot checks the value at the root of the body. The required and read only attributes
+// are only checked for that value, as nested objects are usually links to other objects that
+// need to contain the identifier.
+func (v *validator) checkRoot(value interface{}, root *ValidationType) {
+	object, ok := value.(map[string]interface{})
+	if ok && root.Kind == ValidationObject && v.mode != ValidateAction {
+		names := make([]string, 0, len(root.Attributes))
+		for name := range root.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			attribute := root.Attributes[name]
+			_, present := object[name]
+			switch {
+			case attribute.ReadOnly && present:
+				v.addf("attribute '%s' is read only", name)
+			case attribute.Required && !present && v.mode == ValidateAdd:
+				v.addf("attribute '%s' is mandatory", name)
+			}
+		}
+	}
+	v.check("", value, root)
+}
+
+// check checks that the given value, located in the given path of the body, is of the given
+// type.
+func (v *validator) check(path string, value interface{}, typ *ValidationType) {
+	if value == nil {
+		return
+	}
+	switch typ.Kind {
+	case ValidationBoolean:
+		if _, ok := value.(bool); !ok {
+			v.addf("attribute '%s' should be a boolean", path)
+		}
+	case ValidationInteger:
+		number, ok := value.(json.Number)
+		if ok {
+			_, err := strconv.ParseInt(string(number), 10, 64)
+			ok = err == nil
+		}
+		if !ok {
+			v.addf("attribute '%s' should be an integer", path)
+		}
+	case ValidationFloat:
+		if _, ok := value.(json.Number); !ok {
+			v.addf("attribute '%s' should be a number", path)
+		}
+	case ValidationString:
+		if _, ok := value.(string); !ok {
+			v.addf("attribute '%s' should be a string", path)
+		}
+	case ValidationDate:
+		text, ok := value.(string)
+		if ok {
+			_, err := time.Parse(time.RFC3339, text)
+			ok = err == nil
+		}
+		if !ok {
+			v.addf("attribute '%s' should be a RFC3339 date", path)
+		}
+	case ValidationEnum:
+		v.checkEnum(path, value, typ)
+	case ValidationList:
+		items, ok := value.([]interface{})
+		if !ok {
+			v.addf("attribute '%s' should be a list", path)
+			return
+		}
+		for i, item := range items {
+			v.check(fmt.Sprintf("%s[%d]", path, i), item, typ.Item)
+		}
+	case ValidationMap:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.addf("attribute '%s' should be an object", path)
+			return
+		}
+		for _, name := range sortedKeys(object) {
+			v.check(v.join(path, name), object[name], typ.Item)
+		}
+	case ValidationObject:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			if path == "" {
+				v.addf("body should be an object")
+			} else {
+				v.addf("attribute '%s' should be an object", path)
+			}
+			return
+		}
+		for _, name := range sortedKeys(object) {
+			attribute, ok := typ.Attributes[name]
+			if !ok {
+				if v.strict {
+					v.addf("attribute '%s' is unknown", v.join(path, name))
+				}
+				continue
+			}
+			v.check(v.join(path, name), object[name], attribute.Type)
+		}
+	}
+}
+
+// checkEnum checks that the given value is one of the values of the given enumerated type.
+func (v *validator) checkEnum(path string, value interface{}, typ *ValidationType) {
+	text, ok := value.(string)
+	if !ok {
+		v.addf("attribute '%s' should be a string", path)
+		return
+	}
+	for _, valid := range typ.Values {
+		if text == valid {
+			return
+		}
+	}
+	v.addf(
+		"value '%s' of attribute '%s' isn't valid, valid values are '%s'",
+		text, path, strings.Join(typ.Values, "', '"),
+	)
+}
+
+// join calculates the path of an attribute from the path of the object that contains it.
+func (v *validator) join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// addf adds a violation with a description composed using the given format and arguments as the
+// fmt.Sprintf function does.
+func (v *validator) addf(format string, args ...interface{}) {
+	v.violations = append(v.violations, fmt.Sprintf(format, args...))
+}
+
+// sortedKeys returns the keys of the given object, sorted alphabetically, so that violations are
+// always reported in the same order.
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// strictValidationKeyType is the type of the key used to store in the context the flag that
+// enables strict validation.
+type strictValidationKeyType string
+
+// strictValidationKey is the key used to store in the context the flag that enables strict
+// validation.
+const strictValidationKey strictValidationKeyType = "strictValidation"
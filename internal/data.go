@@ -24,3 +24,16 @@ type TokenResponse struct {
 	RefreshToken     *string `json:"refresh_token,omitempty"`
 	TokenType        *string `json:"token_type,omitempty"`
 }
+
+// DeviceAuthorizationResponse is used to unmarshal the JSON responses of the device authorization
+// endpoint.
+type DeviceAuthorizationResponse struct {
+	DeviceCode              *string `json:"device_code,omitempty"`
+	Error                   *string `json:"error,omitempty"`
+	ErrorDescription        *string `json:"error_description,omitempty"`
+	ExpiresIn               *int    `json:"expires_in,omitempty"`
+	Interval                *int    `json:"interval,omitempty"`
+	UserCode                *string `json:"user_code,omitempty"`
+	VerificationURI         *string `json:"verification_uri,omitempty"`
+	VerificationURIComplete *string `json:"verification_uri_complete,omitempty"`
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of OpenID Connect discovery, used by the client and by the
+// authentication handler to find the endpoints of the SSO server from the issuer URL.
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DiscoveryDocument is used to unmarshal the sub-set of properties of the OpenID provider
+// configuration that we need.
+type DiscoveryDocument struct {
+	Issuer                      string   `json:"issuer,omitempty"`
+	AuthorizationEndpoint       string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint               string   `json:"token_endpoint,omitempty"`
+	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint,omitempty"`
+	JWKSURI                     string   `json:"jwks_uri,omitempty"`
+	GrantTypesSupported         []string `json:"grant_types_supported,omitempty"`
+}
+
+// SupportsGrant checks if the provider supports the given grant type. Note that the list of
+// supported grants is optional, and when it is empty all grants are assumed to be supported.
+func (d *DiscoveryDocument) SupportsGrant(grant string) bool {
+	if len(d.GrantTypesSupported) == 0 {
+		return true
+	}
+	for _, supported := range d.GrantTypesSupported {
+		if supported == grant {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscoveryTTL is the time that discovery documents are kept in the cache.
+var DiscoveryTTL = 1 * time.Hour
+
+// discoveryEntry is an entry of the cache of discovery documents.
+type discoveryEntry struct {
+	document *DiscoveryDocument
+	expiry   time.Time
+}
+
+// discoveryCache contains the discovery documents that have already been loaded, indexed by
+// issuer URL.
+var discoveryCache = &sync.Map{}
+
+// Discover loads the OpenID provider configuration of the given issuer, from the
+// `/.well-known/openid-configuration` path. Documents are cached, so that creating multiple
+// connections or handlers for the same issuer doesn't send multiple requests.
+func Discover(ctx context.Context, client *http.Client, issuer string) (document *DiscoveryDocument,
+	err error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	// Try the cache first:
+	value, ok := discoveryCache.Load(issuer)
+	if ok {
+		entry := value.(*discoveryEntry)
+		if time.Now().Before(entry.expiry) {
+			document = entry.document
+			return
+		}
+	}
+
+	// Load the document:
+	addr := issuer + "/.well-known/openid-configuration"
+	request, err := http.NewRequest(http.MethodGet, addr, nil)
+	if err != nil {
+		return
+	}
+	request.Header.Set("Accept", "application/json")
+	request = request.WithContext(ctx)
+	response, err := client.Do(request)
+	if err != nil {
+		err = fmt.Errorf("can't load OpenID configuration from '%s': %v", addr, err)
+		return
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		err = fmt.Errorf("can't read OpenID configuration from '%s': %v", addr, err)
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf(
+			"can't load OpenID configuration from '%s', response status is: %s",
+			addr, response.Status,
+		)
+		return
+	}
+	document = &DiscoveryDocument{}
+	err = json.Unmarshal(body, document)
+	if err != nil {
+		err = fmt.Errorf("can't parse OpenID configuration from '%s': %v", addr, err)
+		return
+	}
+
+	// Check that the document is for the requested issuer, as required by the specification:
+	if strings.TrimSuffix(document.Issuer, "/") != issuer {
+		err = fmt.Errorf(
+			"issuer '%s' of OpenID configuration doesn't match expected '%s'",
+			document.Issuer, issuer,
+		)
+		return
+	}
+
+	// Save the document in the cache:
+	discoveryCache.Store(issuer, &discoveryEntry{
+		document: document,
+		expiry:   time.Now().Add(DiscoveryTTL),
+	})
+
+	return
+}
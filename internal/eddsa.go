@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the EdDSA signing method, as the JWT library that we
+// use doesn't support it.
+
+package internal
+
+import (
+	"crypto/ed25519"
+	"sync"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// SigningMethodEdDSA implements the EdDSA signing method using Ed25519 keys.
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+// signingMethodEdDSA is the implementation of the jwt.SigningMethod interface for EdDSA.
+type signingMethodEdDSA struct{}
+
+// RegisterEdDSA registers the EdDSA signing method with the JWT library, so that parsers accept
+// tokens that use it. Note that the JWT library only supports a global registry of signing methods,
+// shared by all the parsers of the process, so this affects all of them. That is why it isn't done
+// automatically when this package is imported, but only by the code that needs it. If a signing
+// method with the same name has already been registered by other code it is preserved.
+func RegisterEdDSA() {
+	registerEdDSAOnce.Do(func() {
+		if jwt.GetSigningMethod(SigningMethodEdDSA.Alg()) != nil {
+			return
+		}
+		jwt.RegisterSigningMethod(SigningMethodEdDSA.Alg(), func() jwt.SigningMethod {
+			return SigningMethodEdDSA
+		})
+	})
+}
+
+// registerEdDSAOnce ensures that the EdDSA signing method is registered only once.
+var registerEdDSAOnce sync.Once
+
+// Alg is the implementation of the jwt.SigningMethod interface.
+func (m *signingMethodEdDSA) Alg() string {
+	return "EdDSA"
+}
+
+// Verify is the implementation of the jwt.SigningMethod interface. The key must be an
+// ed25519.PublicKey.
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(public, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
+
+// Sign is the implementation of the jwt.SigningMethod interface. The key must be an
+// ed25519.PrivateKey.
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	sig := ed25519.Sign(private, []byte(signingString))
+	return jwt.EncodeSegment(sig), nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to parse JSON web key sets. They are used by the client,
+// to verify the tokens that it receives, and by the authentication handler, to verify the tokens
+// sent by clients.
+
+package internal
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// KeyData is the type used to read a single key from a JSON document.
+type KeyData struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// KeySetData is the type used to read a collection of keys from a JSON document.
+type KeySetData struct {
+	Keys []KeyData `json:"keys"`
+}
+
+// Key is a key that has been loaded from a JSON web key set, together with the details needed to
+// decide if it can be used to verify a token.
+type Key struct {
+	// ID is the key identifier.
+	ID string
+
+	// Algorithm is the signing algorithm that the key is intended for, for example `RS256`.
+	Algorithm string
+
+	// Use is the intended use of the key, usually `sig`. It may be empty.
+	Use string
+
+	// Public is the public key itself. It will be a *rsa.PublicKey, *ecdsa.PublicKey or
+	// ed25519.PublicKey, depending on the type of the key.
+	Public interface{}
+}
+
+// Check verifies that the key can be used to verify the given token: the key must be intended for
+// signatures and for the algorithm used by the token.
+func (k *Key) Check(token *jwt.Token) error {
+	if k.Use != "" && k.Use != "sig" {
+		return fmt.Errorf("key '%s' has use '%s' instead of 'sig'", k.ID, k.Use)
+	}
+	alg := token.Method.Alg()
+	if alg != k.Algorithm {
+		return fmt.Errorf(
+			"token algorithm '%s' doesn't match algorithm '%s' of key '%s'",
+			alg, k.Algorithm, k.ID,
+		)
+	}
+	return nil
+}
+
+// CheckKey checks that the given key data contains the fields that are required in order to
+// parse it.
+func CheckKey(data KeyData) error {
+	if data.Kid == "" {
+		return fmt.Errorf("'kid' is empty")
+	}
+	if data.Kty == "" {
+		return fmt.Errorf("'kty' is empty")
+	}
+	if data.Alg == "" {
+		return fmt.Errorf("'alg' is empty")
+	}
+	var required map[string]string
+	switch data.Kty {
+	case "RSA":
+		required = map[string]string{
+			"e": data.E,
+			"n": data.N,
+		}
+	case "EC":
+		required = map[string]string{
+			"crv": data.Crv,
+			"x":   data.X,
+			"y":   data.Y,
+		}
+	case "OKP":
+		required = map[string]string{
+			"crv": data.Crv,
+			"x":   data.X,
+		}
+	}
+	for _, name := range []string{"crv", "e", "n", "x", "y"} {
+		value, ok := required[name]
+		if ok && value == "" {
+			return fmt.Errorf("'%s' is empty", name)
+		}
+	}
+	return nil
+}
+
+// ParseKey converts the key data loaded from the JSON document to an actual key that can be used
+// to verify the signatures of tokens. Supported key types are `RSA`, `EC` with the `P-256`,
+// `P-384` and `P-521` curves, and `OKP` with the `Ed25519` curve.
+func ParseKey(data KeyData) (key *Key, err error) {
+	var public interface{}
+	switch data.Kty {
+	case "RSA":
+		public, err = parseRSAKey(data)
+	case "EC":
+		public, err = parseECKey(data)
+	case "OKP":
+		public, err = parseOKPKey(data)
+	default:
+		err = fmt.Errorf("key type '%s' isn't supported", data.Kty)
+	}
+	if err != nil {
+		return
+	}
+	key = &Key{
+		ID:        data.Kid,
+		Algorithm: data.Alg,
+		Use:       data.Use,
+		Public:    public,
+	}
+	return
+}
+
+// parseRSAKey creates an RSA public key from the `n` and `e` values.
+func parseRSAKey(data KeyData) (key *rsa.PublicKey, err error) {
+	nb, err := base64.RawURLEncoding.DecodeString(data.N)
+	if err != nil {
+		return
+	}
+	eb, err := base64.RawURLEncoding.DecodeString(data.E)
+	if err != nil {
+		return
+	}
+	key = &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nb),
+		E: int(new(big.Int).SetBytes(eb).Int64()),
+	}
+	return
+}
+
+// parseECKey creates an elliptic curve public key from the `crv`, `x` and `y` values.
+func parseECKey(data KeyData) (key *ecdsa.PublicKey, err error) {
+	var curve elliptic.Curve
+	switch data.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		err = fmt.Errorf("curve '%s' isn't supported", data.Crv)
+		return
+	}
+	xb, err := base64.RawURLEncoding.DecodeString(data.X)
+	if err != nil {
+		return
+	}
+	yb, err := base64.RawURLEncoding.DecodeString(data.Y)
+	if err != nil {
+		return
+	}
+	x := new(big.Int).SetBytes(xb)
+	y := new(big.Int).SetBytes(yb)
+	if !curve.IsOnCurve(x, y) {
+		err = fmt.Errorf("point isn't on curve '%s'", data.Crv)
+		return
+	}
+	key = &ecdsa.PublicKey{
+		Curve: curve,
+		X:     x,
+		Y:     y,
+	}
+	return
+}
+
+// parseOKPKey creates an Edwards curve public key from the `crv` and `x` values.
+func parseOKPKey(data KeyData) (key ed25519.PublicKey, err error) {
+	if data.Crv != "Ed25519" {
+		err = fmt.Errorf("curve '%s' isn't supported", data.Crv)
+		return
+	}
+	xb, err := base64.RawURLEncoding.DecodeString(data.X)
+	if err != nil {
+		return
+	}
+	if len(xb) != ed25519.PublicKeySize {
+		err = fmt.Errorf(
+			"key size is %d bytes but it should be %d",
+			len(xb), ed25519.PublicKeySize,
+		)
+		return
+	}
+	key = ed25519.PublicKey(xb)
+	return
+}
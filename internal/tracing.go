@@ -0,0 +1,132 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to create OpenTelemetry spans for the requests received by
+// servers.
+
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+// TracerName is the name of the tracer used to create the spans of the SDK.
+const TracerName = "github.com/openshift-online/ocm-sdk-go"
+
+// StartServerSpan starts a span for a request received by a server. If the context of the request
+// doesn't already contain a span, the trace context is extracted from the request headers using
+// the given propagator. It returns the response writer and the request that should be used to
+// process the request, and a function that must be called to end the span when the processing is
+// finished.
+func StartServerSpan(tracer trace.Tracer, propagator otel.TextMapPropagator,
+	w http.ResponseWriter, r *http.Request, name string) (http.ResponseWriter, *http.Request,
+	func()) {
+	// Extract the trace context from the headers, unless the request has already been traced
+	// by a previous handler:
+	ctx := r.Context()
+	if !trace.SpanFromContext(ctx).SpanContext().IsValid() &&
+		!trace.RemoteSpanContextFromContext(ctx).IsValid() {
+		ctx = propagator.Extract(ctx, r.Header)
+	}
+
+	// Start the span:
+	ctx, span := tracer.Start(
+		ctx,
+		name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(r.Method),
+			semconv.HTTPTargetKey.String(r.URL.Path),
+		),
+	)
+
+	// Wrap the response writer so that we can find the status code when the span ends:
+	recorder := &statusRecorder{
+		ResponseWriter: w,
+		code:           http.StatusOK,
+	}
+	end := func() {
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(recorder.code))
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(recorder.code))
+		span.End()
+	}
+	return recorder, r.WithContext(ctx), end
+}
+
+// statusRecorder is a response writer that remembers the status code sent, and if the response has
+// already been started.
+type statusRecorder struct {
+	http.ResponseWriter
+	code    int
+	written bool
+}
+
+// WriteHeader saves the status code and sends it to the wrapped response writer.
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.written = true
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Write remembers that the response has been started and sends the data to the wrapped response
+// writer.
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	r.written = true
+	return r.ResponseWriter.Write(data)
+}
+
+// Written returns true if the status code or part of the body have already been sent, or if the
+// connection has been hijacked.
+func (r *statusRecorder) Written() bool {
+	return r.written
+}
+
+// Flush sends buffered data to the client, if the wrapped response writer supports it.
+func (r *statusRecorder) Flush() {
+	flusher, ok := r.ResponseWriter.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets the caller take over the connection, if the wrapped response writer supports it.
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer doesn't support hijacking")
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		r.written = true
+	}
+	return conn, rw, err
+}
+
+// Push initiates an HTTP/2 server push, if the wrapped response writer supports it.
+func (r *statusRecorder) Push(target string, opts *http.PushOptions) error {
+	pusher, ok := r.ResponseWriter.(http.Pusher)
+	if !ok {
+		return http.ErrNotSupported
+	}
+	return pusher.Push(target, opts)
+}
@@ -0,0 +1,187 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the iteration of paginated collections.
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+var _ = Describe("Iterate", func() {
+	// Server used during the tests:
+	var apiServer *ghttp.Server
+
+	// Connection used during the tests:
+	var connection *Connection
+
+	BeforeEach(func() {
+		// Create the API server:
+		apiServer = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err := NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Create the connection:
+		connection, err = NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(DefaultToken("Bearer", 5*time.Minute)).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		apiServer.Close()
+
+		// Close the connection:
+		err := connection.Close()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// collect iterates the clusters collection and returns the identifiers of the clusters.
+	collect := func(request *cmv1.ClustersListRequest) (ids []string, err error) {
+		err = request.Iterate(context.Background(), func(item *cmv1.Cluster) bool {
+			ids = append(ids, item.ID())
+			return true
+		})
+		return
+	}
+
+	It("Retrieves all the pages till the total is reached", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters", "page=1&size=2"),
+				RespondWithJSON(http.StatusOK, `{
+					"page": 1,
+					"size": 2,
+					"total": 3,
+					"items": [
+						{ "id": "123" },
+						{ "id": "456" }
+					]
+				}`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters", "page=2&size=2"),
+				RespondWithJSON(http.StatusOK, `{
+					"page": 2,
+					"size": 1,
+					"total": 3,
+					"items": [
+						{ "id": "789" }
+					]
+				}`),
+			),
+		)
+
+		// Iterate the collection:
+		ids, err := collect(connection.ClustersMgmt().V1().Clusters().List().Size(2))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ids).To(Equal([]string{"123", "456", "789"}))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Stops when a page is empty", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusOK, `{
+				"page": 1,
+				"size": 0,
+				"total": 10,
+				"items": []
+			}`),
+		)
+
+		// Iterate the collection:
+		ids, err := collect(connection.ClustersMgmt().V1().Clusters().List())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ids).To(BeEmpty())
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Stops when the function returns false", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusOK, `{
+				"page": 1,
+				"size": 2,
+				"total": 4,
+				"items": [
+					{ "id": "123" },
+					{ "id": "456" }
+				]
+			}`),
+		)
+
+		// Iterate the collection:
+		var ids []string
+		err := connection.ClustersMgmt().V1().Clusters().List().Size(2).Iterate(
+			context.Background(),
+			func(item *cmv1.Cluster) bool {
+				ids = append(ids, item.ID())
+				return false
+			},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ids).To(Equal([]string{"123"}))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Propagates the error returned for a page", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			RespondWithJSON(http.StatusOK, `{
+				"page": 1,
+				"size": 1,
+				"total": 2,
+				"items": [
+					{ "id": "123" }
+				]
+			}`),
+			RespondWithJSON(http.StatusNotFound, `{
+				"kind": "Error",
+				"id": "404",
+				"reason": "Not found"
+			}`),
+		)
+
+		// Iterate the collection:
+		ids, err := collect(connection.ClustersMgmt().V1().Clusters().List().Size(1))
+		Expect(err).To(HaveOccurred())
+		Expect(ids).To(Equal([]string{"123"}))
+		typed, ok := err.(*errors.Error)
+		Expect(ok).To(BeTrue())
+		Expect(typed.ID()).To(Equal("404"))
+	})
+})
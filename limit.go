@@ -0,0 +1,283 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the logic that limits the rate and the concurrency of the requests sent by the
+// connection.
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimit contains the configuration of a rate limit.
+type rateLimit struct {
+	rate  float64
+	burst int
+}
+
+// requestLimiter limits the rate and the number of concurrent requests sent to the paths that
+// start with a prefix. The empty prefix is used for the limiter that applies to all requests.
+type requestLimiter struct {
+	prefix string
+	rate   *rateLimiter
+	slots  chan struct{}
+}
+
+// rateLimiter is a token bucket that allows on average `rate` requests per second, with bursts of
+// up to `burst` requests.
+type rateLimiter struct {
+	mutex  *sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a rate limiter with the bucket initially full.
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		mutex:  &sync.Mutex{},
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token from the bucket, waiting till one is available or the context is cancelled.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	// Reserve a token, calculating how much we need to wait till it is available:
+	l.mutex.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mutex.Unlock()
+	if delay == 0 {
+		return nil
+	}
+
+	// Wait for the token, and give it back if the context is cancelled before that:
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+// createLimiters creates the request limiters from the configuration stored in the builder.
+func (b *ConnectionBuilder) createLimiters() (result map[string]*requestLimiter, err error) {
+	result = map[string]*requestLimiter{}
+	find := func(prefix string) *requestLimiter {
+		limiter, ok := result[prefix]
+		if !ok {
+			limiter = &requestLimiter{
+				prefix: prefix,
+			}
+			result[prefix] = limiter
+		}
+		return limiter
+	}
+	for prefix, limit := range b.rateLimits {
+		err = checkLimitPrefix(prefix)
+		if err != nil {
+			return
+		}
+		if limit.rate <= 0 {
+			err = fmt.Errorf(
+				"rate limit %f for %s isn't valid, it must be greater than zero",
+				limit.rate, limitDescription(prefix),
+			)
+			return
+		}
+		if limit.burst < 1 {
+			err = fmt.Errorf(
+				"rate limit burst %d for %s isn't valid, it must be one or greater",
+				limit.burst, limitDescription(prefix),
+			)
+			return
+		}
+		find(prefix).rate = newRateLimiter(limit.rate, limit.burst)
+	}
+	for prefix, max := range b.concurrencyLimits {
+		err = checkLimitPrefix(prefix)
+		if err != nil {
+			return
+		}
+		if max < 1 {
+			err = fmt.Errorf(
+				"maximum number of concurrent requests %d for %s isn't valid, it "+
+					"must be one or greater",
+				max, limitDescription(prefix),
+			)
+			return
+		}
+		find(prefix).slots = make(chan struct{}, max)
+	}
+	return
+}
+
+// checkLimitPrefix checks that the given prefix is empty or an absolute path.
+func checkLimitPrefix(prefix string) error {
+	if prefix != "" && (!path.IsAbs(prefix) || path.Clean(prefix) != prefix) {
+		return fmt.Errorf(
+			"limit path '%s' isn't valid, it must be a clean absolute path like "+
+				"'/api/clusters_mgmt'",
+			prefix,
+		)
+	}
+	return nil
+}
+
+// limitDescription returns a description of the requests affected by a limit, for use in error
+// messages.
+func limitDescription(prefix string) string {
+	if prefix == "" {
+		return "all requests"
+	}
+	return fmt.Sprintf("path '%s'", prefix)
+}
+
+// selectLimiters returns the limiters that apply to a request for the given path. That is the
+// limiter for all requests, if it exists, and the one with the longest prefix matching the path.
+func (c *Connection) selectLimiters(path string) []*requestLimiter {
+	if len(c.limiters) == 0 {
+		return nil
+	}
+	var result []*requestLimiter
+	global, ok := c.limiters[""]
+	if ok {
+		result = append(result, global)
+	}
+	var selected *requestLimiter
+	for prefix, limiter := range c.limiters {
+		if prefix == "" {
+			continue
+		}
+		if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+		if selected == nil || len(prefix) > len(selected.prefix) {
+			selected = limiter
+		}
+	}
+	if selected != nil {
+		result = append(result, selected)
+	}
+	return result
+}
+
+// acquireLimits waits till the limiters allow sending a request. It returns a function that must be
+// called to release the concurrency slots when the request is finished.
+func (c *Connection) acquireLimits(ctx context.Context, limiters []*requestLimiter,
+	metric string) (release func(), err error) {
+	var acquired []*requestLimiter
+	release = func() {
+		for _, limiter := range acquired {
+			<-limiter.slots
+		}
+		acquired = nil
+	}
+
+	// Wait for the rate limiters:
+	limited := false
+	before := time.Now()
+	for _, limiter := range limiters {
+		if limiter.rate == nil {
+			continue
+		}
+		limited = true
+		err = limiter.rate.wait(ctx)
+		if err != nil {
+			err = fmt.Errorf("can't wait for rate limit: %v", err)
+			return
+		}
+	}
+	if limited {
+		c.updateWaitMetric(metricsLimitRate, metric, time.Since(before))
+	}
+
+	// Wait for the concurrency limiters:
+	limited = false
+	before = time.Now()
+	for _, limiter := range limiters {
+		if limiter.slots == nil {
+			continue
+		}
+		limited = true
+		select {
+		case limiter.slots <- struct{}{}:
+			acquired = append(acquired, limiter)
+		case <-ctx.Done():
+			release()
+			err = fmt.Errorf("can't wait for concurrency limit: %v", ctx.Err())
+			return
+		}
+	}
+	if limited {
+		c.updateWaitMetric(metricsLimitConcurrency, metric, time.Since(before))
+	}
+
+	return
+}
+
+// limitedBody is a response body that releases the concurrency slots of the request when it is
+// closed.
+type limitedBody struct {
+	io.ReadCloser
+	once    *sync.Once
+	release func()
+}
+
+// Close closes the underlying body and releases the concurrency slots.
+func (b *limitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// holdLimits makes sure that the concurrency slots are released when the request is finished. That
+// is when the response body is closed, or immediately if there is no response body.
+func holdLimits(response *http.Response, release func()) {
+	if response.Body == nil {
+		release()
+		return
+	}
+	response.Body = &limitedBody{
+		ReadCloser: response.Body,
+		once:       &sync.Once{},
+		release:    release,
+	}
+}
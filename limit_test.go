@@ -0,0 +1,272 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the rate and concurrency limits.
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ = Describe("Limits", func() {
+	// Server used during the tests:
+	var apiServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Access token used during the tests:
+	var accessToken string
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the token:
+		accessToken = DefaultToken("Bearer", 5*time.Minute)
+
+		// Create the API server, configured to always respond with success:
+		apiServer = ghttp.NewServer()
+		apiServer.SetAllowUnhandledRequests(true)
+		apiServer.SetUnhandledRequestStatusCode(http.StatusOK)
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		apiServer.Close()
+	})
+
+	// sendContext sends a request to the given path and returns the error:
+	sendContext := func(ctx context.Context, connection *Connection, path string) error {
+		_, err := connection.Get().Path(path).SendContext(ctx)
+		return err
+	}
+
+	// sendTimeout sends a request to the given path with a short timeout and returns the error:
+	sendTimeout := func(connection *Connection, path string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		return sendContext(ctx, connection, path)
+	}
+
+	It("Delays requests that exceed the rate limit", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			RateLimit(10, 1).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the requests, the first should go immediately and the rest should be delayed
+		// about 100 milliseconds each:
+		before := time.Now()
+		for i := 0; i < 3; i++ {
+			err = sendContext(context.Background(), connection, "/mypath")
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(time.Since(before)).To(BeNumerically(">=", 180*time.Millisecond))
+	})
+
+	It("Stops waiting for the rate limit when the context is cancelled", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			RateLimit(0.1, 1).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// The first request consumes the burst, so the second has to wait ten seconds:
+		err = sendContext(context.Background(), connection, "/mypath")
+		Expect(err).ToNot(HaveOccurred())
+		before := time.Now()
+		err = sendTimeout(connection, "/mypath")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("rate limit"))
+		Expect(time.Since(before)).To(BeNumerically("<", time.Second))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Limits the number of concurrent requests", func() {
+		// Configure the server so that the first request blocks till we release it:
+		unblock := make(chan struct{})
+		apiServer.AppendHandlers(
+			func(w http.ResponseWriter, r *http.Request) {
+				<-unblock
+			},
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			MaxConcurrentRequests(1).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the first request in the background and wait till the server receives it:
+		done := make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			done <- sendContext(context.Background(), connection, "/mypath")
+		}()
+		Eventually(apiServer.ReceivedRequests).Should(HaveLen(1))
+
+		// The second request should fail because it can't get a slot before the timeout:
+		err = sendTimeout(connection, "/mypath")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("concurrency limit"))
+
+		// Once the first request finishes the slot should be available again:
+		close(unblock)
+		Expect(<-done).ToNot(HaveOccurred())
+		err = sendTimeout(connection, "/mypath")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Applies path limits only to the matching requests", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			PathRateLimit("/api/clusters_mgmt", 0.1, 1).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// The first request consumes the burst of the clusters service:
+		err = sendTimeout(connection, "/api/clusters_mgmt/v1/clusters")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Requests for other services and for paths that only share a prefix aren't limited:
+		err = sendTimeout(connection, "/api/accounts_mgmt/v1/accounts")
+		Expect(err).ToNot(HaveOccurred())
+		err = sendTimeout(connection, "/api/clusters_mgmt_other")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Another request for the clusters service has to wait:
+		err = sendTimeout(connection, "/api/clusters_mgmt/v1/clusters")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("rate limit"))
+	})
+
+	It("Uses the longest matching path", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			PathRateLimit("/api", 0.1, 1).
+			PathRateLimit("/api/clusters_mgmt", 100, 10).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Requests for the clusters service use only the more specific limit:
+		for i := 0; i < 3; i++ {
+			err = sendTimeout(connection, "/api/clusters_mgmt/v1/clusters")
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+
+	It("Records the wait time", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			Metrics("limit_test").
+			RateLimit(100, 1).
+			MaxConcurrentRequests(1).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send a request:
+		err = sendContext(context.Background(), connection, "/mypath")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Check that there are samples for both kinds of limits:
+		families, err := prometheus.DefaultGatherer.Gather()
+		Expect(err).ToNot(HaveOccurred())
+		limits := map[string]uint64{}
+		for _, family := range families {
+			if family.GetName() != "limit_test_request_wait_duration" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "limit" {
+						limits[label.GetValue()] = metric.GetHistogram().GetSampleCount()
+					}
+				}
+			}
+		}
+		Expect(limits).To(HaveKeyWithValue("rate", BeNumerically(">", 0)))
+		Expect(limits).To(HaveKeyWithValue("concurrency", BeNumerically(">", 0)))
+	})
+
+	It("Rejects invalid limits", func() {
+		_, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			RateLimit(0, 1).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("rate limit"))
+
+		_, err = NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			PathMaxConcurrentRequests("/api/clusters_mgmt", 0).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("/api/clusters_mgmt"))
+
+		_, err = NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			PathRateLimit("api/clusters_mgmt/", 1, 1).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("isn't valid"))
+	})
+})
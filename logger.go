@@ -19,42 +19,10 @@ limitations under the License.
 package sdk
 
 import (
-	"context"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // Logger is the interface that must be implemented by objects that are used for logging by the
-// client. By default the client uses a logger based on the `glog` package, but that can be changed
-// using the `Logger` method of the builder.
-//
-// Note that the context is optional in most of the methods of the SDK, so implementations of this
-// interface must accept and handle smoothly calls to the Debug, Info, Warn and Error methods where
-// the ctx parameter is nil.
-type Logger interface {
-	// DebugEnabled returns true iff the debug level is enabled.
-	DebugEnabled() bool
-
-	// InfoEnabled returns true iff the information level is enabled.
-	InfoEnabled() bool
-
-	// WarnEnabled returns true iff the warning level is enabled.
-	WarnEnabled() bool
-
-	// ErrorEnabled returns true iff the error level is enabled.
-	ErrorEnabled() bool
-
-	// Debug sends to the log a debug message formatted using the fmt.Sprintf function and the
-	// given format and arguments.
-	Debug(ctx context.Context, format string, args ...interface{})
-
-	// Info sends to the log an information message formatted using the fmt.Sprintf function and
-	// the given format and arguments.
-	Info(ctx context.Context, format string, args ...interface{})
-
-	// Warn sends to the log a warning message formatted using the fmt.Sprintf function and the
-	// given format and arguments.
-	Warn(ctx context.Context, format string, args ...interface{})
-
-	// Error sends to the log an error message formatted using the fmt.Sprintf function and the
-	// given format and arguments.
-	Error(ctx context.Context, format string, args ...interface{})
-}
+// client. It is an alias of the logging.Logger interface, so that the same loggers can be used by
+// the client, by the authentication handler and by the generated servers.
+type Logger = logging.Logger
@@ -120,6 +120,17 @@ func RespondWithJSONTemplate(statusCode int, source string, args ...interface{})
 	)
 }
 
+// RespondWithJSON responds with the given status code and JSON body.
+func RespondWithJSON(statusCode int, body string) http.HandlerFunc {
+	return ghttp.RespondWith(
+		statusCode,
+		body,
+		http.Header{
+			"Content-Type": []string{"application/json"},
+		},
+	)
+}
+
 // DefaultToken generates a token issued by the default OpenID server and with the given type and
 // with the given life. If the life is zero the token will never expire. If the life is positive the
 // token will be valid, and expire after that time.  If the life is negative the token will be
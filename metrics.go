@@ -19,6 +19,8 @@ limitations under the License.
 package sdk
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -70,6 +72,25 @@ func (c *Connection) registerMetrics(subsystem string) error {
 		}
 	}
 
+	// Register the token event count metric:
+	c.tokenEventMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: subsystem,
+			Name:      "token_event_count",
+			Help:      "Number of token events.",
+		},
+		tokenEventMetricsLabels,
+	)
+	err = prometheus.Register(c.tokenEventMetric)
+	if err != nil {
+		registered, ok := err.(prometheus.AlreadyRegisteredError)
+		if ok {
+			c.tokenEventMetric = registered.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			return err
+		}
+	}
+
 	// Register the call count metric:
 	c.callCountMetric = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -114,14 +135,64 @@ func (c *Connection) registerMetrics(subsystem string) error {
 		}
 	}
 
+	// Description of the wait duration metric:
+	c.waitDurationMetric = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: subsystem,
+			Name:      "request_wait_duration",
+			Help:      "Time waiting for rate and concurrency limits in seconds.",
+			Buckets: []float64{
+				0.01,
+				0.1,
+				1.0,
+				10.0,
+				30.0,
+			},
+		},
+		waitMetricsLabels,
+	)
+	err = prometheus.Register(c.waitDurationMetric)
+	if err != nil {
+		registered, ok := err.(prometheus.AlreadyRegisteredError)
+		if ok {
+			c.waitDurationMetric = registered.ExistingCollector.(*prometheus.HistogramVec)
+		} else {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// updateTokenEventMetric increases the counter of the given token event.
+func (c *Connection) updateTokenEventMetric(event string) {
+	if c.tokenEventMetric == nil {
+		return
+	}
+	c.tokenEventMetric.With(map[string]string{
+		metricsEventLabel: event,
+	}).Inc()
+}
+
+// updateWaitMetric records the time that a request waited for the given kind of limit.
+func (c *Connection) updateWaitMetric(limit, metric string, elapsed time.Duration) {
+	if c.waitDurationMetric == nil {
+		return
+	}
+	c.waitDurationMetric.With(map[string]string{
+		metricsLimitLabel: limit,
+		metricsPathLabel:  metric,
+	}).Observe(elapsed.Seconds())
+}
+
 // Names of the labels added to metrics:
 const (
-	metricsCodeLabel   = "code"
-	metricsMethodLabel = "method"
-	metricsPathLabel   = "path"
+	metricsAttemptLabel = "attempt"
+	metricsCodeLabel    = "code"
+	metricsEventLabel   = "event"
+	metricsLimitLabel   = "limit"
+	metricsMethodLabel  = "method"
+	metricsPathLabel    = "path"
 )
 
 // Array of labels added to token metrics:
@@ -129,12 +200,37 @@ var tokenMetricsLabels = []string{
 	metricsCodeLabel,
 }
 
+// Array of labels added to token event metrics:
+var tokenEventMetricsLabels = []string{
+	metricsEventLabel,
+}
+
+// Values of the label of the token event metrics:
+const (
+	tokenEventRenewal        = "renewal"
+	tokenEventRenewalFailure = "renewal_failure"
+	tokenEventShared         = "shared"
+)
+
 // Array of labels added to call metrics:
 var callMetricsLabels = []string{
+	metricsAttemptLabel,
 	metricsCodeLabel,
 	metricsMethodLabel,
 	metricsPathLabel,
 }
 
+// Array of labels added to wait metrics:
+var waitMetricsLabels = []string{
+	metricsLimitLabel,
+	metricsPathLabel,
+}
+
+// Values of the label of the wait metrics:
+const (
+	metricsLimitConcurrency = "concurrency"
+	metricsLimitRate        = "rate"
+)
+
 // Name of the header that contains the metrics path:
 const metricHeader = "X-Metric"
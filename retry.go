@@ -0,0 +1,148 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the methods of the connection that decide if a request
+// should be retried and how long to wait before retrying it.
+
+package sdk
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// retryable checks if the request should be sent again after the given attempt. Requests are
+// retried only when the transport failed or when the server responded with a status code that
+// indicates a transient problem. Non idempotent requests are only retried if explicitly enabled.
+func (c *Connection) retryable(ctx context.Context, request *http.Request, attempt int,
+	response *http.Response, err error) bool {
+	// Check the attempts limit:
+	if attempt >= c.retryLimit {
+		return false
+	}
+
+	// Don't retry if the context has been cancelled or if it has expired:
+	if ctx.Err() != nil {
+		return false
+	}
+
+	// Check the method:
+	switch request.Method {
+	case http.MethodGet, http.MethodDelete:
+	default:
+		if !c.retryNonIdempotent {
+			return false
+		}
+	}
+
+	// Transport errors, like connection resets, can be retried:
+	if err != nil {
+		return true
+	}
+
+	// Check the status code:
+	switch response.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay calculates how long to wait before sending the next attempt. If the response contains
+// the `Retry-After` header then its value is used, but never more than the configured maximum, so
+// that a misbehaving server can't block the caller for an arbitrary amount of time. Otherwise the
+// delay grows exponentially with the number of attempts, up to the configured maximum, and with the
+// configured jitter applied.
+func (c *Connection) retryDelay(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		delay, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now())
+		if ok {
+			if c.retryMaxInterval > 0 && delay > c.retryMaxInterval {
+				delay = c.retryMaxInterval
+			}
+			return delay
+		}
+	}
+	delay := float64(c.retryInterval) * math.Pow(2, float64(attempt-1))
+	if c.retryMaxInterval > 0 && delay > float64(c.retryMaxInterval) {
+		delay = float64(c.retryMaxInterval)
+	}
+	if c.retryJitter > 0 {
+		// #nosec G404
+		delay = delay * (1 + c.retryJitter*(2*rand.Float64()-1))
+	}
+	return time.Duration(delay)
+}
+
+// retryWait waits for the given delay, or till the context is done. It returns an error if the
+// context was cancelled or expired before the delay elapsed.
+func retryWait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// discardBody reads and closes the body of a response that will not be returned to the caller, so
+// that the underlying connection can be reused.
+func discardBody(response *http.Response) {
+	if response == nil || response.Body == nil {
+		return
+	}
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	_ = response.Body.Close()
+}
+
+// parseRetryAfter parses the value of the `Retry-After` header, which can be a number of seconds
+// or an HTTP date. It returns the delay and a flag indicating if the value could be parsed.
+func parseRetryAfter(value string, now time.Time) (delay time.Duration, ok bool) {
+	if value == "" {
+		return
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		if seconds < 0 {
+			return
+		}
+		delay = time.Duration(seconds) * time.Second
+		ok = true
+		return
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return
+	}
+	delay = date.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	ok = true
+	return
+}
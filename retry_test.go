@@ -0,0 +1,316 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the retry support of the connection.
+
+package sdk
+
+import (
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Retry", func() {
+	// Server used during the tests:
+	var apiServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Access token used during the tests:
+	var accessToken string
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the token:
+		accessToken = DefaultToken("Bearer", 5*time.Minute)
+
+		// Create the API server:
+		apiServer = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		apiServer.Close()
+	})
+
+	// newConnection creates a connection that retries requests up to the given limit, with
+	// very short intervals so that tests run quickly.
+	newConnection := func(limit int) *ConnectionBuilder {
+		return NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			RetryLimit(limit).
+			RetryInterval(time.Millisecond).
+			RetryMaxInterval(10 * time.Millisecond)
+	}
+
+	It("Doesn't retry by default", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Get().
+			Path("/mypath").
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusServiceUnavailable))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Retries GET after transient status codes", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusTooManyRequests, nil),
+			ghttp.RespondWith(http.StatusBadGateway, nil),
+			ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+			ghttp.RespondWith(http.StatusGatewayTimeout, nil),
+			ghttp.RespondWith(http.StatusOK, "mybody"),
+		)
+
+		// Create the connection:
+		connection, err := newConnection(5).Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Get().
+			Path("/mypath").
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusOK))
+		Expect(response.String()).To(Equal("mybody"))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(5))
+	})
+
+	It("Doesn't retry other status codes", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusInternalServerError, nil),
+		)
+
+		// Create the connection:
+		connection, err := newConnection(3).Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Get().
+			Path("/mypath").
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusInternalServerError))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Returns the last response when the limit is reached", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusServiceUnavailable, "first"),
+			ghttp.RespondWith(http.StatusServiceUnavailable, "second"),
+		)
+
+		// Create the connection:
+		connection, err := newConnection(2).Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Get().
+			Path("/mypath").
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusServiceUnavailable))
+		Expect(response.String()).To(Equal("second"))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Doesn't retry POST by default", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+		)
+
+		// Create the connection:
+		connection, err := newConnection(3).Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Post().
+			Path("/mypath").
+			String("{}").
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusServiceUnavailable))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Sends the complete body in each attempt", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{"name":"mycluster"}`),
+				ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyJSON(`{"name":"mycluster"}`),
+				ghttp.RespondWith(http.StatusCreated, nil),
+			),
+		)
+
+		// Create the connection:
+		connection, err := newConnection(3).
+			RetryNonIdempotent(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Post().
+			Path("/mypath").
+			String(`{"name":"mycluster"}`).
+			Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusCreated))
+		Expect(apiServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Honours the Retry-After header", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(
+				http.StatusTooManyRequests,
+				nil,
+				http.Header{
+					"Retry-After": []string{"1"},
+				},
+			),
+			ghttp.RespondWith(http.StatusOK, nil),
+		)
+
+		// Create the connection:
+		connection, err := newConnection(2).
+			RetryMaxInterval(2 * time.Second).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		before := time.Now()
+		response, err := connection.Get().
+			Path("/mypath").
+			Send()
+		elapsed := time.Since(before)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusOK))
+		Expect(elapsed).To(BeNumerically(">=", time.Second))
+	})
+
+	It("Doesn't wait more than the maximum interval", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(
+				http.StatusServiceUnavailable,
+				nil,
+				http.Header{
+					"Retry-After": []string{"3600"},
+				},
+			),
+			ghttp.RespondWith(http.StatusOK, nil),
+		)
+
+		// Create the connection:
+		connection, err := newConnection(2).Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		before := time.Now()
+		response, err := connection.Get().
+			Path("/mypath").
+			Send()
+		elapsed := time.Since(before)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusOK))
+		Expect(elapsed).To(BeNumerically("<", time.Second))
+	})
+
+	It("Rejects negative retry limit", func() {
+		connection, err := newConnection(-1).Build()
+		Expect(err).To(HaveOccurred())
+		Expect(connection).To(BeNil())
+	})
+
+	It("Rejects jitter greater than one", func() {
+		connection, err := newConnection(1).
+			RetryJitter(2).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(connection).To(BeNil())
+	})
+
+	Describe("Retry-After parsing", func() {
+		now := time.Date(2020, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+		It("Accepts number of seconds", func() {
+			delay, ok := parseRetryAfter("120", now)
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(Equal(2 * time.Minute))
+		})
+
+		It("Accepts HTTP date", func() {
+			delay, ok := parseRetryAfter("Fri, 10 Jan 2020 12:00:30 GMT", now)
+			Expect(ok).To(BeTrue())
+			Expect(delay).To(Equal(30 * time.Second))
+		})
+
+		It("Rejects empty value", func() {
+			_, ok := parseRetryAfter("", now)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("Rejects junk", func() {
+			_, ok := parseRetryAfter("junk", now)
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
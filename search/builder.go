@@ -0,0 +1,225 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to build search expressions.
+
+package search
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Eq creates an expression that checks if the given attribute is equal to the given value. The
+// value can be a string, a number, a boolean, a time, or a value of a type derived from those, like
+// the enumerated types of the model. Times are converted to strings using the RFC3339 format. Any
+// other value is converted to a string using the fmt.Sprint function.
+func Eq(attribute string, value interface{}) *Comparison {
+	return compare(attribute, OperatorEq, value)
+}
+
+// Ne creates an expression that checks if the given attribute is not equal to the given value.
+func Ne(attribute string, value interface{}) *Comparison {
+	return compare(attribute, OperatorNe, value)
+}
+
+// Lt creates an expression that checks if the given attribute is less than the given value.
+func Lt(attribute string, value interface{}) *Comparison {
+	return compare(attribute, OperatorLt, value)
+}
+
+// Le creates an expression that checks if the given attribute is less than or equal to the given
+// value.
+func Le(attribute string, value interface{}) *Comparison {
+	return compare(attribute, OperatorLe, value)
+}
+
+// Gt creates an expression that checks if the given attribute is greater than the given value.
+func Gt(attribute string, value interface{}) *Comparison {
+	return compare(attribute, OperatorGt, value)
+}
+
+// Ge creates an expression that checks if the given attribute is greater than or equal to the
+// given value.
+func Ge(attribute string, value interface{}) *Comparison {
+	return compare(attribute, OperatorGe, value)
+}
+
+// Like creates an expression that checks if the given attribute matches the given pattern. In
+// the pattern the '%' character matches any sequence of characters and the '_' character matches
+// any single character.
+func Like(attribute string, pattern string) *Comparison {
+	return compare(attribute, OperatorLike, pattern)
+}
+
+// NotLike creates an expression that checks if the given attribute doesn't match the given
+// pattern.
+func NotLike(attribute string, pattern string) *Comparison {
+	return compare(attribute, OperatorNotLike, pattern)
+}
+
+// ILike creates an expression that checks if the given attribute matches the given pattern,
+// ignoring case.
+func ILike(attribute string, pattern string) *Comparison {
+	return compare(attribute, OperatorILike, pattern)
+}
+
+// NotILike creates an expression that checks if the given attribute doesn't match the given
+// pattern, ignoring case.
+func NotILike(attribute string, pattern string) *Comparison {
+	return compare(attribute, OperatorNotILike, pattern)
+}
+
+// In creates an expression that checks if the given attribute is equal to one of the given
+// values.
+func In(attribute string, values ...interface{}) *Comparison {
+	return compare(attribute, OperatorIn, values...)
+}
+
+// NotIn creates an expression that checks if the given attribute isn't equal to any of the given
+// values.
+func NotIn(attribute string, values ...interface{}) *Comparison {
+	return compare(attribute, OperatorNotIn, values...)
+}
+
+// IsNull creates an expression that checks if the given attribute has no value.
+func IsNull(attribute string) *NullCheck {
+	return &NullCheck{
+		Attribute: attribute,
+	}
+}
+
+// IsNotNull creates an expression that checks if the given attribute has a value.
+func IsNotNull(attribute string) *NullCheck {
+	return &NullCheck{
+		Attribute: attribute,
+		Negated:   true,
+	}
+}
+
+// And creates an expression that is true when all the given expressions are true. Nil operands
+// are ignored, so it is possible to conditionally add criteria. For example:
+//
+//	var owner search.Expression
+//	if user != "" {
+//		owner = search.Eq("creator.username", user)
+//	}
+//	expression := search.And(
+//		search.Eq("state", cmv1.ClusterStateReady),
+//		owner,
+//	)
+func And(operands ...Expression) *Conjunction {
+	return &Conjunction{
+		Operands: compact(operands),
+	}
+}
+
+// Or creates an expression that is true when at least one of the given expressions is true. Nil
+// operands are ignored.
+func Or(operands ...Expression) *Disjunction {
+	return &Disjunction{
+		Operands: compact(operands),
+	}
+}
+
+// Not creates an expression that is true when the given expression is false.
+func Not(operand Expression) *Negation {
+	return &Negation{
+		Operand: operand,
+	}
+}
+
+// compare creates a comparison, converting the given values to the types supported by literals.
+func compare(attribute string, operator Operator, values ...interface{}) *Comparison {
+	literals := make([]interface{}, len(values))
+	for i, value := range values {
+		literals[i] = normalizeLiteral(value)
+	}
+	return &Comparison{
+		Attribute: attribute,
+		Operator:  operator,
+		Values:    literals,
+	}
+}
+
+// compact returns a slice containing the expressions that aren't nil. Note that this needs
+// reflection because an interface containing a nil pointer isn't nil.
+func compact(expressions []Expression) []Expression {
+	var result []Expression
+	for _, expression := range expressions {
+		if expression == nil {
+			continue
+		}
+		value := reflect.ValueOf(expression)
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			continue
+		}
+		result = append(result, expression)
+	}
+	return result
+}
+
+// normalizeLiteral converts the given value to one of the types supported by literals: string,
+// int64, float64 or bool.
+func normalizeLiteral(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case string, int64, float64, bool:
+		return value
+	case time.Time:
+		return typed.Format(time.RFC3339)
+	case *time.Time:
+		if typed != nil {
+			return typed.Format(time.RFC3339)
+		}
+	case fmt.Stringer:
+		if reflect.ValueOf(value).Kind() != reflect.String {
+			return typed.String()
+		}
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.String:
+		return reflected.String()
+	case reflect.Bool:
+		return reflected.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return reflected.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(reflected.Uint())
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float()
+	}
+	return fmt.Sprint(value)
+}
+
+// formatLiteral generates the text of a literal value. Strings are enclosed in single quotes and
+// quotes inside them are doubled.
+func formatLiteral(value interface{}) string {
+	switch typed := normalizeLiteral(value).(type) {
+	case string:
+		return "'" + strings.Replace(typed, "'", "''", -1) + "'"
+	case int64:
+		return strconv.FormatInt(typed, 10)
+	case float64:
+		return strconv.FormatFloat(typed, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(typed)
+	}
+	return ""
+}
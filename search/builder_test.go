@@ -0,0 +1,180 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the functions that build search expressions.
+
+package search_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/search"
+)
+
+var _ = Describe("Builder", func() {
+	It("Generates comparisons", func() {
+		Expect(search.Eq("name", "mycluster").String()).To(Equal("name = 'mycluster'"))
+		Expect(search.Ne("name", "mycluster").String()).To(Equal("name <> 'mycluster'"))
+		Expect(search.Lt("nodes.compute", 3).String()).To(Equal("nodes.compute < 3"))
+		Expect(search.Le("nodes.compute", 3).String()).To(Equal("nodes.compute <= 3"))
+		Expect(search.Gt("nodes.compute", 3).String()).To(Equal("nodes.compute > 3"))
+		Expect(search.Ge("nodes.compute", 3).String()).To(Equal("nodes.compute >= 3"))
+		Expect(search.Like("name", "my%").String()).To(Equal("name like 'my%'"))
+		Expect(search.NotLike("name", "my%").String()).To(Equal("name not like 'my%'"))
+		Expect(search.ILike("name", "my%").String()).To(Equal("name ilike 'my%'"))
+		Expect(search.NotILike("name", "my%").String()).To(Equal("name not ilike 'my%'"))
+		Expect(search.IsNull("region").String()).To(Equal("region is null"))
+		Expect(search.IsNotNull("region").String()).To(Equal("region is not null"))
+	})
+
+	It("Generates lists of values", func() {
+		Expect(search.In("region.id", "us-east-1", "us-west-2").String()).To(
+			Equal("region.id in ('us-east-1', 'us-west-2')"),
+		)
+		Expect(search.NotIn("nodes.compute", 1, 2.5).String()).To(
+			Equal("nodes.compute not in (1, 2.5)"),
+		)
+	})
+
+	It("Doubles quotes inside strings", func() {
+		Expect(search.Eq("name", "my' or name like '%").String()).To(
+			Equal("name = 'my'' or name like ''%'"),
+		)
+	})
+
+	It("Converts values of derived types, booleans and times", func() {
+		date := time.Date(2020, time.November, 3, 10, 30, 0, 0, time.UTC)
+		Expect(search.Eq("state", cmv1.ClusterStateReady).String()).To(Equal("state = 'ready'"))
+		Expect(search.Eq("managed", true).String()).To(Equal("managed = true"))
+		Expect(search.Gt("creation_timestamp", date).String()).To(
+			Equal("creation_timestamp > '2020-11-03T10:30:00Z'"),
+		)
+	})
+
+	It("Adds parentheses only when needed", func() {
+		expression := search.And(
+			search.Eq("a", 1),
+			search.Or(search.Eq("b", 2), search.And(search.Eq("c", 3), search.Eq("d", 4))),
+			search.Not(search.Eq("e", 5)),
+		)
+		Expect(expression.String()).To(
+			Equal("a = 1 and (b = 2 or c = 3 and d = 4) and not (e = 5)"),
+		)
+	})
+
+	It("Ignores nil operands", func() {
+		var missing *search.Comparison
+		expression := search.And(nil, search.Eq("name", "mycluster"), missing)
+		Expect(expression.Operands).To(HaveLen(1))
+		Expect(expression.String()).To(Equal("name = 'mycluster'"))
+	})
+
+	It("Generates empty text for empty conjunction", func() {
+		Expect(search.And().String()).To(BeEmpty())
+	})
+
+	It("Rejects attribute names that aren't valid", func() {
+		Expect(func() {
+			_ = search.Eq("name = 'x' or name", "y").String()
+		}).To(Panic())
+		Expect(func() {
+			_ = search.In("", "x").String()
+		}).To(Panic())
+		Expect(func() {
+			_ = search.Gt("1nodes", 1).String()
+		}).To(Panic())
+		Expect(func() {
+			_ = search.IsNull("region id").String()
+		}).To(Panic())
+		Expect(func() {
+			_ = search.IsNotNull("not").String()
+		}).To(Panic())
+	})
+
+	It("Rejects comparisons without value", func() {
+		Expect(func() {
+			_ = (&search.Comparison{
+				Attribute: "name",
+				Operator:  search.OperatorEq,
+			}).String()
+		}).To(Panic())
+	})
+
+	It("Generates text for expressions that are always true or false", func() {
+		Expect(search.Or().String()).To(Equal("false"))
+		Expect(search.In("region.id").String()).To(Equal("false"))
+		Expect(search.NotIn("region.id").String()).To(Equal("region.id is not null"))
+		Expect(search.Not(search.And()).String()).To(Equal("false"))
+		Expect(search.Not(search.Or()).String()).To(BeEmpty())
+		Expect(search.And(search.Eq("a", 1), search.Or()).String()).To(Equal("false"))
+		Expect(search.And(search.Eq("a", 1), search.And()).String()).To(Equal("a = 1"))
+		Expect(search.Or(search.Eq("a", 1), search.And()).String()).To(BeEmpty())
+		Expect(search.Or(search.Eq("a", 1), search.In("b")).String()).To(Equal("a = 1"))
+	})
+
+	It("Generates text that can be parsed again", func() {
+		expression := search.And(
+			search.Eq("name", "it's"),
+			search.Or(search.In("region.id", "us-east-1", "eu-west-1"), search.IsNull("region")),
+			search.Not(search.Like("name", "%test%")),
+			search.Ge("nodes.compute", -2.5e10),
+		)
+		parsed, err := search.Parse(expression.String())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed).To(Equal(expression))
+	})
+
+	It("Generates text that is parsed into an equivalent expression", func() {
+		expressions := []search.Expression{
+			search.And(),
+			search.Or(),
+			search.In("b", "x", "y"),
+			search.In("b"),
+			search.NotIn("b"),
+			search.Not(search.And()),
+			search.Not(search.Or()),
+			search.Not(search.In("b")),
+			search.And(search.Eq("a", 1), search.Or()),
+			search.And(search.Eq("a", 1), search.Not(search.Or())),
+			search.Or(search.Eq("a", 1), search.Not(search.And())),
+			search.Or(search.Eq("a", 1), search.And()),
+			search.And(search.Or(search.Eq("a", 1), search.IsNull("b")), search.Ne("b", "x")),
+			search.Not(search.Or(search.Eq("a", 1), search.NotIn("b", "x"))),
+		}
+		objects := []map[string]interface{}{
+			{"a": 1.0, "b": "x"},
+			{"a": 2.0, "b": "y"},
+			{"a": 1.0, "b": nil},
+		}
+		for _, expression := range expressions {
+			text := expression.String()
+			parsed, err := search.Parse(text)
+			Expect(err).ToNot(HaveOccurred(), "text is '%s'", text)
+			Expect(parsed.String()).To(Equal(text))
+			for _, object := range objects {
+				expected, err := search.Match(expression, object)
+				Expect(err).ToNot(HaveOccurred())
+				actual, err := search.Match(parsed, object)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(actual).To(Equal(expected), "text is '%s' and object is %v", text, object)
+			}
+		}
+	})
+})
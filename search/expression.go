@@ -0,0 +1,243 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package search contains support for the language used in the 'search' parameter of the list
+// methods. It contains functions to build search expressions with the values correctly quoted,
+// a parser that converts the text of a search expression into a syntax tree, and an evaluator
+// that checks if an object matches a search expression.
+//
+// Clients can use the builder functions instead of composing the text of the expression with
+// fmt.Sprintf, so that values containing quotes are correctly escaped. For example:
+//
+//	expression := search.And(
+//		search.Like("name", "my%"),
+//		search.In("region.id", "us-east-1", "us-west-2"),
+//	)
+//	response, err := connection.ClustersMgmt().V1().Clusters().List().
+//		Search(expression.String()).
+//		Send()
+//
+// Servers can parse the search parameter and use the result to select the objects:
+//
+//	expression, err := search.Parse(request.Search())
+//	if err != nil {
+//		return errors.NewBadRequest("%v", err)
+//	}
+//	for _, cluster := range clusters {
+//		matched, err := search.Match(expression, cluster)
+//		...
+//	}
+package search // github.com/openshift-online/ocm-sdk-go/search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Expression is the interface implemented by all the nodes of the syntax tree of a search
+// expression.
+type Expression interface {
+	// String generates the text of the expression, in a format that can be parsed again and
+	// used in the 'search' parameter of list methods.
+	String() string
+
+	// expression is only used to make sure that only the types of this package implement the
+	// interface.
+	expression()
+}
+
+// Operator represents the operators that can be used to compare an attribute with values.
+type Operator string
+
+const (
+	OperatorEq       Operator = "="
+	OperatorNe       Operator = "<>"
+	OperatorLt       Operator = "<"
+	OperatorLe       Operator = "<="
+	OperatorGt       Operator = ">"
+	OperatorGe       Operator = ">="
+	OperatorLike     Operator = "like"
+	OperatorNotLike  Operator = "not like"
+	OperatorILike    Operator = "ilike"
+	OperatorNotILike Operator = "not ilike"
+	OperatorIn       Operator = "in"
+	OperatorNotIn    Operator = "not in"
+)
+
+// Comparison is an expression that compares the value of an attribute with one value, or with a
+// list of values for the 'in' and 'not in' operators. The attribute is the name of the attribute,
+// and it can contain dots to refer to attributes of nested objects, for example 'region.id'.
+// Values are strings, 64 bits integers, 64 bits floating point numbers or booleans.
+//
+// The String method panics if the name of the attribute isn't a valid identifier of the language,
+// or if the operator isn't 'in' or 'not in' and there isn't exactly one value.
+type Comparison struct {
+	Attribute string
+	Operator  Operator
+	Values    []interface{}
+}
+
+// NullCheck is an expression that checks if an attribute has no value, or if it has a value when
+// Negated is true. The String method panics if the name of the attribute isn't a valid identifier
+// of the language.
+type NullCheck struct {
+	Attribute string
+	Negated   bool
+}
+
+// Conjunction is an expression that is true when all its operands are true. A conjunction without
+// operands is always true, and its text is empty, like the text of any other expression that is
+// always true.
+type Conjunction struct {
+	Operands []Expression
+}
+
+// Disjunction is an expression that is true when at least one of its operands is true. A
+// disjunction without operands is always false, and its text is 'false', like the text of any
+// other expression that is always false.
+type Disjunction struct {
+	Operands []Expression
+}
+
+// Negation is an expression that is true when its operand is false.
+type Negation struct {
+	Operand Expression
+}
+
+func (e *Comparison) expression()  {}
+func (e *NullCheck) expression()   {}
+func (e *Conjunction) expression() {}
+func (e *Disjunction) expression() {}
+func (e *Negation) expression()    {}
+
+// String is the implementation of the Expression interface. An 'in' comparison without values is
+// always false, and a 'not in' comparison without values is true when the attribute isn't null, so
+// they are written like that.
+func (e *Comparison) String() string {
+	checkAttribute(e.Attribute)
+	switch e.Operator {
+	case OperatorIn, OperatorNotIn:
+		if len(e.Values) == 0 {
+			if e.Operator == OperatorIn {
+				return falseText
+			}
+			return e.Attribute + " is not null"
+		}
+	default:
+		if len(e.Values) != 1 {
+			panic(fmt.Sprintf(
+				"operator '%s' of attribute '%s' needs one value but has %d",
+				e.Operator, e.Attribute, len(e.Values),
+			))
+		}
+	}
+	buffer := &strings.Builder{}
+	buffer.WriteString(e.Attribute)
+	buffer.WriteString(" ")
+	buffer.WriteString(string(e.Operator))
+	buffer.WriteString(" ")
+	switch e.Operator {
+	case OperatorIn, OperatorNotIn:
+		buffer.WriteString("(")
+		for i, value := range e.Values {
+			if i > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(formatLiteral(value))
+		}
+		buffer.WriteString(")")
+	default:
+		buffer.WriteString(formatLiteral(e.Values[0]))
+	}
+	return buffer.String()
+}
+
+// String is the implementation of the Expression interface.
+func (e *NullCheck) String() string {
+	checkAttribute(e.Attribute)
+	if e.Negated {
+		return e.Attribute + " is not null"
+	}
+	return e.Attribute + " is null"
+}
+
+// String is the implementation of the Expression interface. Operands that are always true are
+// omitted, and if any operand is always false then the result is 'false'.
+func (e *Conjunction) String() string {
+	var chunks []string
+	for _, operand := range e.Operands {
+		text := operand.String()
+		if text == "" {
+			continue
+		}
+		if text == falseText {
+			return falseText
+		}
+		if _, ok := operand.(*Disjunction); ok {
+			text = "(" + text + ")"
+		}
+		chunks = append(chunks, text)
+	}
+	return strings.Join(chunks, " and ")
+}
+
+// String is the implementation of the Expression interface. Operands that are always false are
+// omitted, and if any operand is always true then the result is empty.
+func (e *Disjunction) String() string {
+	var chunks []string
+	for _, operand := range e.Operands {
+		text := operand.String()
+		if text == "" {
+			return ""
+		}
+		if text == falseText {
+			continue
+		}
+		chunks = append(chunks, text)
+	}
+	if len(chunks) == 0 {
+		return falseText
+	}
+	return strings.Join(chunks, " or ")
+}
+
+// String is the implementation of the Expression interface. The negation of an expression that is
+// always true is 'false', and the negation of an expression that is always false is empty.
+func (e *Negation) String() string {
+	text := e.Operand.String()
+	switch text {
+	case "":
+		return falseText
+	case falseText:
+		return ""
+	}
+	return "not (" + text + ")"
+}
+
+// checkAttribute panics if the given attribute name isn't a valid identifier of the language, as
+// otherwise the generated text could mean something different, or not be valid at all.
+func checkAttribute(name string) {
+	if !attributeRE.MatchString(name) || isKeyword(name) {
+		panic(fmt.Sprintf("attribute name '%s' isn't valid", name))
+	}
+}
+
+// attributeRE is the regular expression used to check the names of attributes.
+var attributeRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// falseText is the text of expressions that are always false.
+const falseText = "false"
@@ -0,0 +1,389 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the evaluator that checks if objects match search expressions.
+
+package search
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Match checks if the given object matches the given search expression. The object can be one of
+// the types of the model, like *cmv1.Cluster, or a map containing the result of decoding the JSON
+// representation of an object.
+//
+// For the types of the model the values of the attributes are obtained calling the methods that
+// have the same name than the attribute, ignoring case and underscores. For example, the value of
+// the 'region.id' attribute of a cluster is obtained calling the Region and ID methods. Attributes
+// that aren't set are null. An error is returned when an attribute doesn't exist.
+//
+// Comparisons of attributes that are null are always false, use 'is null' to check them. An error
+// is returned when the type of an attribute and the type of the value that it is compared with
+// don't match. Attributes that contain times can be compared with strings containing dates in
+// RFC3339 format, and boolean attributes can be compared with the strings accepted by the
+// strconv.ParseBool function, like 't' and 'f'.
+func Match(expression Expression, object interface{}) (result bool, err error) {
+	switch typed := expression.(type) {
+	case *Conjunction:
+		for _, operand := range typed.Operands {
+			result, err = Match(operand, object)
+			if err != nil || !result {
+				return
+			}
+		}
+		result = true
+	case *Disjunction:
+		for _, operand := range typed.Operands {
+			result, err = Match(operand, object)
+			if err != nil || result {
+				return
+			}
+		}
+		result = false
+	case *Negation:
+		result, err = Match(typed.Operand, object)
+		result = !result
+	case *NullCheck:
+		var value interface{}
+		value, err = lookup(object, typed.Attribute)
+		if err != nil {
+			return
+		}
+		result = value == nil
+		if typed.Negated {
+			result = !result
+		}
+	case *Comparison:
+		result, err = matchComparison(typed, object)
+	default:
+		err = fmt.Errorf("don't know how to evaluate expression of type %T", expression)
+	}
+	return
+}
+
+// matchComparison checks if the given object matches the given comparison.
+func matchComparison(comparison *Comparison, object interface{}) (result bool, err error) {
+	value, err := lookup(object, comparison.Attribute)
+	if err != nil || value == nil {
+		return
+	}
+	switch comparison.Operator {
+	case OperatorIn, OperatorNotIn:
+		for _, literal := range comparison.Values {
+			var order int
+			order, err = compareValues(comparison.Attribute, value, literal)
+			if err != nil {
+				return
+			}
+			if order == 0 {
+				result = true
+				break
+			}
+		}
+		if comparison.Operator == OperatorNotIn {
+			result = !result
+		}
+		return
+	}
+	if len(comparison.Values) != 1 {
+		err = fmt.Errorf(
+			"operator '%s' of attribute '%s' needs one value but has %d",
+			comparison.Operator, comparison.Attribute, len(comparison.Values),
+		)
+		return
+	}
+	literal := comparison.Values[0]
+	switch comparison.Operator {
+	case OperatorLike, OperatorNotLike, OperatorILike, OperatorNotILike:
+		text, ok := value.(string)
+		if !ok {
+			err = fmt.Errorf(
+				"operator '%s' can't be used with attribute '%s' because it isn't a string",
+				comparison.Operator, comparison.Attribute,
+			)
+			return
+		}
+		pattern, ok := literal.(string)
+		if !ok {
+			err = fmt.Errorf(
+				"pattern of attribute '%s' should be a string",
+				comparison.Attribute,
+			)
+			return
+		}
+		fold := comparison.Operator == OperatorILike ||
+			comparison.Operator == OperatorNotILike
+		negated := comparison.Operator == OperatorNotLike ||
+			comparison.Operator == OperatorNotILike
+		var matcher *regexp.Regexp
+		matcher, err = compileLike(pattern, fold)
+		if err != nil {
+			return
+		}
+		result = matcher.MatchString(text) != negated
+		return
+	}
+	_, boolean := value.(bool)
+	if boolean && comparison.Operator != OperatorEq && comparison.Operator != OperatorNe {
+		err = fmt.Errorf(
+			"operator '%s' can't be used with attribute '%s' because it is a boolean",
+			comparison.Operator, comparison.Attribute,
+		)
+		return
+	}
+	order, err := compareValues(comparison.Attribute, value, literal)
+	if err != nil {
+		return
+	}
+	switch comparison.Operator {
+	case OperatorEq:
+		result = order == 0
+	case OperatorNe:
+		result = order != 0
+	case OperatorLt:
+		result = order < 0
+	case OperatorLe:
+		result = order <= 0
+	case OperatorGt:
+		result = order > 0
+	case OperatorGe:
+		result = order >= 0
+	default:
+		err = fmt.Errorf("unknown operator '%s'", comparison.Operator)
+	}
+	return
+}
+
+// compareValues compares the value of an attribute with a literal value. It returns a negative
+// number if the value is less than the literal, zero if they are equal and a positive number if
+// the value is greater than the literal.
+func compareValues(attribute string, value, literal interface{}) (result int, err error) {
+	literal = normalizeLiteral(literal)
+	switch typed := value.(type) {
+	case string:
+		text, ok := literal.(string)
+		if ok {
+			result = strings.Compare(typed, text)
+			return
+		}
+	case float64:
+		var number float64
+		ok := true
+		switch literal := literal.(type) {
+		case int64:
+			number = float64(literal)
+		case float64:
+			number = literal
+		default:
+			ok = false
+		}
+		if ok {
+			switch {
+			case typed < number:
+				result = -1
+			case typed > number:
+				result = 1
+			}
+			return
+		}
+	case bool:
+		flag, ok := literal.(bool)
+		if !ok {
+			var text string
+			text, ok = literal.(string)
+			if ok {
+				flag, err = strconv.ParseBool(text)
+				if err != nil {
+					err = fmt.Errorf(
+						"value '%s' of attribute '%s' isn't a valid boolean",
+						text, attribute,
+					)
+					return
+				}
+			}
+		}
+		if ok {
+			switch {
+			case typed == flag:
+				result = 0
+			case !typed:
+				result = -1
+			default:
+				result = 1
+			}
+			return
+		}
+	case time.Time:
+		text, ok := literal.(string)
+		if ok {
+			var date time.Time
+			date, err = time.Parse(time.RFC3339, text)
+			if err != nil {
+				err = fmt.Errorf(
+					"value '%s' of attribute '%s' isn't a valid RFC3339 date",
+					text, attribute,
+				)
+				return
+			}
+			switch {
+			case typed.Before(date):
+				result = -1
+			case typed.After(date):
+				result = 1
+			}
+			return
+		}
+	default:
+		err = fmt.Errorf(
+			"attribute '%s' can't be compared because its type is %T",
+			attribute, value,
+		)
+		return
+	}
+	err = fmt.Errorf(
+		"attribute '%s' can't be compared with value %s",
+		attribute, formatLiteral(literal),
+	)
+	return
+}
+
+// lookup returns the value of the given attribute of the given object. The result is nil if the
+// attribute isn't set, a string, a float64, a bool or a time for scalar attributes, and the
+// object itself for other attributes.
+func lookup(object interface{}, attribute string) (result interface{}, err error) {
+	result = object
+	for _, name := range strings.Split(attribute, ".") {
+		result, err = lookupField(result, name)
+		if err != nil {
+			err = fmt.Errorf("can't get value of attribute '%s': %v", attribute, err)
+			return
+		}
+		if result == nil {
+			return
+		}
+	}
+	result = normalizeValue(result)
+	return
+}
+
+// lookupField returns the value of the field with the given name of the given object.
+func lookupField(object interface{}, name string) (result interface{}, err error) {
+	if object == nil {
+		return
+	}
+	value := reflect.ValueOf(object)
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			err = fmt.Errorf("type %T doesn't have field '%s'", object, name)
+			return
+		}
+		field := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+		if field.IsValid() {
+			result = field.Interface()
+		}
+		return
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return
+		}
+	}
+
+	// Find the methods that return the value of the attribute, preferring the one that also
+	// tells if the attribute has a value:
+	key := strings.ToLower(strings.Replace(name, "_", "", -1))
+	var plain reflect.Value
+	var checked reflect.Value
+	for i := 0; i < value.NumMethod(); i++ {
+		method := value.Type().Method(i)
+		lower := strings.ToLower(method.Name)
+		if method.Type.NumIn() != 1 {
+			continue
+		}
+		switch {
+		case lower == key && method.Type.NumOut() == 1:
+			plain = value.Method(i)
+		case lower == "get"+key && method.Type.NumOut() == 2 &&
+			method.Type.Out(1).Kind() == reflect.Bool:
+			checked = value.Method(i)
+		}
+	}
+	switch {
+	case checked.IsValid():
+		outputs := checked.Call(nil)
+		if outputs[1].Bool() {
+			result = outputs[0].Interface()
+		}
+	case plain.IsValid():
+		result = plain.Call(nil)[0].Interface()
+	default:
+		err = fmt.Errorf("type %T doesn't have field '%s'", object, name)
+	}
+	return
+}
+
+// normalizeValue converts the value of an attribute to the types used for comparisons: string,
+// float64, bool or time. Values of other types, like nested objects, are returned as they are.
+func normalizeValue(value interface{}) interface{} {
+	if _, ok := value.(time.Time); ok {
+		return value
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.String:
+		return reflected.String()
+	case reflect.Bool:
+		return reflected.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint())
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float()
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if reflected.IsNil() {
+			return nil
+		}
+	}
+	return value
+}
+
+// compileLike converts a like pattern into a regular expression.
+func compileLike(pattern string, fold bool) (*regexp.Regexp, error) {
+	buffer := &strings.Builder{}
+	if fold {
+		buffer.WriteString("(?i)")
+	}
+	buffer.WriteString("^")
+	for _, char := range pattern {
+		switch char {
+		case '%':
+			buffer.WriteString(".*")
+		case '_':
+			buffer.WriteString(".")
+		default:
+			buffer.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	buffer.WriteString("$")
+	return regexp.Compile("(?s)" + buffer.String())
+}
@@ -0,0 +1,198 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the evaluator of search expressions.
+
+package search_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/search"
+)
+
+var _ = Describe("Match", func() {
+	var cluster *cmv1.Cluster
+
+	BeforeEach(func() {
+		var err error
+		cluster, err = cmv1.NewCluster().
+			ID("123").
+			Name("MyCluster").
+			ExternalID("my-uuid").
+			State(cmv1.ClusterStateReady).
+			Managed(true).
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).
+			Nodes(cmv1.NewClusterNodes().Compute(3)).
+			CreationTimestamp(time.Date(2020, time.November, 3, 10, 30, 0, 0, time.UTC)).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// match parses the given text and evaluates it against the cluster:
+	match := func(text string) bool {
+		expression, err := search.Parse(text)
+		Expect(err).ToNot(HaveOccurred())
+		result, err := search.Match(expression, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	// fail parses the given text, evaluates it against the cluster and returns the error:
+	fail := func(text string) error {
+		expression, err := search.Parse(text)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = search.Match(expression, cluster)
+		Expect(err).To(HaveOccurred())
+		return err
+	}
+
+	It("Matches everything with empty expression", func() {
+		Expect(match("")).To(BeTrue())
+	})
+
+	It("Compares strings", func() {
+		Expect(match("name = 'MyCluster'")).To(BeTrue())
+		Expect(match("name <> 'MyCluster'")).To(BeFalse())
+		Expect(match("external_id = 'my-uuid'")).To(BeTrue())
+		Expect(match("name < 'N'")).To(BeTrue())
+	})
+
+	It("Compares nested attributes", func() {
+		Expect(match("region.id = 'us-east-1'")).To(BeTrue())
+		Expect(match("region.id in ('eu-west-1', 'us-west-2')")).To(BeFalse())
+		Expect(match("region.id not in ('eu-west-1', 'us-west-2')")).To(BeTrue())
+	})
+
+	It("Compares enumerated values", func() {
+		Expect(match("state = 'ready'")).To(BeTrue())
+		Expect(match("state in ('installing', 'uninstalling')")).To(BeFalse())
+	})
+
+	It("Compares numbers", func() {
+		Expect(match("nodes.compute = 3")).To(BeTrue())
+		Expect(match("nodes.compute > 2.5")).To(BeTrue())
+		Expect(match("nodes.compute <= 2")).To(BeFalse())
+	})
+
+	It("Compares booleans", func() {
+		Expect(match("managed = true")).To(BeTrue())
+		Expect(match("managed = 'f'")).To(BeFalse())
+		Expect(match("managed = 't'")).To(BeTrue())
+	})
+
+	It("Compares times", func() {
+		Expect(match("creation_timestamp > '2020-11-01T00:00:00Z'")).To(BeTrue())
+		Expect(match("creation_timestamp < '2020-11-01T00:00:00Z'")).To(BeFalse())
+	})
+
+	It("Matches patterns", func() {
+		Expect(match("name like 'My%'")).To(BeTrue())
+		Expect(match("name like 'my%'")).To(BeFalse())
+		Expect(match("name ilike 'my%'")).To(BeTrue())
+		Expect(match("name not ilike 'my%'")).To(BeFalse())
+		Expect(match("name like 'My_luster'")).To(BeTrue())
+		Expect(match("name like 'My.*'")).To(BeFalse())
+	})
+
+	It("Checks null values", func() {
+		Expect(match("region is not null")).To(BeTrue())
+		Expect(match("version is null")).To(BeTrue())
+		Expect(match("display_name is null")).To(BeTrue())
+	})
+
+	It("Doesn't match null values in comparisons", func() {
+		Expect(match("display_name = ''")).To(BeFalse())
+		Expect(match("display_name <> ''")).To(BeFalse())
+		Expect(match("version.id = 'openshift-v4.5.16'")).To(BeFalse())
+	})
+
+	It("Evaluates logical operators", func() {
+		Expect(match("name = 'MyCluster' and state = 'ready'")).To(BeTrue())
+		Expect(match("name = 'Other' and state = 'ready'")).To(BeFalse())
+		Expect(match("name = 'Other' or state = 'ready'")).To(BeTrue())
+		Expect(match("not (name = 'Other')")).To(BeTrue())
+	})
+
+	It("Evaluates expressions created with the builder", func() {
+		expression := search.And(
+			search.Eq("state", cmv1.ClusterStateReady),
+			search.Ge("nodes.compute", 3),
+			search.Lt("creation_timestamp", time.Now()),
+		)
+		result, err := search.Match(expression, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeTrue())
+	})
+
+	It("Evaluates maps", func() {
+		object := map[string]interface{}{
+			"name": "mycluster",
+			"region": map[string]interface{}{
+				"id": "us-east-1",
+			},
+			"nodes": map[string]interface{}{
+				"compute": 3.0,
+			},
+		}
+		expression, err := search.Parse(
+			"name = 'mycluster' and region.id = 'us-east-1' and nodes.compute = 3 and " +
+				"version is null",
+		)
+		Expect(err).ToNot(HaveOccurred())
+		result, err := search.Match(expression, object)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeTrue())
+	})
+
+	It("Fails if attribute doesn't exist", func() {
+		err := fail("junk = 'x'")
+		Expect(err.Error()).To(Equal(
+			"can't get value of attribute 'junk': type *v1.Cluster doesn't have field 'junk'",
+		))
+	})
+
+	It("Fails if types don't match", func() {
+		err := fail("name = 1")
+		Expect(err.Error()).To(Equal("attribute 'name' can't be compared with value 1"))
+	})
+
+	It("Fails if boolean is compared with order operator", func() {
+		err := fail("managed > false")
+		Expect(err.Error()).To(Equal(
+			"operator '>' can't be used with attribute 'managed' because it is a boolean",
+		))
+	})
+
+	It("Fails if date isn't valid", func() {
+		err := fail("creation_timestamp > 'yesterday'")
+		Expect(err.Error()).To(Equal(
+			"value 'yesterday' of attribute 'creation_timestamp' isn't a valid RFC3339 date",
+		))
+	})
+
+	It("Fails if pattern is used with attribute that isn't a string", func() {
+		err := fail("nodes.compute like '3%'")
+		Expect(err.Error()).To(Equal(
+			"operator 'like' can't be used with attribute 'nodes.compute' because it " +
+				"isn't a string",
+		))
+	})
+})
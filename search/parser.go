@@ -0,0 +1,462 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the parser that converts the text of a search expression into a syntax tree.
+// The grammar is the following, where keywords aren't case sensitive:
+//
+//	expression  = disjunction
+//	disjunction = conjunction { "or" conjunction }
+//	conjunction = negation { "and" negation }
+//	negation    = "not" negation | primary
+//	primary     = "(" expression ")" | "true" | "false" | comparison
+//	comparison  = attribute operator literal
+//	            | attribute [ "not" ] ( "like" | "ilike" ) string
+//	            | attribute [ "not" ] "in" "(" literal { "," literal } ")"
+//	            | attribute "is" [ "not" ] "null"
+//	operator    = "=" | "<>" | "!=" | "<" | "<=" | ">" | ">="
+//	attribute   = identifier { "." identifier }
+//	literal     = string | number | "true" | "false"
+
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parse parses the given text and returns the syntax tree of the search expression. An empty text
+// results in an empty conjunction, which matches all the objects.
+func Parse(text string) (result Expression, err error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return
+	}
+	if len(tokens) == 1 {
+		result = &Conjunction{}
+		return
+	}
+	parser := &parser{
+		tokens: tokens,
+	}
+	result, err = parser.parseDisjunction()
+	if err != nil {
+		return
+	}
+	if !parser.at(tokenEnd) {
+		err = parser.unexpected("'and', 'or' or end of expression")
+		return
+	}
+	return
+}
+
+// tokenKind represents the kinds of tokens of the language.
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenIdentifier
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+// token is a piece of the text of the expression, together with its kind and position.
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// parser contains the state of the parser.
+type parser struct {
+	tokens  []token
+	current int
+}
+
+func (p *parser) parseDisjunction() (result Expression, err error) {
+	operand, err := p.parseConjunction()
+	if err != nil {
+		return
+	}
+	operands := []Expression{operand}
+	for p.atKeyword("or") {
+		p.current++
+		operand, err = p.parseConjunction()
+		if err != nil {
+			return
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		result = operand
+		return
+	}
+	result = &Disjunction{
+		Operands: operands,
+	}
+	return
+}
+
+func (p *parser) parseConjunction() (result Expression, err error) {
+	operand, err := p.parseNegation()
+	if err != nil {
+		return
+	}
+	operands := []Expression{operand}
+	for p.atKeyword("and") {
+		p.current++
+		operand, err = p.parseNegation()
+		if err != nil {
+			return
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		result = operand
+		return
+	}
+	result = &Conjunction{
+		Operands: operands,
+	}
+	return
+}
+
+func (p *parser) parseNegation() (result Expression, err error) {
+	if !p.atKeyword("not") {
+		result, err = p.parsePrimary()
+		return
+	}
+	p.current++
+	operand, err := p.parseNegation()
+	if err != nil {
+		return
+	}
+	result = &Negation{
+		Operand: operand,
+	}
+	return
+}
+
+func (p *parser) parsePrimary() (result Expression, err error) {
+	switch {
+	case p.atKeyword("true"):
+		p.current++
+		result = &Conjunction{}
+		return
+	case p.atKeyword("false"):
+		p.current++
+		result = &Disjunction{}
+		return
+	case !p.atSymbol("("):
+		result, err = p.parseComparison()
+		return
+	}
+	p.current++
+	result, err = p.parseDisjunction()
+	if err != nil {
+		return
+	}
+	if !p.atSymbol(")") {
+		err = p.unexpected("')'")
+		return
+	}
+	p.current++
+	return
+}
+
+func (p *parser) parseComparison() (result Expression, err error) {
+	// Get the name of the attribute:
+	if !p.at(tokenIdentifier) || isKeyword(p.peek().text) {
+		err = p.unexpected("attribute name")
+		return
+	}
+	attribute := p.peek().text
+	p.current++
+
+	// Check if this is a null check:
+	if p.atKeyword("is") {
+		p.current++
+		negated := false
+		if p.atKeyword("not") {
+			negated = true
+			p.current++
+		}
+		if !p.atKeyword("null") {
+			err = p.unexpected("'null'")
+			return
+		}
+		p.current++
+		result = &NullCheck{
+			Attribute: attribute,
+			Negated:   negated,
+		}
+		return
+	}
+
+	// Get the operator:
+	var operator Operator
+	switch {
+	case p.at(tokenSymbol) && p.peek().text != "(" && p.peek().text != ")" &&
+		p.peek().text != ",":
+		operator = Operator(p.peek().text)
+		if operator == "!=" {
+			operator = OperatorNe
+		}
+		p.current++
+	case p.atKeyword("like"), p.atKeyword("ilike"), p.atKeyword("in"):
+		operator = Operator(strings.ToLower(p.peek().text))
+		p.current++
+	case p.atKeyword("not"):
+		p.current++
+		if !p.atKeyword("like") && !p.atKeyword("ilike") && !p.atKeyword("in") {
+			err = p.unexpected("'like', 'ilike' or 'in'")
+			return
+		}
+		operator = Operator("not " + strings.ToLower(p.peek().text))
+		p.current++
+	default:
+		err = p.unexpected("operator")
+		return
+	}
+
+	// Get the values:
+	var values []interface{}
+	switch operator {
+	case OperatorIn, OperatorNotIn:
+		values, err = p.parseList()
+	case OperatorLike, OperatorNotLike, OperatorILike, OperatorNotILike:
+		if !p.at(tokenString) {
+			err = p.unexpected("string")
+			return
+		}
+		var value interface{}
+		value, err = p.parseLiteral()
+		values = []interface{}{value}
+	default:
+		var value interface{}
+		value, err = p.parseLiteral()
+		values = []interface{}{value}
+	}
+	if err != nil {
+		return
+	}
+
+	result = &Comparison{
+		Attribute: attribute,
+		Operator:  operator,
+		Values:    values,
+	}
+	return
+}
+
+func (p *parser) parseList() (result []interface{}, err error) {
+	if !p.atSymbol("(") {
+		err = p.unexpected("'('")
+		return
+	}
+	p.current++
+	for {
+		var value interface{}
+		value, err = p.parseLiteral()
+		if err != nil {
+			return
+		}
+		result = append(result, value)
+		switch {
+		case p.atSymbol(","):
+			p.current++
+		case p.atSymbol(")"):
+			p.current++
+			return
+		default:
+			err = p.unexpected("',' or ')'")
+			return
+		}
+	}
+}
+
+func (p *parser) parseLiteral() (result interface{}, err error) {
+	current := p.peek()
+	switch {
+	case current.kind == tokenString:
+		text := current.text[1 : len(current.text)-1]
+		result = strings.Replace(text, "''", "'", -1)
+	case current.kind == tokenNumber:
+		result, err = strconv.ParseInt(current.text, 10, 64)
+		if err != nil {
+			result, err = strconv.ParseFloat(current.text, 64)
+		}
+		if err != nil {
+			err = fmt.Errorf(
+				"number '%s' at position %d isn't valid",
+				current.text, current.position,
+			)
+			return
+		}
+	case p.atKeyword("true"):
+		result = true
+	case p.atKeyword("false"):
+		result = false
+	default:
+		err = p.unexpected("literal value")
+		return
+	}
+	p.current++
+	return
+}
+
+// peek returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.current]
+}
+
+// at checks if the current token is of the given kind.
+func (p *parser) at(kind tokenKind) bool {
+	return p.peek().kind == kind
+}
+
+// atKeyword checks if the current token is the given keyword, ignoring case.
+func (p *parser) atKeyword(keyword string) bool {
+	current := p.peek()
+	return current.kind == tokenIdentifier && strings.EqualFold(current.text, keyword)
+}
+
+// atSymbol checks if the current token is the given symbol.
+func (p *parser) atSymbol(symbol string) bool {
+	current := p.peek()
+	return current.kind == tokenSymbol && current.text == symbol
+}
+
+// unexpected creates the error returned when the current token isn't what was expected.
+func (p *parser) unexpected(expected string) error {
+	current := p.peek()
+	if current.kind == tokenEnd {
+		return fmt.Errorf("expected %s but found end of expression", expected)
+	}
+	return fmt.Errorf(
+		"expected %s at position %d but found '%s'",
+		expected, current.position, current.text,
+	)
+}
+
+// isKeyword checks if the given identifier is one of the reserved words of the language.
+func isKeyword(text string) bool {
+	switch strings.ToLower(text) {
+	case "and", "or", "not", "like", "ilike", "in", "is", "null", "true", "false":
+		return true
+	}
+	return false
+}
+
+// tokenize splits the text of the expression into tokens. The last token is always the end
+// token.
+func tokenize(text string) (result []token, err error) {
+	runes := []rune(text)
+	i := 0
+	for i < len(runes) {
+		char := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(char):
+			i++
+			continue
+		case char == '\'':
+			i++
+			for {
+				if i >= len(runes) {
+					err = fmt.Errorf("string starting at position %d isn't terminated", start)
+					return
+				}
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				i++
+			}
+			result = append(result, token{
+				kind: tokenString,
+			})
+		case unicode.IsDigit(char) || (char == '-' || char == '.') && i+1 < len(runes) &&
+			unicode.IsDigit(runes[i+1]):
+			i++
+			for i < len(runes) {
+				current := runes[i]
+				if unicode.IsDigit(current) || current == '.' || current == 'e' ||
+					current == 'E' {
+					i++
+					continue
+				}
+				if (current == '+' || current == '-') &&
+					(runes[i-1] == 'e' || runes[i-1] == 'E') {
+					i++
+					continue
+				}
+				break
+			}
+			result = append(result, token{
+				kind: tokenNumber,
+			})
+		case unicode.IsLetter(char) || char == '_':
+			i++
+			for i < len(runes) {
+				current := runes[i]
+				if unicode.IsLetter(current) || unicode.IsDigit(current) || current == '_' ||
+					current == '.' {
+					i++
+					continue
+				}
+				break
+			}
+			result = append(result, token{
+				kind: tokenIdentifier,
+			})
+		case strings.ContainsRune("(),=", char):
+			i++
+			result = append(result, token{
+				kind: tokenSymbol,
+			})
+		case strings.ContainsRune("<>!", char):
+			i++
+			if i < len(runes) && (runes[i] == '=' || char == '<' && runes[i] == '>') {
+				i++
+			}
+			if char == '!' && i-start == 1 {
+				err = fmt.Errorf("unexpected character '!' at position %d", start)
+				return
+			}
+			result = append(result, token{
+				kind: tokenSymbol,
+			})
+		default:
+			err = fmt.Errorf("unexpected character '%c' at position %d", char, start)
+			return
+		}
+		last := &result[len(result)-1]
+		last.text = string(runes[start:i])
+		last.position = start
+	}
+	result = append(result, token{
+		kind:     tokenEnd,
+		position: len(runes),
+	})
+	return
+}
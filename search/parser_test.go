@@ -0,0 +1,160 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the parser of search expressions.
+
+package search_test
+
+import (
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/openshift-online/ocm-sdk-go/search"
+)
+
+var _ = Describe("Parser", func() {
+	It("Parses empty text", func() {
+		expression, err := search.Parse("  ")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(&search.Conjunction{}))
+	})
+
+	It("Parses constant expressions", func() {
+		expression, err := search.Parse("TRUE")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(&search.Conjunction{}))
+		expression, err = search.Parse("false")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(&search.Disjunction{}))
+	})
+
+	It("Parses comparison with string", func() {
+		expression, err := search.Parse("name = 'my''cluster'")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(&search.Comparison{
+			Attribute: "name",
+			Operator:  search.OperatorEq,
+			Values:    []interface{}{"my'cluster"},
+		}))
+	})
+
+	It("Parses numbers and booleans", func() {
+		expression, err := search.Parse("a >= 3 and b < -1.5 and c != TRUE")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(&search.Conjunction{
+			Operands: []search.Expression{
+				&search.Comparison{
+					Attribute: "a",
+					Operator:  search.OperatorGe,
+					Values:    []interface{}{int64(3)},
+				},
+				&search.Comparison{
+					Attribute: "b",
+					Operator:  search.OperatorLt,
+					Values:    []interface{}{-1.5},
+				},
+				&search.Comparison{
+					Attribute: "c",
+					Operator:  search.OperatorNe,
+					Values:    []interface{}{true},
+				},
+			},
+		}))
+	})
+
+	It("Parses keywords ignoring case", func() {
+		expression, err := search.Parse("name NOT ILIKE 'my%' AND region IS NOT NULL")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(search.And(
+			search.NotILike("name", "my%"),
+			search.IsNotNull("region"),
+		)))
+	})
+
+	It("Parses lists of values", func() {
+		expression, err := search.Parse("region.id not in ('us-east-1', 'us-west-2')")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(search.NotIn("region.id", "us-east-1", "us-west-2")))
+	})
+
+	It("Gives 'and' more precedence than 'or'", func() {
+		expression, err := search.Parse("a = 1 or b = 2 and c = 3")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(search.Or(
+			search.Eq("a", 1),
+			search.And(search.Eq("b", 2), search.Eq("c", 3)),
+		)))
+	})
+
+	It("Honours parentheses", func() {
+		expression, err := search.Parse("(a = 1 or b = 2) and not (c = 3)")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expression).To(Equal(search.And(
+			search.Or(search.Eq("a", 1), search.Eq("b", 2)),
+			search.Not(search.Eq("c", 3)),
+		)))
+	})
+
+	It("Fails if string isn't terminated", func() {
+		_, err := search.Parse("name = 'my")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("string starting at position 7 isn't terminated"))
+	})
+
+	It("Fails if value is missing", func() {
+		_, err := search.Parse("name = ")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("expected literal value but found end of expression"))
+	})
+
+	It("Fails if operator is unknown", func() {
+		_, err := search.Parse("name is 'my'")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("expected 'null' at position 8 but found ''my''"))
+	})
+
+	It("Fails if pattern isn't a string", func() {
+		_, err := search.Parse("name like 1")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("expected string at position 10 but found '1'"))
+	})
+
+	It("Fails if parenthesis isn't closed", func() {
+		_, err := search.Parse("(name = 'my'")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("expected ')' but found end of expression"))
+	})
+
+	It("Fails if there is text after the expression", func() {
+		_, err := search.Parse("name = 'my' junk")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal(
+			"expected 'and', 'or' or end of expression at position 12 but found 'junk'",
+		))
+	})
+
+	It("Fails if a keyword is used as attribute name", func() {
+		_, err := search.Parse("and = 1")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("expected attribute name at position 0 but found 'and'"))
+	})
+
+	It("Fails on unexpected characters", func() {
+		_, err := search.Parse("name = 'my'; drop table clusters")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("unexpected character ';' at position 11"))
+	})
+})
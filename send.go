@@ -28,8 +28,13 @@ import (
 	"path"
 	"strconv"
 	"time"
+
+	"github.com/openshift-online/ocm-sdk-go/internal"
 )
 
+// RoundTrip is the implementation of the http.RoundTripper interface. It checks the request, adds
+// the authentication and default headers and sends it. If the request fails with a transient error
+// and retries are enabled then it will be sent again, waiting between attempts.
 func (c *Connection) RoundTrip(request *http.Request) (response *http.Response, err error) {
 	// Check if the connection is closed:
 	err = c.checkClosed()
@@ -52,45 +57,131 @@ func (c *Connection) RoundTrip(request *http.Request) (response *http.Response,
 		metric = "/-"
 	}
 
-	// Measure the time that it takes to send the request and receive the response:
-	// it in the log:
-	before := time.Now()
-	response, err = c.send(ctx, request)
-	after := time.Now()
-	elapsed := after.Sub(before)
+	// Find the limiters that apply to the request, before the path is modified:
+	limiters := c.selectLimiters(request.URL.Path)
+
+	// Check the request and add the default headers:
+	err = c.prepareRequest(request)
+	if err != nil {
+		return
+	}
+
+	// Read the complete request body in memory, as we may need to send it multiple times, and
+	// we also need it to send it to the log when debug is enabled:
+	var body []byte
+	if request.Body != nil {
+		body, err = ioutil.ReadAll(request.Body)
+		if err != nil {
+			err = fmt.Errorf("can't read request body: %v", err)
+			return
+		}
+		err = request.Body.Close()
+		if err != nil {
+			err = fmt.Errorf("can't close request body: %v", err)
+			return
+		}
+	}
+
+	// Send the request, and retry it while the result is a transient error:
+	attempt := 0
+	for {
+		attempt++
+
+		// Wait till the rate and concurrency limits allow sending the request:
+		var release func()
+		release, err = c.acquireLimits(ctx, limiters, metric)
+		if err != nil {
+			return
+		}
+
+		// Get the access token. This is done for each attempt because the token may
+		// expire while we wait between attempts:
+		var token string
+		token, err = c.requestToken(ctx)
+		if err != nil {
+			release()
+			err = fmt.Errorf("can't get access token: %v", err)
+			return
+		}
 
-	// Update the metrics:
-	if c.callCountMetric != nil || c.callDurationMetric != nil {
-		code := 0
-		if response != nil {
-			code = response.StatusCode
+		// Measure the time that it takes to send the request and receive the response:
+		spanCtx, span := c.startCallSpan(contextWithMetricPath(ctx, metric), request, metric,
+			attempt)
+		before := time.Now()
+		response, err = c.send(spanCtx, request, token, body)
+		after := time.Now()
+		elapsed := after.Sub(before)
+		response, err = c.endCallSpan(spanCtx, span, response, err)
+		if err != nil {
+			release()
+		} else {
+			holdLimits(response, release)
 		}
-		labels := map[string]string{
-			metricsMethodLabel: request.Method,
-			metricsPathLabel:   metric,
-			metricsCodeLabel:   strconv.Itoa(code),
+
+		// Update the metrics:
+		c.updateCallMetrics(request.Method, metric, attempt, response, elapsed)
+
+		// Check if we should try again:
+		if !c.retryable(ctx, request, attempt, response, err) {
+			return
 		}
-		if c.callCountMetric != nil {
-			c.callCountMetric.With(labels).Inc()
+		delay := c.retryDelay(attempt, response)
+		if err != nil {
+			c.logger.Debug(
+				ctx,
+				"Attempt %d of request '%s %s' failed, will retry in %s: %v",
+				attempt, request.Method, request.URL, delay, err,
+			)
+		} else {
+			c.logger.Debug(
+				ctx,
+				"Attempt %d of request '%s %s' returned status code %d, will retry in %s",
+				attempt, request.Method, request.URL, response.StatusCode, delay,
+			)
 		}
-		if c.callDurationMetric != nil {
-			c.callDurationMetric.With(labels).Observe(elapsed.Seconds())
+		discardBody(response)
+		response = nil
+		err = retryWait(ctx, delay)
+		if err != nil {
+			err = fmt.Errorf("can't wait to retry request: %v", err)
+			return
 		}
 	}
+}
 
-	return
+// updateCallMetrics updates the call metrics with the results of one attempt to send a request.
+func (c *Connection) updateCallMetrics(method, metric string, attempt int,
+	response *http.Response, elapsed time.Duration) {
+	if c.callCountMetric == nil && c.callDurationMetric == nil {
+		return
+	}
+	code := 0
+	if response != nil {
+		code = response.StatusCode
+	}
+	labels := map[string]string{
+		metricsMethodLabel:  method,
+		metricsPathLabel:    metric,
+		metricsCodeLabel:    strconv.Itoa(code),
+		metricsAttemptLabel: strconv.Itoa(attempt),
+	}
+	if c.callCountMetric != nil {
+		c.callCountMetric.With(labels).Inc()
+	}
+	if c.callDurationMetric != nil {
+		c.callDurationMetric.With(labels).Observe(elapsed.Seconds())
+	}
 }
 
-func (c *Connection) send(ctx context.Context, request *http.Request) (response *http.Response,
-	err error) {
+// prepareRequest checks that the request URL and method are acceptable, adds the API URL to the
+// request URL and adds the default headers.
+func (c *Connection) prepareRequest(request *http.Request) error {
 	// Check that the request URL:
 	if request.URL.Path == "" {
-		err = fmt.Errorf("request path is mandatory")
-		return
+		return fmt.Errorf("request path is mandatory")
 	}
 	if request.URL.Scheme != "" || request.URL.Host != "" || !path.IsAbs(request.URL.Path) {
-		err = fmt.Errorf("request URL '%s' isn't absolute", request.URL)
-		return
+		return fmt.Errorf("request URL '%s' isn't absolute", request.URL)
 	}
 
 	// Add the API URL to the request URL:
@@ -100,25 +191,16 @@ func (c *Connection) send(ctx context.Context, request *http.Request) (response
 	switch request.Method {
 	case http.MethodGet, http.MethodDelete:
 		if request.Body != nil {
-			err = fmt.Errorf(
+			return fmt.Errorf(
 				"request body is not allowed for the '%s' method",
 				request.Method,
 			)
-			return
 		}
 	case http.MethodPost, http.MethodPatch:
 		// POST and PATCH don't need to have a body. It is up to the server to decide if
 		// this is acceptable.
 	default:
-		err = fmt.Errorf("method '%s' is not allowed", request.Method)
-		return
-	}
-
-	// Get the access token:
-	token, _, err := c.TokensContext(ctx)
-	if err != nil {
-		err = fmt.Errorf("can't get access token: %v", err)
-		return
+		return fmt.Errorf("method '%s' is not allowed", request.Method)
 	}
 
 	// Add the default headers:
@@ -128,39 +210,37 @@ func (c *Connection) send(ctx context.Context, request *http.Request) (response
 	if c.agent != "" {
 		request.Header.Set("User-Agent", c.agent)
 	}
-	if token != "" {
-		request.Header.Set("Authorization", "Bearer "+token)
-	}
 	switch request.Method {
 	case http.MethodPost, http.MethodPatch:
 		request.Header.Set("Content-Type", "application/json")
 	}
 	request.Header.Set("Accept", "application/json")
 
-	// If debug is enabled then we need to read the complete body in memory, in order to send it
-	// to the log, and we need to replace the original with a reader that reads it from memory:
+	return nil
+}
+
+// send sends one attempt of the given request, using the given access token and body.
+func (c *Connection) send(ctx context.Context, request *http.Request, token string,
+	body []byte) (response *http.Response, err error) {
+	// Create a copy of the request, so that each attempt has its own body reader:
+	attempt := request.WithContext(ctx)
+	attempt.Header = internal.CopyHeader(request.Header)
+	if token != "" {
+		attempt.Header.Set("Authorization", "Bearer "+token)
+	}
+	c.tracePropagator.Inject(ctx, attempt.Header)
+	if body != nil {
+		attempt.Body = ioutil.NopCloser(bytes.NewReader(body))
+		attempt.ContentLength = int64(len(body))
+	}
+
+	// If debug is enabled then send the details of the request to the log:
 	if c.logger.DebugEnabled() {
-		if request.Body != nil {
-			var body []byte
-			body, err = ioutil.ReadAll(request.Body)
-			if err != nil {
-				err = fmt.Errorf("can't read request body: %v", err)
-				return
-			}
-			err = request.Body.Close()
-			if err != nil {
-				err = fmt.Errorf("can't close request body: %v", err)
-				return
-			}
-			c.dumpRequest(ctx, request, body)
-			request.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-		} else {
-			c.dumpRequest(ctx, request, nil)
-		}
+		c.dumpRequest(ctx, attempt, body)
 	}
 
 	// Send the request and get the response:
-	response, err = c.client.Do(request)
+	response, err = c.client.Do(attempt)
 	if err != nil {
 		err = fmt.Errorf("can't send request: %v", err)
 		return
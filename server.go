@@ -48,43 +48,43 @@ type Server interface {
 
 // Dispatch navigates the servers tree till it finds one that matches the given set
 // of path segments, and then invokes it.
-func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []string) {
+func Dispatch(w http.ResponseWriter, r *http.Request, logger Logger, server Server, segments []string) {
 	if len(segments) == 0 {
 		// TODO: This should send the metadata.
-		errors.SendMethodNotAllowed(w, r)
+		errors.SendMethodNotAllowedWithLogger(w, r, logger)
 		return
 	} else {
 		switch segments[0] {
 		case "accounts_mgmt":
 			service := server.AccountsMgmt()
 			if service == nil {
-				errors.SendNotFound(w, r)
+				errors.SendNotFoundWithLogger(w, r, logger)
 				return
 			}
-			accountsmgmt.Dispatch(w, r, service, segments[1:])
+			accountsmgmt.Dispatch(w, r, logger, service, segments[1:])
 		case "authorizations":
 			service := server.Authorizations()
 			if service == nil {
-				errors.SendNotFound(w, r)
+				errors.SendNotFoundWithLogger(w, r, logger)
 				return
 			}
-			authorizations.Dispatch(w, r, service, segments[1:])
+			authorizations.Dispatch(w, r, logger, service, segments[1:])
 		case "clusters_mgmt":
 			service := server.ClustersMgmt()
 			if service == nil {
-				errors.SendNotFound(w, r)
+				errors.SendNotFoundWithLogger(w, r, logger)
 				return
 			}
-			clustersmgmt.Dispatch(w, r, service, segments[1:])
+			clustersmgmt.Dispatch(w, r, logger, service, segments[1:])
 		case "service_logs":
 			service := server.ServiceLogs()
 			if service == nil {
-				errors.SendNotFound(w, r)
+				errors.SendNotFoundWithLogger(w, r, logger)
 				return
 			}
-			servicelogs.Dispatch(w, r, service, segments[1:])
+			servicelogs.Dispatch(w, r, logger, service, segments[1:])
 		default:
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -94,17 +94,36 @@ func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []
 // to the methods of an object that implements the Server interface.
 type Adapter struct {
 	server Server
+	logger Logger
+	strict bool
 }
 
 // NewAdapter creates a new adapter that will translate HTTP requests into calls to
-// the given server.
-func NewAdapter(server Server) *Adapter {
+// the given server. Errors and panics are written to the given logger. If the
+// logger is nil a logger based on the `glog` package will be used.
+func NewAdapter(server Server, logger Logger) *Adapter {
+	if logger == nil {
+		logger, _ = NewGlogLoggerBuilder().Build()
+	}
 	return &Adapter{
 		server: server,
+		logger: logger,
 	}
 }
 
+// StrictValidation enables or disables the strict validation of the bodies of requests. When
+// it is enabled requests that contain attributes that aren't part of the model are rejected.
+// The default is to ignore those attributes. This must be called before the adapter starts
+// processing requests.
+func (a *Adapter) StrictValidation(flag bool) *Adapter {
+	a.strict = flag
+	return a
+}
+
 // ServeHTTP is the implementation of the http.Handler interface.
 func (a *Adapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	Dispatch(w, r, a.server, helpers.Segments(r.URL.Path))
+	if a.strict {
+		r = r.WithContext(helpers.ContextWithStrictValidation(r.Context()))
+	}
+	Dispatch(w, r, a.logger, a.server, helpers.Segments(r.URL.Path))
 }
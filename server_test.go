@@ -0,0 +1,415 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the generated servers.
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/openshift-online/ocm-sdk-go/clustersmgmt"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+)
+
+// clustersServer is a server that only implements the collection of clusters of the clusters
+// management service. The methods of the collection call the functions stored in the server, so
+// that each test can decide what to do.
+type clustersServer struct {
+	Server
+	add func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+		response *cmv1.ClustersAddServerResponse) error
+}
+
+func (s *clustersServer) ClustersMgmt() clustersmgmt.Server {
+	return &clustersMgmtServer{
+		clusters: s,
+	}
+}
+
+type clustersMgmtServer struct {
+	clusters *clustersServer
+}
+
+func (s *clustersMgmtServer) V1() cmv1.Server {
+	return &clustersMgmtV1Server{
+		clusters: s.clusters,
+	}
+}
+
+type clustersMgmtV1Server struct {
+	cmv1.Server
+	clusters *clustersServer
+}
+
+func (s *clustersMgmtV1Server) Clusters() cmv1.ClustersServer {
+	return &clustersCollectionServer{
+		clusters: s.clusters,
+	}
+}
+
+type clustersCollectionServer struct {
+	cmv1.ClustersServer
+	clusters *clustersServer
+}
+
+func (s *clustersCollectionServer) Add(ctx context.Context,
+	request *cmv1.ClustersAddServerRequest, response *cmv1.ClustersAddServerResponse) error {
+	return s.clusters.add(ctx, request, response)
+}
+
+var _ = Describe("Server", func() {
+	// Server used by the tests:
+	var server *clustersServer
+
+	// Logger used by the tests, and buffer that contains the messages written to it:
+	var logger Logger
+	var buffer *bytes.Buffer
+
+	// Flag that indicates if the adapter should validate request bodies in strict mode:
+	var strict bool
+
+	BeforeEach(func() {
+		var err error
+
+		// Use lenient validation unless the test says otherwise:
+		strict = false
+
+		// Create the server:
+		server = &clustersServer{}
+
+		// Create the logger:
+		buffer = &bytes.Buffer{}
+		stream := io.MultiWriter(buffer, GinkgoWriter)
+		logger, err = NewStdLoggerBuilder().
+			Streams(stream, stream).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// send sends a request to add a cluster, and returns the response:
+	send := func(body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(
+			http.MethodPost,
+			"/clusters_mgmt/v1/clusters",
+			strings.NewReader(body),
+		)
+		recorder := httptest.NewRecorder()
+		NewAdapter(server, logger).StrictValidation(strict).ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// parse parses the body of the response as an error:
+	parse := func(recorder *httptest.ResponseRecorder) *errors.Error {
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		result, err := errors.UnmarshalError(recorder.Body.Bytes())
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	It("Sends the object returned by the server", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			body, err := cmv1.NewCluster().
+				ID("123").
+				Name(request.Body().Name()).
+				Build()
+			if err != nil {
+				return err
+			}
+			response.Body(body)
+			return nil
+		}
+		recorder := send(`{"name": "mycluster", "region": {"id": "us-east-1"}}`)
+		cluster, err := cmv1.UnmarshalCluster(recorder.Body.Bytes())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.ID()).To(Equal("123"))
+		Expect(cluster.Name()).To(Equal("mycluster"))
+	})
+
+	It("Sends 400 if the request body can't be parsed", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			Fail("The server shouldn't be called")
+			return nil
+		}
+		recorder := send(`{"name": `)
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		result := parse(recorder)
+		Expect(result.ID()).To(Equal("400"))
+		Expect(result.Reason()).ToNot(BeEmpty())
+	})
+
+	It("Rejects requests that don't contain required attributes", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			Fail("The server shouldn't be called")
+			return nil
+		}
+		recorder := send(`{"name": "mycluster"}`)
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		result := parse(recorder)
+		Expect(result.Reason()).To(ContainSubstring("attribute 'region' is mandatory"))
+	})
+
+	It("Rejects requests that contain read only attributes", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			Fail("The server shouldn't be called")
+			return nil
+		}
+		recorder := send(`{
+			"id": "123",
+			"href": "/api/clusters_mgmt/v1/clusters/123",
+			"name": "mycluster",
+			"region": {"id": "us-east-1"},
+			"creation_timestamp": "2020-01-01T00:00:00Z"
+		}`)
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		result := parse(recorder)
+		Expect(result.Reason()).To(ContainSubstring("attribute 'id' is read only"))
+		Expect(result.Reason()).To(ContainSubstring("attribute 'href' is read only"))
+		Expect(result.Reason()).To(ContainSubstring(
+			"attribute 'creation_timestamp' is read only",
+		))
+	})
+
+	It("Rejects requests that contain invalid enumerated values", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			Fail("The server shouldn't be called")
+			return nil
+		}
+		recorder := send(`{
+			"name": "mycluster",
+			"region": {"id": "us-east-1"},
+			"state": "broken"
+		}`)
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		result := parse(recorder)
+		Expect(result.Reason()).To(ContainSubstring(
+			"value 'broken' of attribute 'state' isn't valid",
+		))
+	})
+
+	It("Ignores unknown attributes by default", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			body, err := cmv1.NewCluster().
+				ID("123").
+				Name(request.Body().Name()).
+				Build()
+			if err != nil {
+				return err
+			}
+			response.Body(body)
+			return nil
+		}
+		recorder := send(`{
+			"name": "mycluster",
+			"region": {"id": "us-east-1", "zone": "a"},
+			"junk": true
+		}`)
+		cluster, err := cmv1.UnmarshalCluster(recorder.Body.Bytes())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cluster.Name()).To(Equal("mycluster"))
+	})
+
+	It("Rejects requests that contain unknown attributes in strict mode", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			Fail("The server shouldn't be called")
+			return nil
+		}
+		strict = true
+		recorder := send(`{
+			"name": "mycluster",
+			"region": {"id": "us-east-1", "zone": "a"},
+			"junk": true
+		}`)
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		result := parse(recorder)
+		Expect(result.Reason()).To(ContainSubstring("attribute 'junk' is unknown"))
+		Expect(result.Reason()).To(ContainSubstring("attribute 'region.zone' is unknown"))
+	})
+
+	It("Reports all the violations", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			Fail("The server shouldn't be called")
+			return nil
+		}
+		strict = true
+		recorder := send(`{
+			"href": "/api/clusters_mgmt/v1/clusters/123",
+			"name": 123,
+			"nodes": {"compute": "many"},
+			"groups": {"items": [{"users": {"items": [{"name": "joe"}]}}]}
+		}`)
+		Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		result := parse(recorder)
+		Expect(result.Reason()).To(Equal(
+			"request is invalid: " +
+				"attribute 'href' is read only, " +
+				"attribute 'region' is mandatory, " +
+				"attribute 'groups.items[0].users.items[0].name' is unknown, " +
+				"attribute 'name' should be a string, " +
+				"attribute 'nodes.compute' should be an integer",
+		))
+	})
+
+	// checkTyped checks that the given error returned by the server is sent to the client with
+	// the given status code:
+	checkTyped := func(err *errors.Error, status int) {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			return err
+		}
+		recorder := send(`{"name": "mycluster", "region": {"id": "us-east-1"}}`)
+		Expect(recorder.Code).To(Equal(status))
+		result := parse(recorder)
+		Expect(result.ID()).To(Equal(fmt.Sprintf("%d", status)))
+		Expect(result.Reason()).To(Equal("Cluster 'mycluster' is wrong"))
+	}
+
+	It("Sends bad request errors returned by the server", func() {
+		checkTyped(
+			errors.NewBadRequest("Cluster '%s' is wrong", "mycluster"),
+			http.StatusBadRequest,
+		)
+	})
+
+	It("Sends not found errors returned by the server", func() {
+		checkTyped(
+			errors.NewNotFound("Cluster '%s' is wrong", "mycluster"),
+			http.StatusNotFound,
+		)
+	})
+
+	It("Sends conflict errors returned by the server", func() {
+		checkTyped(
+			errors.NewConflict("Cluster '%s' is wrong", "mycluster"),
+			http.StatusConflict,
+		)
+	})
+
+	It("Sends unprocessable entity errors returned by the server", func() {
+		checkTyped(
+			errors.NewUnprocessableEntity("Cluster '%s' is wrong", "mycluster"),
+			http.StatusUnprocessableEntity,
+		)
+	})
+
+	It("Uses the status of errors created with the builder", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			err, _ := errors.NewError().
+				Status(http.StatusConflict).
+				ID("CLUSTER-EXISTS").
+				Code("CLUSTERS-MGMT-409").
+				Reason("Cluster already exists").
+				Build()
+			return err
+		}
+		recorder := send(`{"name": "mycluster", "region": {"id": "us-east-1"}}`)
+		Expect(recorder.Code).To(Equal(http.StatusConflict))
+		result := parse(recorder)
+		Expect(result.ID()).To(Equal("CLUSTER-EXISTS"))
+		Expect(result.Code()).To(Equal("CLUSTERS-MGMT-409"))
+	})
+
+	It("Sends 500 for other errors", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			return fmt.Errorf("database password is 'secret'")
+		}
+		recorder := send(`{"name": "mycluster", "region": {"id": "us-east-1"}}`)
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		result := parse(recorder)
+		Expect(result.ID()).To(Equal("500"))
+		Expect(result.Reason()).ToNot(ContainSubstring("secret"))
+		Expect(buffer.String()).To(ContainSubstring("secret"))
+	})
+
+	It("Sends 500 with an operation identifier if the server panics", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			panic("database is gone")
+		}
+		recorder := send(`{"name": "mycluster", "region": {"id": "us-east-1"}}`)
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		result := parse(recorder)
+		Expect(result.ID()).To(Equal("1000"))
+		Expect(result.Reason()).ToNot(ContainSubstring("database"))
+		Expect(result.OperationID()).ToNot(BeEmpty())
+		Expect(buffer.String()).To(ContainSubstring(result.OperationID()))
+		Expect(buffer.String()).To(ContainSubstring("database is gone"))
+	})
+
+	It("Doesn't recover the panic used to abort the handler", func() {
+		server.add = func(ctx context.Context, request *cmv1.ClustersAddServerRequest,
+			response *cmv1.ClustersAddServerResponse) error {
+			panic(http.ErrAbortHandler)
+		}
+		var cause interface{}
+		func() {
+			defer func() {
+				cause = recover()
+			}()
+			send(`{"name": "mycluster", "region": {"id": "us-east-1"}}`)
+		}()
+		Expect(cause).To(BeIdenticalTo(http.ErrAbortHandler))
+	})
+
+	It("Doesn't send the panic error if the response has already been started", func() {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		recorder := httptest.NewRecorder()
+		func() {
+			w, r, end := helpers.StartServerSpan(recorder, request, "test")
+			defer end()
+			defer errors.RecoverPanic(w, r, logger)
+			_, err := w.Write([]byte("partial"))
+			Expect(err).ToNot(HaveOccurred())
+			panic("connection is gone")
+		}()
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(Equal("partial"))
+		Expect(buffer.String()).To(ContainSubstring("connection is gone"))
+	})
+
+	It("Sends the panic error if the logger is nil", func() {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		recorder := httptest.NewRecorder()
+		func() {
+			defer errors.RecoverPanic(recorder, request, nil)
+			panic("database is gone")
+		}()
+		Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+		result := parse(recorder)
+		Expect(result.ID()).To(Equal("1000"))
+	})
+})
@@ -21,14 +21,22 @@ package v1 // github.com/openshift-online/ocm-sdk-go/servicelogs/v1
 
 import (
 	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/helpers"
 )
 
 func readClusterLogsAddRequest(request *ClusterLogsAddServerRequest, r *http.Request) error {
-	var err error
-	request.body, err = UnmarshalLogEntry(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	err = helpers.Validate(r.Context(), body, logEntryValidation, helpers.ValidateAdd)
+	if err != nil {
+		return err
+	}
+	request.body, err = UnmarshalLogEntry(body)
 	return err
 }
 func writeClusterLogsAddRequest(request *ClusterLogsAddRequest, writer io.Writer) error {
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // ClusterLogsServer represents the interface the manages the 'cluster_logs' resource.
@@ -292,17 +292,17 @@ func (r *ClusterLogsListServerResponse) Status(value int) *ClusterLogsListServer
 // dispatchClusterLogs navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchClusterLogs(w http.ResponseWriter, r *http.Request, server ClusterLogsServer, segments []string) {
+func dispatchClusterLogs(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterLogsServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "POST":
-			adaptClusterLogsAddRequest(w, r, server)
+			adaptClusterLogsAddRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptClusterLogsListRequest(w, r, server)
+			adaptClusterLogsListRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -310,41 +310,34 @@ func dispatchClusterLogs(w http.ResponseWriter, r *http.Request, server ClusterL
 	default:
 		target := server.LogEntry(segments[0])
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchLogEntry(w, r, target, segments[1:])
+		dispatchLogEntry(w, r, logger, target, segments[1:])
 	}
 }
 
 // adaptClusterLogsAddRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptClusterLogsAddRequest(w http.ResponseWriter, r *http.Request, server ClusterLogsServer) {
+func adaptClusterLogsAddRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterLogsServer) {
 	request := &ClusterLogsAddServerRequest{}
 	err := readClusterLogsAddRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ClusterLogsAddServerResponse{}
 	response.status = 201
 	err = server.Add(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeClusterLogsAddResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -355,31 +348,24 @@ func adaptClusterLogsAddRequest(w http.ResponseWriter, r *http.Request, server C
 // adaptClusterLogsListRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptClusterLogsListRequest(w http.ResponseWriter, r *http.Request, server ClusterLogsServer) {
+func adaptClusterLogsListRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server ClusterLogsServer) {
 	request := &ClusterLogsListServerRequest{}
 	err := readClusterLogsListRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &ClusterLogsListServerResponse{}
 	response.status = 200
 	err = server.List(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeClusterLogsListResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -23,8 +23,8 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang/glog"
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // LogEntryServer represents the interface the manages the 'log_entry' resource.
@@ -85,23 +85,23 @@ func (r *LogEntryGetServerResponse) Status(value int) *LogEntryGetServerResponse
 // dispatchLogEntry navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func dispatchLogEntry(w http.ResponseWriter, r *http.Request, server LogEntryServer, segments []string) {
+func dispatchLogEntry(w http.ResponseWriter, r *http.Request, logger logging.Logger, server LogEntryServer, segments []string) {
 	if len(segments) == 0 {
 		switch r.Method {
 		case "DELETE":
-			adaptLogEntryDeleteRequest(w, r, server)
+			adaptLogEntryDeleteRequest(w, r, logger, server)
 			return
 		case "GET":
-			adaptLogEntryGetRequest(w, r, server)
+			adaptLogEntryGetRequest(w, r, logger, server)
 			return
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
 	switch segments[0] {
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -109,31 +109,24 @@ func dispatchLogEntry(w http.ResponseWriter, r *http.Request, server LogEntrySer
 // adaptLogEntryDeleteRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptLogEntryDeleteRequest(w http.ResponseWriter, r *http.Request, server LogEntryServer) {
+func adaptLogEntryDeleteRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server LogEntryServer) {
 	request := &LogEntryDeleteServerRequest{}
 	err := readLogEntryDeleteRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &LogEntryDeleteServerResponse{}
 	response.status = 204
 	err = server.Delete(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeLogEntryDeleteResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
@@ -144,31 +137,24 @@ func adaptLogEntryDeleteRequest(w http.ResponseWriter, r *http.Request, server L
 // adaptLogEntryGetRequest translates the given HTTP request into a call to
 // the corresponding method of the given server. Then it translates the
 // results returned by that method into an HTTP response.
-func adaptLogEntryGetRequest(w http.ResponseWriter, r *http.Request, server LogEntryServer) {
+func adaptLogEntryGetRequest(w http.ResponseWriter, r *http.Request, logger logging.Logger, server LogEntryServer) {
 	request := &LogEntryGetServerRequest{}
 	err := readLogEntryGetRequest(request, r)
 	if err != nil {
-		glog.Errorf(
-			"Can't read request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendBadRequest(w, r, logger, err.Error())
 		return
 	}
 	response := &LogEntryGetServerResponse{}
 	response.status = 200
 	err = server.Get(r.Context(), request, response)
 	if err != nil {
-		glog.Errorf(
-			"Can't process request for method '%s' and path '%s': %v",
-			r.Method, r.URL.Path, err,
-		)
-		errors.SendInternalServerError(w, r)
+		errors.SendServerError(w, r, logger, err)
 		return
 	}
 	err = writeLogEntryGetResponse(response, w)
 	if err != nil {
-		glog.Errorf(
+		errors.LogError(
+			logger, r,
 			"Can't write response for method '%s' and path '%s': %v",
 			r.Method, r.URL.Path, err,
 		)
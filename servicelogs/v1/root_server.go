@@ -23,6 +23,8 @@ import (
 	"net/http"
 
 	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 // Server represents the interface the manages the 'root' resource.
@@ -37,11 +39,14 @@ type Server interface {
 // Dispatch navigates the servers tree rooted at the given server
 // till it finds one that matches the given set of path segments, and then invokes
 // the corresponding server.
-func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []string) {
+func Dispatch(w http.ResponseWriter, r *http.Request, logger logging.Logger, server Server, segments []string) {
+	w, r, end := helpers.StartServerSpan(w, r, "/api/service_logs/v1")
+	defer end()
+	defer errors.RecoverPanic(w, r, logger)
 	if len(segments) == 0 {
 		switch r.Method {
 		default:
-			errors.SendMethodNotAllowed(w, r)
+			errors.SendMethodNotAllowedWithLogger(w, r, logger)
 			return
 		}
 	}
@@ -49,12 +54,12 @@ func Dispatch(w http.ResponseWriter, r *http.Request, server Server, segments []
 	case "cluster_logs":
 		target := server.ClusterLogs()
 		if target == nil {
-			errors.SendNotFound(w, r)
+			errors.SendNotFoundWithLogger(w, r, logger)
 			return
 		}
-		dispatchClusterLogs(w, r, target, segments[1:])
+		dispatchClusterLogs(w, r, logger, target, segments[1:])
 	default:
-		errors.SendNotFound(w, r)
+		errors.SendNotFoundWithLogger(w, r, logger)
 		return
 	}
 }
@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2019 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// IMPORTANT: This file has been generated automatically, refrain from modifying it manually as all
+// your changes will be lost when the file is generated again.
+
+package v1 // github.com/openshift-online/ocm-sdk-go/servicelogs/v1
+
+import (
+	"github.com/openshift-online/ocm-sdk-go/helpers"
+)
+
+// severityValidation describes the 'severity' enumerated type.
+var severityValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationEnum,
+	Values: []string{
+		"debug",
+		"error",
+		"fatal",
+		"info",
+		"warning",
+	},
+}
+
+// logEntryValidation describes the 'log_entry' type.
+var logEntryValidation = &helpers.ValidationType{
+	Kind: helpers.ValidationObject,
+}
+
+// init sets the attributes of the descriptions of the object types. This isn't done in the
+// declarations because the types may refer to each other.
+func init() {
+	logEntryValidation.Attributes = map[string]*helpers.ValidationAttribute{
+		"kind": {
+			Type: helpers.ValidationStringType,
+		},
+		"id": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"href": {
+			Type:     helpers.ValidationStringType,
+			ReadOnly: true,
+		},
+		"cluster_uuid": {
+			Type: helpers.ValidationStringType,
+		},
+		"description": {
+			Type: helpers.ValidationStringType,
+		},
+		"internal_only": {
+			Type: helpers.ValidationBooleanType,
+		},
+		"service_name": {
+			Type: helpers.ValidationStringType,
+		},
+		"severity": {
+			Type: severityValidation,
+		},
+		"summary": {
+			Type: helpers.ValidationStringType,
+		},
+		"timestamp": {
+			Type: helpers.ValidationDateType,
+		},
+	}
+}
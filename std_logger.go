@@ -106,6 +106,7 @@ func (b *StdLoggerBuilder) Build() (logger *StdLogger, err error) {
 	if logger.outStream == nil {
 		logger.outStream = os.Stdout
 	}
+	logger.errStream = b.errStream
 	if logger.errStream == nil {
 		logger.errStream = os.Stderr
 	}
@@ -0,0 +1,48 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the standard logger.
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+)
+
+var _ = Describe("Standard logger", func() {
+	It("Writes error messages to the error stream", func() {
+		// Create the logger:
+		outBuffer := &bytes.Buffer{}
+		errBuffer := &bytes.Buffer{}
+		logger, err := NewStdLoggerBuilder().
+			Streams(outBuffer, errBuffer).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Write the messages:
+		ctx := context.Background()
+		logger.Info(ctx, "My info")
+		logger.Error(ctx, "My error")
+
+		// Verify that each message went to the right stream:
+		Expect(outBuffer.String()).To(Equal("My info\n"))
+		Expect(errBuffer.String()).To(Equal("My error\n"))
+	})
+})
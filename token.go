@@ -49,50 +49,324 @@ func (c *Connection) Tokens() (access, refresh string, err error) {
 // TokensContext returns the access and refresh tokens that is currently in use by the connection.
 // If it is necessary to request a new token because it wasn't requested yet, or because it is
 // expired, this method will do it and will return an error if it fails.
+//
+// If the connection was created with a token source then the access token is obtained from it, and
+// the returned refresh token will always be empty.
 func (c *Connection) TokensContext(ctx context.Context) (access, refresh string, err error) {
-	// We need to make sure that this method isn't execute concurrently, as we will be updating
-	// multiple attributes of the connection:
+	// Make sure that we have a context, as we may need to wait for it:
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// If the access token is available and it isn't expired or about to expire then we can
+	// return the current tokens directly, without waiting for token requests that may be in
+	// progress:
+	c.tokenMutex.Lock()
+	if c.accessToken != nil {
+		var expires bool
+		var left time.Duration
+		expires, left, err = tokenExpiry(c.accessToken, time.Now())
+		if err != nil {
+			c.tokenMutex.Unlock()
+			return
+		}
+		if !expires || left >= 1*time.Minute {
+			access, refresh = c.currentTokens()
+			c.tokenMutex.Unlock()
+			return
+		}
+	}
+	c.tokenMutex.Unlock()
+
+	// Request new tokens, or wait for the request that is already in progress:
+	access, refresh, err = c.renewTokens(ctx, false)
+	return
+}
+
+// tokenCall contains the details of a token request that is in progress, so that multiple
+// goroutines that need new tokens at the same time can share the results of a single request.
+type tokenCall struct {
+	done      chan struct{}
+	access    string
+	refresh   string
+	err       error
+	cancelled bool
+}
+
+// renewTokens requests new tokens. If there is already a request in progress it waits for it to
+// finish and returns its results instead of sending a new one. The force flag indicates if the
+// tokens should be renewed even if the current access token is still valid.
+func (c *Connection) renewTokens(ctx context.Context, force bool) (access, refresh string,
+	err error) {
+	for {
+		// Check if there is already a request in progress, and wait for it if there is:
+		c.tokenMutex.Lock()
+		call := c.tokenCall
+		if call == nil {
+			break
+		}
+		c.tokenMutex.Unlock()
+		c.logger.Debug(ctx, "Waiting for token request that is already in progress")
+		c.updateTokenEventMetric(tokenEventShared)
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		}
+
+		// If the request failed only because the context of the goroutine that sent it was
+		// cancelled then its result doesn't apply to us, so we try again, either waiting
+		// for other request or sending our own:
+		if call.cancelled {
+			continue
+		}
+		access, refresh, err = call.access, call.refresh, call.err
+		return
+	}
+	call := &tokenCall{
+		done: make(chan struct{}),
+	}
+	c.tokenCall = call
+	c.tokenMutex.Unlock()
+
+	// Send the request without holding the lock, so that other goroutines can still use the
+	// current tokens if they are valid:
+	access, refresh, err = c.requestTokens(ctx, force)
+
+	// Publish the results to the goroutines that may be waiting:
+	call.access, call.refresh, call.err = access, refresh, err
+	call.cancelled = err != nil && ctx.Err() != nil
+	c.tokenMutex.Lock()
+	c.tokenCall = nil
+	c.tokenMutex.Unlock()
+	close(call.done)
+
+	return
+}
+
+// requestTokens obtains a new access token from the token source and, if it is acceptable, makes
+// it the current access token of the connection. This is used for all token sources, including the
+// default one that uses the OpenID grants, so that all of them benefit from the cache, the
+// verification and the background renewal. The force flag indicates if the tokens should be
+// renewed even if the current access token is still valid.
+func (c *Connection) requestTokens(ctx context.Context, force bool) (access, refresh string,
+	err error) {
+	// Check if other connections have saved better tokens to the cache:
+	c.loadCachedTokens(ctx)
+
+	// If the access token is available and it isn't expired or about to expire then we can
+	// return the current tokens directly, unless we have been explicitly asked to renew them:
+	c.tokenMutex.Lock()
+	current := c.accessToken
+	c.tokenMutex.Unlock()
+	if !force && current != nil {
+		var expires bool
+		var left time.Duration
+		expires, left, err = tokenExpiry(current, time.Now())
+		if err != nil {
+			return
+		}
+		if !expires || left >= 1*time.Minute {
+			access, refresh = c.lockedTokens()
+			return
+		}
+	}
+
+	// Get the new token from the source. When renewing in the background we tell the source,
+	// so that it doesn't try to interact with the user.
+	if force {
+		ctx = context.WithValue(ctx, renewalKeyValue, true)
+	}
+	text, expiry, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return
+	}
+
+	// The default token source may have obtained a new refresh token together with the access
+	// token. It is saved only after the access token has been verified.
+	var newRefresh *jwt.Token
+	source, ok := c.tokenSource.(*openIDTokenSource)
+	if ok {
+		newRefresh = source.takeRefreshToken(text)
+	}
+	if current != nil && text == current.Raw {
+		access, refresh = c.lockedTokens()
+		return
+	}
+	token, err := c.parseSourceToken(text, expiry)
+	if err != nil {
+		return
+	}
+
+	if c.logger.DebugEnabled() {
+		var expires bool
+		var left time.Duration
+		expires, left, err = tokenExpiry(token, time.Now())
+		if err != nil {
+			return
+		}
+		c.debugExpiry(ctx, "Bearer", token, expires, left)
+	}
+
+	// Verify the access token, if enabled:
+	if c.tokenVerifier != nil {
+		err = c.tokenVerifier.verify(ctx, token, true)
+		if err != nil {
+			err = fmt.Errorf("can't verify access token: %v", err)
+			return
+		}
+	}
+
+	// Save the new token, and tell the background renewer that it needs to recalculate when
+	// it should be renewed:
+	c.tokenMutex.Lock()
+	c.accessToken = token
+	if newRefresh != nil {
+		c.refreshToken = newRefresh
+	}
+	if c.tokenCache == nil && c.tokenCachePath != "" {
+		c.tokenCache = newTokenCache(
+			c.tokenCachePath,
+			c.tokenURL.String(), c.clientID, c.user, c.scopes, tokenSubject(token),
+		)
+	}
+	refreshToken := c.refreshToken
+	access, refresh = c.currentTokens()
+	c.tokenMutex.Unlock()
+	c.wakeRenewer()
+
+	// Save the new tokens to the cache, so that other connections can use them:
+	c.saveCachedTokens(ctx, token, refreshToken)
+
+	return
+}
+
+// parseSourceToken parses the access token returned by a token source. Tokens that aren't JSON web
+// tokens, or that don't have an expiration time, are accepted, and the expiration time returned by
+// the source is used for them.
+func (c *Connection) parseSourceToken(text string, expiry time.Time) (token *jwt.Token,
+	err error) {
+	if text == "" {
+		err = fmt.Errorf("token source returned an empty access token")
+		return
+	}
+	token, _, err = c.tokenParser.ParseUnverified(text, jwt.MapClaims{})
+	if err != nil {
+		token = &jwt.Token{
+			Raw:    text,
+			Claims: jwt.MapClaims{},
+		}
+		err = nil
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		err = fmt.Errorf("expected map claims but got %T", token.Claims)
+		return
+	}
+	if _, ok := claims["exp"]; !ok {
+		var exp float64
+		if !expiry.IsZero() {
+			exp = float64(expiry.Unix())
+		}
+		claims["exp"] = exp
+	}
+	return
+}
+
+// lockedTokens returns the current tokens, acquiring the lock that protects them.
+func (c *Connection) lockedTokens() (access, refresh string) {
 	c.tokenMutex.Lock()
 	defer c.tokenMutex.Unlock()
+	return c.currentTokens()
+}
+
+// openIDTokenSource is the token source used when the connection isn't given one explicitly. It
+// obtains access tokens using the OpenID grants: refresh token, resource owner password, client
+// credentials, authorization code and device authorization. The connection calls it only when it
+// needs a new access token, and it keeps the refresh token in the connection.
+type openIDTokenSource struct {
+	connection *Connection
+
+	// The refresh token returned by the server together with the last access token, waiting
+	// for the connection to verify that access token. Protected by the token mutex of the
+	// connection.
+	pendingAccess  string
+	pendingRefresh *jwt.Token
+}
+
+// setRefreshToken remembers the refresh token that was returned by the server together with the
+// given access token.
+func (s *openIDTokenSource) setRefreshToken(access, refresh *jwt.Token) {
+	c := s.connection
+	c.tokenMutex.Lock()
+	s.pendingAccess = access.Raw
+	s.pendingRefresh = refresh
+	c.tokenMutex.Unlock()
+}
+
+// takeRefreshToken returns the refresh token that was returned by the server together with the
+// given access token, if any, and forgets it.
+func (s *openIDTokenSource) takeRefreshToken(access string) (refresh *jwt.Token) {
+	c := s.connection
+	c.tokenMutex.Lock()
+	if s.pendingRefresh != nil && s.pendingAccess == access {
+		refresh = s.pendingRefresh
+	}
+	s.pendingAccess = ""
+	s.pendingRefresh = nil
+	c.tokenMutex.Unlock()
+	return
+}
+
+// Token is the implementation of the TokenSource interface.
+func (s *openIDTokenSource) Token(ctx context.Context) (token string, expiry time.Time,
+	err error) {
+	c := s.connection
+
+	// Check if we are renewing the tokens in the background:
+	renewal, _ := ctx.Value(renewalKeyValue).(bool)
+
+	// Take a snapshot of the current tokens, as they may be replaced while we work:
+	c.tokenMutex.Lock()
+	accessToken := c.accessToken
+	refreshToken := c.refreshToken
+	c.tokenMutex.Unlock()
 
 	// Check the expiration times of the tokens:
 	now := time.Now()
 	var accessExpires bool
 	var accessLeft time.Duration
-	if c.accessToken != nil {
-		accessExpires, accessLeft, err = tokenExpiry(c.accessToken, now)
+	if accessToken != nil {
+		accessExpires, accessLeft, err = tokenExpiry(accessToken, now)
 		if err != nil {
 			return
 		}
 	}
 	var refreshExpires bool
 	var refreshLeft time.Duration
-	if c.refreshToken != nil {
-		refreshExpires, refreshLeft, err = tokenExpiry(c.refreshToken, now)
+	if refreshToken != nil {
+		refreshExpires, refreshLeft, err = tokenExpiry(refreshToken, now)
 		if err != nil {
 			return
 		}
 	}
 	if c.logger.DebugEnabled() {
-		c.debugExpiry(ctx, "Bearer", c.accessToken, accessExpires, accessLeft)
-		c.debugExpiry(ctx, "Refresh", c.refreshToken, refreshExpires, refreshLeft)
+		c.debugExpiry(ctx, "Bearer", accessToken, accessExpires, accessLeft)
+		c.debugExpiry(ctx, "Refresh", refreshToken, refreshExpires, refreshLeft)
 	}
 
-	// If the access token is available and it isn't expired or about to expire then we can
-	// return the current tokens directly:
-	if c.accessToken != nil && (!accessExpires || accessLeft >= 1*time.Minute) {
-		access, refresh = c.currentTokens()
-		return
-	}
-
-	// At this point we know that the access token is unavailable, expired or about to expire.
-	// So we need to check if we can use the refresh token to request a new one.
-	if c.refreshToken != nil && (!refreshExpires || refreshLeft >= 1*time.Minute) {
-		err = c.sendRefreshTokenForm(ctx)
+	// The connection calls this when the access token is unavailable, expired or about to
+	// expire, or when it needs to renew it anyhow. So we need to check if we can use the
+	// refresh token to request a new one.
+	var result, refresh *jwt.Token
+	if refreshToken != nil && (!refreshExpires || refreshLeft >= 1*time.Minute) {
+		result, refresh, err = c.sendRefreshTokenForm(ctx, refreshToken)
 		if err != nil {
 			return
 		}
-		access, refresh = c.currentTokens()
+		s.setRefreshToken(result, refresh)
+		token, expiry = tokenText(result)
 		return
 	}
 
@@ -102,29 +376,61 @@ func (c *Connection) TokensContext(ctx context.Context) (access, refresh string,
 	havePassword := c.user != "" && c.password != ""
 	haveSecret := c.clientID != "" && c.clientSecret != ""
 	if havePassword || haveSecret {
-		err = c.sendRequestTokenForm(ctx)
+		result, refresh, err = c.sendRequestTokenForm(ctx)
+		if err != nil {
+			return
+		}
+		s.setRefreshToken(result, refresh)
+		token, expiry = tokenText(result)
+		return
+	}
+
+	// If the connection is configured to use the authorization code or device authorization
+	// grants then use them. These require interaction with the user, so we never do it when
+	// renewing tokens in the background.
+	if !renewal && c.codeHandler != nil {
+		result, refresh, err = c.sendAuthorizationCodeForm(ctx)
+		if err != nil {
+			return
+		}
+		s.setRefreshToken(result, refresh)
+		token, expiry = tokenText(result)
+		return
+	}
+	if !renewal && c.deviceHandler != nil {
+		result, refresh, err = c.sendDeviceCodeForm(ctx)
 		if err != nil {
 			return
 		}
-		access, refresh = c.currentTokens()
+		s.setRefreshToken(result, refresh)
+		token, expiry = tokenText(result)
+		return
+	}
+
+	// If we have been asked to renew the tokens, but there is no mechanism to do it, then we
+	// just return the current ones, without the fallbacks below, as they are only intended for
+	// requests that actually need a token:
+	if renewal && accessToken != nil && accessLeft > 0 {
+		token, expiry = tokenText(accessToken)
 		return
 	}
 
 	// Here we know that the access and refresh tokens are unavailable, expired or about to
 	// expire. We also know that we don't have credentials to request new ones. But we could
 	// still use the refresh token if it isn't completely exired.
-	if c.refreshToken != nil && refreshLeft > 0 {
+	if refreshToken != nil && refreshLeft > 0 {
 		c.logger.Warn(
 			ctx,
 			"Refresh token expires in only %s, but there is no other mechanism to "+
 				"obtain a new token, so will try to use it anyhow",
 			refreshLeft,
 		)
-		err = c.sendRefreshTokenForm(ctx)
+		result, refresh, err = c.sendRefreshTokenForm(ctx, refreshToken)
 		if err != nil {
 			return
 		}
-		access, refresh = c.currentTokens()
+		s.setRefreshToken(result, refresh)
+		token, expiry = tokenText(result)
 		return
 	}
 
@@ -132,14 +438,14 @@ func (c *Connection) TokensContext(ctx context.Context) (access, refresh string,
 	// that the refresh token is unavailable or completely expired. And we know that we don't
 	// have credentials to request new tokens. But we can still use the access token if it isn't
 	// expired.
-	if c.accessToken != nil && accessLeft > 0 {
+	if accessToken != nil && accessLeft > 0 {
 		c.logger.Warn(
 			ctx,
 			"Access token expires in only %s, but there is no other mechanism to "+
 				"obtain a new token, so will try to use it anyhow",
 			accessLeft,
 		)
-		access, refresh = c.currentTokens()
+		token, expiry = tokenText(accessToken)
 		return
 	}
 
@@ -152,9 +458,21 @@ func (c *Connection) TokensContext(ctx context.Context) (access, refresh string,
 	return
 }
 
+// tokenText returns the raw text of the given token and the time when it expires. If the token
+// doesn't expire the returned time is the zero value.
+func tokenText(token *jwt.Token) (text string, expiry time.Time) {
+	text = token.Raw
+	now := time.Now()
+	expires, left, err := tokenExpiry(token, now)
+	if err == nil && expires {
+		expiry = now.Add(left)
+	}
+	return
+}
+
 // currentTokens returns the current tokens without trying to send any request to refresh them, and
 // checking that they are actually available. If they aren't available then it will return empty
-// strings.
+// strings. The caller must hold the token mutex.
 func (c *Connection) currentTokens() (access, refresh string) {
 	if c.accessToken != nil {
 		access = c.accessToken.Raw
@@ -165,7 +483,8 @@ func (c *Connection) currentTokens() (access, refresh string) {
 	return
 }
 
-func (c *Connection) sendRequestTokenForm(ctx context.Context) error {
+func (c *Connection) sendRequestTokenForm(ctx context.Context) (access, refresh *jwt.Token,
+	err error) {
 	form := url.Values{}
 	havePassword := c.user != "" && c.password != ""
 	haveSecret := c.clientID != "" && c.clientSecret != ""
@@ -181,31 +500,46 @@ func (c *Connection) sendRequestTokenForm(ctx context.Context) error {
 		form.Set("client_id", c.clientID)
 		form.Set("client_secret", c.clientSecret)
 	} else {
-		return fmt.Errorf(
+		err = fmt.Errorf(
 			"either password or client secret must be provided",
 		)
+		return
 	}
 	form.Set("scope", strings.Join(c.scopes, " "))
 	return c.sendTokenForm(ctx, form)
 }
 
-func (c *Connection) sendRefreshTokenForm(ctx context.Context) error {
+func (c *Connection) sendRefreshTokenForm(ctx context.Context,
+	refreshToken *jwt.Token) (access, refresh *jwt.Token, err error) {
 	c.logger.Debug(ctx, "Requesting new token using the refresh token grant")
 	form := url.Values{}
 	form.Set("grant_type", "refresh_token")
 	form.Set("client_id", c.clientID)
 	form.Set("client_secret", c.clientSecret)
-	form.Set("refresh_token", c.refreshToken.Raw)
+	form.Set("refresh_token", refreshToken.Raw)
 	return c.sendTokenForm(ctx, form)
 }
 
-func (c *Connection) sendTokenForm(ctx context.Context, form url.Values) error {
+// sendTokenForm sends the given form to the token endpoint and returns the access and refresh
+// tokens returned by the server. They aren't saved, so that the caller can verify the access token
+// before using any of them.
+func (c *Connection) sendTokenForm(ctx context.Context, form url.Values) (access,
+	refresh *jwt.Token, err error) {
+	// Start the tracing span:
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := c.startTokenSpan(ctx, form.Get("grant_type"))
+
 	// Measure the time that it takes to send the request and receive the response:
 	before := time.Now()
-	code, err := c.sendTokenFormTimed(ctx, form)
+	code, access, refresh, err := c.sendTokenFormTimed(ctx, form)
 	after := time.Now()
 	elapsed := after.Sub(before)
 
+	// End the tracing span:
+	c.endTokenSpan(ctx, span, code, err)
+
 	// Update the metrics:
 	if c.tokenCountMetric != nil || c.tokenDurationMetric != nil {
 		labels := map[string]string{
@@ -220,10 +554,11 @@ func (c *Connection) sendTokenForm(ctx context.Context, form url.Values) error {
 	}
 
 	// Return the original error:
-	return err
+	return
 }
 
-func (c *Connection) sendTokenFormTimed(ctx context.Context, form url.Values) (code int, err error) {
+func (c *Connection) sendTokenFormTimed(ctx context.Context, form url.Values) (code int,
+	access, refresh *jwt.Token, err error) {
 	// Create the HTTP request:
 	body := []byte(form.Encode())
 	request, err := http.NewRequest(http.MethodPost, c.tokenURL.String(), bytes.NewReader(body))
@@ -239,32 +574,15 @@ func (c *Connection) sendTokenFormTimed(ctx context.Context, form url.Values) (c
 		return
 	}
 
-	// Set the context:
+	// Set the context, and add the trace context to the headers:
 	if ctx != nil {
 		request = request.WithContext(ctx)
+		c.tracePropagator.Inject(ctx, header)
 	}
 
 	// Send the HTTP request:
 	if c.logger.DebugEnabled() {
-		var censoredBody bytes.Buffer
-		// Unlike real url.Values.Encode(), this doesn't sort keys.
-		for name, values := range form {
-			for _, value := range values {
-				// Buffer.Write*() don't require error checking but golangci-lint v1.10.2
-				// on Jenkins flags them (maybe https://github.com/securego/gosec/issues/267).
-				if censoredBody.Len() > 0 {
-					censoredBody.WriteByte('&') // #nosec G104
-				}
-				censoredBody.WriteString(url.QueryEscape(name) + "=") // #nosec G104
-
-				if isRedactField(name) {
-					censoredBody.WriteString(redactionStr) // #nosec G104
-				} else {
-					censoredBody.WriteString(url.QueryEscape(value)) // #nosec G104
-				}
-			}
-		}
-		c.dumpRequest(ctx, request, censoredBody.Bytes())
+		c.dumpRequest(ctx, request, censorForm(form))
 	}
 	response, err := c.client.Do(request)
 	if err != nil {
@@ -283,14 +601,22 @@ func (c *Connection) sendTokenFormTimed(ctx context.Context, form url.Values) (c
 		c.dumpResponse(ctx, response, body)
 	}
 
-	// Check the response status and content type:
+	// Check the response status and content type. Note that OpenID servers usually report
+	// errors with a JSON body containing the `error` field, and we need to return it, as some
+	// grants use it to decide what to do next.
 	code = response.StatusCode
+	header = response.Header
+	content := header.Get("Content-Type")
 	if response.StatusCode != http.StatusOK {
+		var msg internal.TokenResponse
+		if content == "application/json" && json.Unmarshal(body, &msg) == nil &&
+			msg.Error != nil {
+			err = newTokenError(msg.Error, msg.ErrorDescription)
+			return
+		}
 		err = fmt.Errorf("token response status is: %s", response.Status)
 		return
 	}
-	header = response.Header
-	content := header.Get("Content-Type")
 	if content != "application/json" {
 		err = fmt.Errorf("expected 'application/json' but got '%s'", content)
 		return
@@ -304,11 +630,7 @@ func (c *Connection) sendTokenFormTimed(ctx context.Context, form url.Values) (c
 		return
 	}
 	if msg.Error != nil {
-		if msg.ErrorDescription != nil {
-			err = fmt.Errorf("%s: %s", *msg.Error, *msg.ErrorDescription)
-			return
-		}
-		err = fmt.Errorf("%s", *msg.Error)
+		err = newTokenError(msg.Error, msg.ErrorDescription)
 		return
 	}
 	if msg.TokenType != nil && *msg.TokenType != "bearer" {
@@ -332,13 +654,39 @@ func (c *Connection) sendTokenFormTimed(ctx context.Context, form url.Values) (c
 		return
 	}
 
-	// Save the new tokens:
-	c.accessToken = accessToken
-	c.refreshToken = refreshToken
+	access = accessToken
+	refresh = refreshToken
 
 	return
 }
 
+// tokenError is the error returned when the token endpoint responds with an OAuth error code, like
+// `invalid_grant` or `authorization_pending`.
+type tokenError struct {
+	code        string
+	description string
+}
+
+// newTokenError creates a token error from the values of the `error` and `error_description`
+// fields of a response.
+func newTokenError(code, description *string) *tokenError {
+	result := &tokenError{
+		code: *code,
+	}
+	if description != nil {
+		result.description = *description
+	}
+	return result
+}
+
+// Error is the implementation of the error interface.
+func (e *tokenError) Error() string {
+	if e.description != "" {
+		return fmt.Sprintf("%s: %s", e.code, e.description)
+	}
+	return e.code
+}
+
 // debugExpiry sends to the log information about the expiration of the given token.
 func (c *Connection) debugExpiry(ctx context.Context, typ string, token *jwt.Token, expires bool,
 	left time.Duration) {
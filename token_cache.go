@@ -0,0 +1,276 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the on-disk cache that allows multiple processes to
+// share the tokens obtained with the same credentials.
+
+package sdk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// tokenCacheFile is the type used to read and write the content of the token cache file.
+type tokenCacheFile struct {
+	Entries map[string]*tokenCacheEntry `json:"entries,omitempty"`
+}
+
+// tokenCacheEntry contains the tokens obtained with one set of credentials.
+type tokenCacheEntry struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// tokenCache stores tokens in a file, so that they can be shared by multiple processes. Access to
+// the file is protected with an advisory lock, so that processes don't overwrite each other's
+// changes.
+type tokenCache struct {
+	path string
+	key  string
+}
+
+// newTokenCache creates a token cache that stores the tokens in the given file. The key
+// identifies the credentials used to obtain the tokens, and is calculated from the token URL,
+// the client identifier, the user name, the scopes and the subject. The subject should be empty
+// when the other values are enough to identify the credentials, for example when the tokens are
+// obtained with a user name and password. Otherwise it should be calculated with the tokenSubject
+// function from the token that the tokens are derived from, so that connections created with
+// tokens for different identities don't share the cache entry.
+func newTokenCache(path string, tokenURL, clientID, user string, scopes []string,
+	subject string) *tokenCache {
+	hash := sha256.New()
+	fmt.Fprintf(
+		hash,
+		"%s\n%s\n%s\n%s\n%s",
+		tokenURL, clientID, user, strings.Join(scopes, " "), subject,
+	)
+	return &tokenCache{
+		path: path,
+		key:  hex.EncodeToString(hash.Sum(nil)),
+	}
+}
+
+// tokenSubject calculates the text that identifies the subject of the given token for the purpose
+// of calculating the key of the token cache. This is the value of the `sub` claim, if the token has
+// it, or the hash of the complete token otherwise.
+func tokenSubject(token *jwt.Token) string {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if ok {
+		sub, ok := claims["sub"].(string)
+		if ok && sub != "" {
+			return "sub:" + sub
+		}
+	}
+	sum := sha256.Sum256([]byte(token.Raw))
+	return "token:" + hex.EncodeToString(sum[:])
+}
+
+// load returns the tokens stored in the cache. If there are no tokens for the key of the cache it
+// returns empty strings.
+func (c *tokenCache) load() (access, refresh string, err error) {
+	var content *tokenCacheFile
+	err = c.update(false, func(value *tokenCacheFile) bool {
+		content = value
+		return false
+	})
+	if err != nil {
+		return
+	}
+	entry := content.Entries[c.key]
+	if entry != nil {
+		access = entry.AccessToken
+		refresh = entry.RefreshToken
+	}
+	return
+}
+
+// save stores the given tokens in the cache, preserving the tokens stored for other keys.
+func (c *tokenCache) save(access, refresh string) error {
+	return c.update(true, func(content *tokenCacheFile) bool {
+		if content.Entries == nil {
+			content.Entries = map[string]*tokenCacheEntry{}
+		}
+		content.Entries[c.key] = &tokenCacheEntry{
+			AccessToken:  access,
+			RefreshToken: refresh,
+		}
+		return true
+	})
+}
+
+// update locks the cache file, reads it and calls the given function with the content. If the
+// function returns true the content is written back to the file before releasing the lock.
+func (c *tokenCache) update(write bool, f func(content *tokenCacheFile) bool) error {
+	// Open the file, creating it and the directory if needed. Note that the permissions of the
+	// file are adjusted even if it already exists, as it contains credentials.
+	err := os.MkdirAll(filepath.Dir(c.path), 0700)
+	if err != nil {
+		return fmt.Errorf("can't create directory for token cache '%s': %v", c.path, err)
+	}
+	file, err := os.OpenFile(c.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("can't open token cache '%s': %v", c.path, err)
+	}
+	defer file.Close()
+	err = file.Chmod(0600)
+	if err != nil {
+		return fmt.Errorf("can't change permissions of token cache '%s': %v", c.path, err)
+	}
+
+	// Lock the file:
+	err = lockFile(file, write)
+	if err != nil {
+		return fmt.Errorf("can't lock token cache '%s': %v", c.path, err)
+	}
+	defer unlockFile(file) // #nosec G307
+
+	// Read the content. An empty file is valid, it means that nothing has been saved yet.
+	data, err := ioutil.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("can't read token cache '%s': %v", c.path, err)
+	}
+	content := &tokenCacheFile{}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, content)
+		if err != nil && !write {
+			return fmt.Errorf("can't parse token cache '%s': %v", c.path, err)
+		}
+	}
+
+	// Call the function and write the result:
+	if !f(content) {
+		return nil
+	}
+	data, err = json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("can't serialize token cache '%s': %v", c.path, err)
+	}
+	err = file.Truncate(0)
+	if err == nil {
+		_, err = file.WriteAt(data, 0)
+	}
+	if err != nil {
+		return fmt.Errorf("can't write token cache '%s': %v", c.path, err)
+	}
+	return nil
+}
+
+// loadCachedTokens reads the tokens from the cache and replaces the current tokens of the
+// connection with them if they are better, for example if they were obtained by other process
+// and expire later. If token verification is enabled the access token is verified before using
+// it. Failures are reported to the log, but otherwise ignored, as the connection can still request
+// new tokens.
+func (c *Connection) loadCachedTokens(ctx context.Context) {
+	c.tokenMutex.Lock()
+	cache := c.tokenCache
+	c.tokenMutex.Unlock()
+	if cache == nil {
+		return
+	}
+	access, refresh, err := cache.load()
+	if err != nil {
+		c.logger.Warn(ctx, "Can't load tokens from cache: %v", err)
+		return
+	}
+	var accessToken, refreshToken *jwt.Token
+	if access != "" {
+		accessToken, _, err = c.tokenParser.ParseUnverified(access, jwt.MapClaims{})
+		if err != nil {
+			c.logger.Warn(ctx, "Can't parse access token from cache: %v", err)
+			accessToken = nil
+		}
+	}
+	if accessToken != nil && c.tokenVerifier != nil {
+		err = c.tokenVerifier.verify(ctx, accessToken, false)
+		if err != nil {
+			c.logger.Warn(ctx, "Ignoring access token from cache: %v", err)
+			accessToken = nil
+		}
+	}
+	if refresh != "" {
+		refreshToken, _, err = c.tokenParser.ParseUnverified(refresh, jwt.MapClaims{})
+		if err != nil {
+			c.logger.Warn(ctx, "Can't parse refresh token from cache: %v", err)
+			refreshToken = nil
+		}
+	}
+	now := time.Now()
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	if accessToken != nil && tokenBetter(accessToken, c.accessToken, now) {
+		c.logger.Debug(ctx, "Using access token from cache")
+		c.accessToken = accessToken
+	}
+	if refreshToken != nil && tokenBetter(refreshToken, c.refreshToken, now) {
+		c.logger.Debug(ctx, "Using refresh token from cache")
+		c.refreshToken = refreshToken
+	}
+}
+
+// saveCachedTokens writes the given tokens to the cache. Failures are reported to the log, but
+// otherwise ignored.
+func (c *Connection) saveCachedTokens(ctx context.Context, access, refresh *jwt.Token) {
+	c.tokenMutex.Lock()
+	cache := c.tokenCache
+	c.tokenMutex.Unlock()
+	if cache == nil {
+		return
+	}
+	var accessText, refreshText string
+	if access != nil {
+		accessText = access.Raw
+	}
+	if refresh != nil {
+		refreshText = refresh.Raw
+	}
+	err := cache.save(accessText, refreshText)
+	if err != nil {
+		c.logger.Warn(ctx, "Can't save tokens to cache: %v", err)
+	}
+}
+
+// tokenBetter checks if the candidate token expires later than the current one.
+func tokenBetter(candidate, current *jwt.Token, now time.Time) bool {
+	if current == nil {
+		return true
+	}
+	if candidate.Raw == current.Raw {
+		return false
+	}
+	candidateExpires, candidateLeft, err := tokenExpiry(candidate, now)
+	if err != nil {
+		return false
+	}
+	if !candidateExpires {
+		return true
+	}
+	currentExpires, currentLeft, err := tokenExpiry(current, now)
+	if err != nil {
+		return true
+	}
+	return currentExpires && candidateLeft > currentLeft
+}
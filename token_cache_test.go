@@ -0,0 +1,308 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the on-disk token cache.
+
+package sdk
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Token cache", func() {
+	// Server used during the tests:
+	var oidServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Directory and file for the cache:
+	var tmp string
+	var path string
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the server:
+		oidServer = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Create the directory for the cache:
+		tmp, err = ioutil.TempDir("", "cache")
+		Expect(err).ToNot(HaveOccurred())
+		path = filepath.Join(tmp, "ocm", "tokens.json")
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		oidServer.Close()
+
+		// Remove the cache:
+		err := os.RemoveAll(tmp)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Reuses the tokens obtained by other connection", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyPasswordGrant("myuser", "mypassword"),
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Get the tokens with a first connection:
+		first, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			User("myuser", "mypassword").
+			TokenCache(path).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer first.Close()
+		returnedAccess, _, err := first.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+
+		// Get the tokens with a second connection, and verify that no new request was sent:
+		second, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			User("myuser", "mypassword").
+			TokenCache(path).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer second.Close()
+		returnedAccess, returnedRefresh, err := second.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+		Expect(returnedRefresh).To(Equal(refreshToken))
+		Expect(oidServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Creates the file with restricted permissions", func() {
+		// Configure the server:
+		oidServer.AppendHandlers(
+			RespondWithTokens(
+				DefaultToken("Bearer", 5*time.Minute),
+				DefaultToken("Refresh", 10*time.Hour),
+			),
+		)
+
+		// Get the tokens:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			User("myuser", "mypassword").
+			TokenCache(path).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+		_, _, err = connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Check the permissions:
+		info, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("Doesn't share tokens obtained with different credentials", func() {
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyPasswordGrant("firstuser", "mypassword"),
+				RespondWithTokens(
+					DefaultToken("Bearer", 5*time.Minute),
+					DefaultToken("Refresh", 10*time.Hour),
+				),
+			),
+			ghttp.CombineHandlers(
+				VerifyPasswordGrant("seconduser", "mypassword"),
+				RespondWithTokens(
+					DefaultToken("Bearer", 5*time.Minute),
+					DefaultToken("Refresh", 10*time.Hour),
+				),
+			),
+		)
+
+		// Get the tokens with two connections that use different users:
+		for _, user := range []string{"firstuser", "seconduser"} {
+			connection, err := NewConnectionBuilder().
+				Logger(logger).
+				TokenURL(oidServer.URL()).
+				User(user, "mypassword").
+				TokenCache(path).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			_, _, err = connection.Tokens()
+			Expect(err).ToNot(HaveOccurred())
+			err = connection.Close()
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		// Check that both connections sent requests:
+		Expect(oidServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Doesn't share tokens obtained with offline tokens of different subjects", func() {
+		// Generate the offline tokens:
+		firstOffline := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"typ": "Offline",
+			"sub": "firstuser",
+			"exp": 0,
+		})
+		secondOffline := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"typ": "Offline",
+			"sub": "seconduser",
+			"exp": 0,
+		})
+
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyRefreshGrant(firstOffline),
+				RespondWithTokens(DefaultToken("Bearer", 5*time.Minute), firstOffline),
+			),
+			ghttp.CombineHandlers(
+				VerifyRefreshGrant(secondOffline),
+				RespondWithTokens(DefaultToken("Bearer", 5*time.Minute), secondOffline),
+			),
+		)
+
+		// Get the tokens with two connections that use different offline tokens:
+		for _, offline := range []string{firstOffline, secondOffline} {
+			connection, err := NewConnectionBuilder().
+				Logger(logger).
+				TokenURL(oidServer.URL()).
+				Tokens(offline).
+				TokenCache(path).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			_, refresh, err := connection.Tokens()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(refresh).To(Equal(offline))
+			err = connection.Close()
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		// Check that both connections sent requests:
+		Expect(oidServer.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("Shares the tokens returned by token sources for the same subject", func() {
+		accessToken := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"typ": "Bearer",
+			"sub": "myuser",
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		source := &sequenceTokenSource{
+			mutex: &sync.Mutex{},
+			tokens: []string{
+				accessToken,
+			},
+		}
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenSource(source).
+			TokenCache(path).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+		_, _, err = connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Check that the token was saved with a key that contains the subject:
+		cache := newTokenCache(
+			path,
+			DefaultTokenURL, DefaultClientID, "", DefaultScopes, "sub:myuser",
+		)
+		cached, _, err := cache.load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cached).To(Equal(accessToken))
+	})
+
+	It("Ignores a corrupt cache file", func() {
+		// Write junk to the cache file:
+		err := os.MkdirAll(filepath.Dir(path), 0700)
+		Expect(err).ToNot(HaveOccurred())
+		err = ioutil.WriteFile(path, []byte("junk"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		// Configure the server:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		oidServer.AppendHandlers(
+			RespondWithTokens(accessToken, DefaultToken("Refresh", 10*time.Hour)),
+		)
+
+		// Get the tokens:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			User("myuser", "mypassword").
+			TokenCache(path).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+	})
+
+	It("Preserves entries when saved concurrently", func() {
+		const count = 10
+		var wg sync.WaitGroup
+		wg.Add(count)
+		for i := 0; i < count; i++ {
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				cache := newTokenCache(path, "myurl", "myclient", string(rune('a'+i)), nil, "")
+				err := cache.save("myaccess", "myrefresh")
+				Expect(err).ToNot(HaveOccurred())
+			}(i)
+		}
+		wg.Wait()
+		content := &tokenCacheFile{}
+		cache := newTokenCache(path, "myurl", "myclient", "", nil, "")
+		err := cache.update(false, func(value *tokenCacheFile) bool {
+			content = value
+			return false
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content.Entries).To(HaveLen(count))
+	})
+})
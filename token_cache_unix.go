@@ -0,0 +1,42 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to lock the token cache file in Unix systems.
+
+package sdk
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile acquires an advisory lock on the given file, waiting till it is available. The lock is
+// exclusive if write is true, and shared otherwise.
+func lockFile(file *os.File, write bool) error {
+	how := syscall.LOCK_SH
+	if write {
+		how = syscall.LOCK_EX
+	}
+	return syscall.Flock(int(file.Fd()), how)
+}
+
+// unlockFile releases the lock acquired with lockFile.
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to lock the token cache file in Windows systems.
+
+package sdk
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Functions and flags of the Windows API used to lock files:
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+const lockfileExclusiveLock = 0x00000002
+
+// lockFile acquires a lock on the given file, waiting till it is available. The lock is exclusive
+// if write is true, and shared otherwise.
+func lockFile(file *os.File, write bool) error {
+	var flags uintptr
+	if write {
+		flags = lockfileExclusiveLock
+	}
+	var overlapped syscall.Overlapped
+	result, _, err := procLockFileEx.Call(
+		file.Fd(),
+		flags,
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(&overlapped)), // #nosec G103
+	)
+	if result == 0 {
+		return err
+	}
+	return nil
+}
+
+// unlockFile releases the lock acquired with lockFile.
+func unlockFile(file *os.File) error {
+	var overlapped syscall.Overlapped
+	result, _, err := procUnlockFileEx.Call(
+		file.Fd(),
+		0,
+		1,
+		0,
+		uintptr(unsafe.Pointer(&overlapped)), // #nosec G103
+	)
+	if result == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the OAuth authorization code grant with proof key for
+// code exchange, as described in RFC 6749 and RFC 7636, using a local loopback listener to receive
+// the redirect, as described in RFC 8252.
+
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// AuthorizationCodeHandler is the type of the functions that are called when the connection needs
+// the user to log in with a browser. The function receives the authorization URL and should open
+// it with a browser, or ask the user to do so. If the function returns an error the login will be
+// aborted.
+type AuthorizationCodeHandler func(ctx context.Context, authorizationURL string) error
+
+// authorizationCodeTimeout is the maximum time that the connection will wait for the user to
+// complete the login in the browser.
+var authorizationCodeTimeout = 5 * time.Minute
+
+// authorizationCodePath is the path of the loopback listener where the authorization server
+// redirects the browser.
+const authorizationCodePath = "/callback"
+
+// authorizationCodeResult contains the result of the redirect received by the loopback listener.
+type authorizationCodeResult struct {
+	code string
+	err  error
+}
+
+// sendAuthorizationCodeForm starts the loopback listener, asks the user to log in with a browser,
+// waits for the redirect and then exchanges the authorization code for tokens.
+func (c *Connection) sendAuthorizationCodeForm(ctx context.Context) (access,
+	refresh *jwt.Token, err error) {
+	c.logger.Debug(ctx, "Requesting new token using the authorization code grant")
+
+	// Generate the PKCE verifier and the state:
+	verifier, err := randomText(32)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't generate code verifier: %v", err)
+	}
+	state, err := randomText(16)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't generate state: %v", err)
+	}
+
+	// Start the loopback listener. Note that we use a random port because the authorization
+	// server should accept any port for loopback redirect URIs.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't start loopback listener: %v", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s%s", listener.Addr().String(), authorizationCodePath)
+	results := make(chan authorizationCodeResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(authorizationCodePath, func(w http.ResponseWriter, r *http.Request) {
+		result, ok := c.checkAuthorizationCodeRedirect(r, state)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if !ok {
+			// Requests that don't contain the right state may come from anything that can
+			// reach the loopback address, so they are rejected but the login continues
+			// waiting for the real redirect.
+			c.logger.Warn(
+				r.Context(),
+				"Ignoring authorization response because the state doesn't match",
+			)
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "State of the authorization response doesn't match.\n") // #nosec G104
+			return
+		}
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "Login failed: %v\n", result.err) // #nosec G104
+		} else {
+			fmt.Fprintf(w, "Login succeeded, you can close this window.\n") // #nosec G104
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{
+		Handler: mux,
+	}
+	go server.Serve(listener) // #nosec G104
+	defer server.Close()
+
+	// Ask the user to open the authorization URL with a browser:
+	authorizationURL := *c.codeURL
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", c.clientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", strings.Join(c.scopes, " "))
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authorizationURL.RawQuery = query.Encode()
+	err = c.codeHandler(ctx, authorizationURL.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("authorization code handler failed: %v", err)
+	}
+
+	// Wait for the redirect:
+	timer := time.NewTimer(authorizationCodeTimeout)
+	defer timer.Stop()
+	var result authorizationCodeResult
+	select {
+	case result = <-results:
+	case <-timer.C:
+		return nil, nil, fmt.Errorf(
+			"user didn't complete the login in %s",
+			authorizationCodeTimeout,
+		)
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+	if result.err != nil {
+		return nil, nil, result.err
+	}
+
+	// Exchange the authorization code for tokens:
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", result.code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", c.clientID)
+	form.Set("code_verifier", verifier)
+	if c.clientSecret != "" {
+		form.Set("client_secret", c.clientSecret)
+	}
+	return c.sendTokenForm(ctx, form)
+}
+
+// checkAuthorizationCodeRedirect checks the redirect request sent by the browser and extracts the
+// authorization code. The returned flag indicates if the request should finish the login, which is
+// the case when the state matches or when the authorization server reported an error.
+func (c *Connection) checkAuthorizationCodeRedirect(r *http.Request,
+	state string) (result authorizationCodeResult, ok bool) {
+	query := r.URL.Query()
+	code := query.Get("error")
+	if code != "" {
+		result.err = &tokenError{
+			code:        code,
+			description: query.Get("error_description"),
+		}
+		ok = true
+		return
+	}
+	if query.Get("state") != state {
+		return
+	}
+	ok = true
+	result.code = query.Get("code")
+	if result.code == "" {
+		result.err = fmt.Errorf("authorization response doesn't contain a code")
+		return
+	}
+	return
+}
+
+// randomText generates a random URL safe text from the given number of random bytes.
+func randomText(size int) (text string, err error) {
+	data := make([]byte, size)
+	_, err = rand.Read(data)
+	if err != nil {
+		return
+	}
+	text = base64.RawURLEncoding.EncodeToString(data)
+	return
+}
+
+// codeChallenge calculates the PKCE challenge for the given verifier, using the `S256` method.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// defaultCodeURL calculates the default authorization URL from the token URL, using the
+// conventions of Keycloak. For example, if the token URL is `.../openid-connect/token` then the
+// authorization URL will be `.../openid-connect/auth`.
+func defaultCodeURL(tokenURL *url.URL) *url.URL {
+	result := *tokenURL
+	result.Path = strings.TrimSuffix(result.Path, "/token") + "/auth"
+	return &result
+}
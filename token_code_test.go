@@ -0,0 +1,275 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the authorization code grant.
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+)
+
+var _ = Describe("Authorization code grant", func() {
+	// Fake OpenID provider used during the tests:
+	var provider *fakeProvider
+	var oidServer *httptest.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Handler that simulates the browser, following the redirects sent by the provider:
+	browser := func(ctx context.Context, authorizationURL string) error {
+		response, err := http.Get(authorizationURL)
+		if err != nil {
+			return err
+		}
+		return response.Body.Close()
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the provider:
+		provider = &fakeProvider{
+			mutex:        &sync.Mutex{},
+			code:         "mycode",
+			accessToken:  DefaultToken("Bearer", 5*time.Minute),
+			refreshToken: DefaultToken("Refresh", 10*time.Hour),
+		}
+		oidServer = httptest.NewServer(provider)
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		oidServer.Close()
+	})
+
+	It("Obtains the tokens using the browser", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL + "/token").
+			AuthorizationCode(browser).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, returnedRefresh, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(provider.accessToken))
+		Expect(returnedRefresh).To(Equal(provider.refreshToken))
+
+		// Check the authorization request:
+		Expect(provider.query.Get("response_type")).To(Equal("code"))
+		Expect(provider.query.Get("client_id")).To(Equal(DefaultClientID))
+		Expect(provider.query.Get("code_challenge_method")).To(Equal("S256"))
+		Expect(provider.query.Get("redirect_uri")).To(HavePrefix("http://127.0.0.1:"))
+	})
+
+	It("Uses the explicitly configured authorization URL", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL + "/token").
+			AuthorizationURL(oidServer.URL + "/auth").
+			AuthorizationCode(browser).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(provider.accessToken))
+	})
+
+	It("Merges the query of the configured authorization URL", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL + "/token").
+			AuthorizationURL(oidServer.URL + "/auth?prompt=login").
+			AuthorizationCode(browser).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Check that both the original and the added parameters were sent:
+		Expect(provider.query.Get("prompt")).To(Equal("login"))
+		Expect(provider.query.Get("response_type")).To(Equal("code"))
+	})
+
+	It("Ignores redirects with a wrong state", func() {
+		// Create a browser that first sends a forged redirect with an incorrect state, and
+		// then follows the real flow:
+		forger := func(ctx context.Context, authorizationURL string) error {
+			parsed, err := url.Parse(authorizationURL)
+			if err != nil {
+				return err
+			}
+			redirectURI := parsed.Query().Get("redirect_uri")
+			response, err := http.Get(redirectURI + "?state=junk&code=forged")
+			if err != nil {
+				return err
+			}
+			err = response.Body.Close()
+			if err != nil {
+				return err
+			}
+			Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+			return browser(ctx, authorizationURL)
+		}
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL + "/token").
+			AuthorizationCode(forger).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(provider.accessToken))
+	})
+
+	It("Fails if the user denies the login", func() {
+		// Configure the provider so that it returns an error:
+		provider.error = "access_denied"
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL + "/token").
+			AuthorizationCode(browser).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("access_denied"))
+	})
+
+	It("Fails if the context is cancelled before the redirect", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL + "/token").
+			AuthorizationCode(func(ctx context.Context, authorizationURL string) error {
+				return nil
+			}).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, _, err = connection.TokensContext(ctx)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// fakeProvider is a minimal OpenID provider that supports the authorization code grant with PKCE.
+type fakeProvider struct {
+	mutex        *sync.Mutex
+	code         string
+	error        string
+	accessToken  string
+	refreshToken string
+	query        url.Values
+}
+
+func (p *fakeProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer GinkgoRecover()
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	switch r.URL.Path {
+	case "/auth":
+		p.authorize(w, r)
+	case "/token":
+		p.token(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (p *fakeProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	// Save the query so that it can be checked later:
+	p.query = r.URL.Query()
+
+	// Redirect the browser to the loopback listener:
+	redirect, err := url.Parse(p.query.Get("redirect_uri"))
+	Expect(err).ToNot(HaveOccurred())
+	values := url.Values{}
+	values.Set("state", p.query.Get("state"))
+	if p.error != "" {
+		values.Set("error", p.error)
+	} else {
+		values.Set("code", p.code)
+	}
+	redirect.RawQuery = values.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *fakeProvider) token(w http.ResponseWriter, r *http.Request) {
+	// Check the form:
+	err := r.ParseForm()
+	Expect(err).ToNot(HaveOccurred())
+	Expect(r.PostForm.Get("grant_type")).To(Equal("authorization_code"))
+	Expect(r.PostForm.Get("code")).To(Equal(p.code))
+	Expect(r.PostForm.Get("redirect_uri")).To(Equal(p.query.Get("redirect_uri")))
+	Expect(r.PostForm.Get("client_id")).To(Equal(p.query.Get("client_id")))
+
+	// Check that the verifier matches the challenge:
+	verifier := r.PostForm.Get("code_verifier")
+	Expect(strings.TrimSpace(verifier)).ToNot(BeEmpty())
+	Expect(codeChallenge(verifier)).To(Equal(p.query.Get("code_challenge")))
+
+	// Return the tokens:
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(map[string]string{
+		"access_token":  p.accessToken,
+		"refresh_token": p.refreshToken,
+	})
+	Expect(err).ToNot(HaveOccurred())
+}
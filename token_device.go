@@ -0,0 +1,248 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the OAuth device authorization grant, as described in
+// RFC 8628.
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/openshift-online/ocm-sdk-go/internal"
+)
+
+// DeviceAuthorization contains the details that the user needs in order to complete the device
+// authorization grant: the URL that should be opened with a browser and the code that should be
+// entered there.
+type DeviceAuthorization struct {
+	// UserCode is the code that the user should enter in the verification page.
+	UserCode string
+
+	// VerificationURI is the address of the verification page.
+	VerificationURI string
+
+	// VerificationURIComplete is the address of the verification page including the user code,
+	// so that the user doesn't need to type it. This is optional, and will be empty if the server
+	// doesn't support it.
+	VerificationURIComplete string
+
+	// ExpiresIn is the time that the user has to complete the verification.
+	ExpiresIn time.Duration
+}
+
+// DeviceAuthorizationHandler is the type of the functions that are called when the connection
+// needs the user to complete the device authorization grant. The function should show the user
+// the verification URL and the user code. If the function returns an error the grant will be
+// aborted.
+type DeviceAuthorizationHandler func(ctx context.Context, authorization *DeviceAuthorization) error
+
+// Default values for the device authorization grant, as defined in RFC 8628:
+const (
+	deviceGrantType        = "urn:ietf:params:oauth:grant-type:device_code"
+	deviceDefaultInterval  = 5 * time.Second
+	deviceDefaultExpiresIn = 10 * time.Minute
+)
+
+// deviceSlowDownIncrement is the time added to the polling interval when the server responds with
+// the `slow_down` error code.
+var deviceSlowDownIncrement = 5 * time.Second
+
+// deviceMinInterval is the minimum time to wait between polling requests. Intervals sent by the
+// server that are shorter than this, including zero or negative ones, are replaced by this value so
+// that a misbehaving server can't make the connection poll without waiting.
+var deviceMinInterval = 1 * time.Second
+
+// sendDeviceCodeForm requests a device code, asks the user to complete the verification and then
+// polls the token endpoint till the user has completed it.
+func (c *Connection) sendDeviceCodeForm(ctx context.Context) (access,
+	refresh *jwt.Token, err error) {
+	c.logger.Debug(ctx, "Requesting new token using the device authorization grant")
+
+	// Request the device code:
+	form := url.Values{}
+	form.Set("client_id", c.clientID)
+	if c.clientSecret != "" {
+		form.Set("client_secret", c.clientSecret)
+	}
+	form.Set("scope", strings.Join(c.scopes, " "))
+	msg, err := c.sendDeviceAuthorizationForm(ctx, form)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Ask the user to complete the verification:
+	authorization := &DeviceAuthorization{
+		UserCode:        *msg.UserCode,
+		VerificationURI: *msg.VerificationURI,
+		ExpiresIn:       deviceDefaultExpiresIn,
+	}
+	if msg.VerificationURIComplete != nil {
+		authorization.VerificationURIComplete = *msg.VerificationURIComplete
+	}
+	if msg.ExpiresIn != nil {
+		authorization.ExpiresIn = time.Duration(*msg.ExpiresIn) * time.Second
+	}
+	err = c.deviceHandler(ctx, authorization)
+	if err != nil {
+		return nil, nil, fmt.Errorf("device authorization handler failed: %v", err)
+	}
+
+	// Poll the token endpoint till the user completes the verification, or till the device
+	// code expires:
+	interval := deviceDefaultInterval
+	if msg.Interval != nil {
+		interval = time.Duration(*msg.Interval) * time.Second
+	}
+	if interval < deviceMinInterval {
+		interval = deviceMinInterval
+	}
+	deadline := time.Now().Add(authorization.ExpiresIn)
+	form = url.Values{}
+	form.Set("grant_type", deviceGrantType)
+	form.Set("device_code", *msg.DeviceCode)
+	form.Set("client_id", c.clientID)
+	if c.clientSecret != "" {
+		form.Set("client_secret", c.clientSecret)
+	}
+	for {
+		if time.Now().Add(interval).After(deadline) {
+			return nil, nil, fmt.Errorf(
+				"device code expired before the user completed the verification",
+			)
+		}
+		err = retryWait(ctx, interval)
+		if err != nil {
+			return nil, nil, err
+		}
+		access, refresh, err = c.sendTokenForm(ctx, form)
+		if err == nil {
+			return
+		}
+		tokenErr, ok := err.(*tokenError)
+		if !ok {
+			return nil, nil, err
+		}
+		switch tokenErr.code {
+		case "authorization_pending":
+			c.logger.Debug(ctx, "Device authorization is still pending")
+		case "slow_down":
+			interval += deviceSlowDownIncrement
+			c.logger.Debug(
+				ctx,
+				"Server asked to slow down, will poll every %s",
+				interval,
+			)
+		default:
+			return nil, nil, err
+		}
+	}
+}
+
+// sendDeviceAuthorizationForm sends the given form to the device authorization endpoint and
+// returns the parsed response.
+func (c *Connection) sendDeviceAuthorizationForm(ctx context.Context,
+	form url.Values) (msg *internal.DeviceAuthorizationResponse, err error) {
+	// Create the HTTP request:
+	body := []byte(form.Encode())
+	request, err := http.NewRequest(http.MethodPost, c.deviceURL.String(), bytes.NewReader(body))
+	if err != nil {
+		err = fmt.Errorf("can't create request: %v", err)
+		return
+	}
+	request.Close = true
+	header := request.Header
+	if c.agent != "" {
+		header.Set("User-Agent", c.agent)
+	}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Accept", "application/json")
+	request = request.WithContext(ctx)
+
+	// Send the HTTP request:
+	if c.logger.DebugEnabled() {
+		c.dumpRequest(ctx, request, censorForm(form))
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		err = fmt.Errorf("can't send request: %v", err)
+		return
+	}
+	defer response.Body.Close()
+
+	// Read the response body:
+	body, err = ioutil.ReadAll(response.Body)
+	if err != nil {
+		err = fmt.Errorf("can't read response: %v", err)
+		return
+	}
+	if c.logger.DebugEnabled() {
+		c.dumpResponse(ctx, response, body)
+	}
+
+	// Parse the response body:
+	msg = &internal.DeviceAuthorizationResponse{}
+	err = json.Unmarshal(body, msg)
+	if err != nil {
+		if response.StatusCode != http.StatusOK {
+			err = fmt.Errorf("device authorization response status is: %s", response.Status)
+			return
+		}
+		err = fmt.Errorf("can't parse JSON response: %v", err)
+		return
+	}
+	if msg.Error != nil {
+		err = newTokenError(msg.Error, msg.ErrorDescription)
+		return
+	}
+	if response.StatusCode != http.StatusOK {
+		err = fmt.Errorf("device authorization response status is: %s", response.Status)
+		return
+	}
+	if msg.DeviceCode == nil {
+		err = fmt.Errorf("no device code was received")
+		return
+	}
+	if msg.UserCode == nil {
+		err = fmt.Errorf("no user code was received")
+		return
+	}
+	if msg.VerificationURI == nil {
+		err = fmt.Errorf("no verification URI was received")
+		return
+	}
+
+	return
+}
+
+// defaultDeviceURL calculates the default device authorization URL from the token URL, using the
+// conventions of Keycloak. For example, if the token URL is `.../openid-connect/token` then the
+// device authorization URL will be `.../openid-connect/auth/device`.
+func defaultDeviceURL(tokenURL *url.URL) *url.URL {
+	result := *tokenURL
+	result.Path = strings.TrimSuffix(result.Path, "/token") + "/auth/device"
+	return &result
+}
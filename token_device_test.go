@@ -0,0 +1,412 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the device authorization grant.
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Device authorization grant", func() {
+	// Server used during the tests:
+	var oidServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Authorization received by the handler:
+	var received *DeviceAuthorization
+
+	// Handler that saves the authorization:
+	handler := func(ctx context.Context, authorization *DeviceAuthorization) error {
+		received = authorization
+		return nil
+	}
+
+	// Minimum polling interval saved before the tests:
+	var savedMinInterval time.Duration
+
+	BeforeEach(func() {
+		var err error
+
+		// Use a small minimum polling interval so that the tests don't take too long:
+		savedMinInterval = deviceMinInterval
+		deviceMinInterval = 10 * time.Millisecond
+
+		// Create the server:
+		oidServer = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Forget the previous authorization:
+		received = nil
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		oidServer.Close()
+
+		// Restore the minimum polling interval:
+		deviceMinInterval = savedMinInterval
+	})
+
+	It("Passes the user code and verification URI to the handler", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyDeviceAuthorization(),
+				RespondWithDeviceCode(0),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL() + "/token").
+			DeviceCode(handler).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, returnedRefresh, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+		Expect(returnedRefresh).To(Equal(refreshToken))
+
+		// Check the authorization received by the handler:
+		Expect(received).ToNot(BeNil())
+		Expect(received.UserCode).To(Equal("ABCD-EFGH"))
+		Expect(received.VerificationURI).To(Equal("https://sso.example.com/device"))
+		Expect(received.VerificationURIComplete).To(Equal(
+			"https://sso.example.com/device?user_code=ABCD-EFGH",
+		))
+		Expect(received.ExpiresIn).To(Equal(10 * time.Minute))
+	})
+
+	It("Polls while the authorization is pending", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyDeviceAuthorization(),
+				RespondWithDeviceCode(0),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithDeviceError("authorization_pending"),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithDeviceError("authorization_pending"),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL() + "/token").
+			DeviceCode(handler).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+		Expect(oidServer.ReceivedRequests()).To(HaveLen(4))
+	})
+
+	It("Slows down when requested by the server", func() {
+		// Use a small increment so that the test doesn't take too long:
+		saved := deviceSlowDownIncrement
+		deviceSlowDownIncrement = 100 * time.Millisecond
+		defer func() {
+			deviceSlowDownIncrement = saved
+		}()
+
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyDeviceAuthorization(),
+				RespondWithDeviceCode(0),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithDeviceError("slow_down"),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL() + "/token").
+			DeviceCode(handler).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens and check that the connection waited before the last request:
+		before := time.Now()
+		returnedAccess, _, err := connection.Tokens()
+		elapsed := time.Since(before)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+		Expect(elapsed).To(BeNumerically(">=", 100*time.Millisecond))
+	})
+
+	It("Doesn't poll faster than the minimum interval", func() {
+		deviceMinInterval = 100 * time.Millisecond
+
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server so that it sends a negative interval:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyDeviceAuthorization(),
+				RespondWithDeviceCode(-1),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithDeviceError("authorization_pending"),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL() + "/token").
+			DeviceCode(handler).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens and check that the connection waited before each poll:
+		before := time.Now()
+		returnedAccess, _, err := connection.Tokens()
+		elapsed := time.Since(before)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+		Expect(elapsed).To(BeNumerically(">=", 200*time.Millisecond))
+	})
+
+	It("Fails if the user denies the authorization", func() {
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyDeviceAuthorization(),
+				RespondWithDeviceCode(0),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithDeviceError("access_denied"),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL() + "/token").
+			DeviceCode(handler).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("access_denied"))
+	})
+
+	It("Fails if the handler fails", func() {
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyDeviceAuthorization(),
+				RespondWithDeviceCode(0),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL() + "/token").
+			DeviceCode(func(ctx context.Context, authorization *DeviceAuthorization) error {
+				return fmt.Errorf("myerror")
+			}).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("myerror"))
+		Expect(oidServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Uses the explicitly configured device authorization URL", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/mydevice"),
+				RespondWithDeviceCode(0),
+			),
+			ghttp.CombineHandlers(
+				VerifyDeviceGrant(),
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL() + "/token").
+			DeviceAuthorizationURL(oidServer.URL() + "/mydevice").
+			DeviceCode(handler).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+	})
+
+	It("Uses the refresh token instead of the device grant when possible", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodPost, "/token"),
+				ghttp.VerifyFormKV("grant_type", "refresh_token"),
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL() + "/token").
+			Tokens(refreshToken).
+			DeviceCode(handler).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+		Expect(received).To(BeNil())
+	})
+})
+
+func VerifyDeviceAuthorization() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodPost, "/auth/device"),
+		ghttp.VerifyContentType("application/x-www-form-urlencoded"),
+		ghttp.VerifyFormKV("client_id", DefaultClientID),
+	)
+}
+
+func VerifyDeviceGrant() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest(http.MethodPost, "/token"),
+		ghttp.VerifyContentType("application/x-www-form-urlencoded"),
+		ghttp.VerifyFormKV("grant_type", "urn:ietf:params:oauth:grant-type:device_code"),
+		ghttp.VerifyFormKV("device_code", "mydevicecode"),
+		ghttp.VerifyFormKV("client_id", DefaultClientID),
+	)
+}
+
+func RespondWithDeviceCode(interval int) http.HandlerFunc {
+	return RespondWithJSONTemplate(
+		http.StatusOK,
+		`{
+			"device_code": "mydevicecode",
+			"user_code": "ABCD-EFGH",
+			"verification_uri": "https://sso.example.com/device",
+			"verification_uri_complete": "https://sso.example.com/device?user_code=ABCD-EFGH",
+			"expires_in": 600,
+			"interval": {{ .Interval }}
+		}`,
+		"Interval", interval,
+	)
+}
+
+func RespondWithDeviceError(code string) http.HandlerFunc {
+	return RespondWithJSONTemplate(
+		http.StatusBadRequest,
+		`{
+			"error": "{{ .Error }}"
+		}`,
+		"Error", code,
+	)
+}
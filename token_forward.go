@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the logic that allows the connection to send requests on behalf of the
+// caller of a service, forwarding the bearer token received by that service instead of using the
+// token of the connection.
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+)
+
+// BearerFunc is the type of the functions that extract from a context the bearer token that should
+// be forwarded. The authentication.BearerFromContext function has this signature. It should return
+// an empty string if the context doesn't contain a bearer token.
+type BearerFunc func(ctx context.Context) (string, error)
+
+// ContextWithBearer creates a new context containing a bearer token that will be used, instead of
+// the token of the connection, for the requests sent with that context. For example, in a service
+// that uses the authentication handler, to send a request on behalf of the caller:
+//
+//	bearer, err := authentication.BearerFromContext(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	response, err := connection.ClustersMgmt().V1().Clusters().List().
+//		SendContext(sdk.ContextWithBearer(ctx, bearer))
+func ContextWithBearer(parent context.Context, bearer string) context.Context {
+	return context.WithValue(parent, bearerKeyValue, bearer)
+}
+
+// bearerFromContext returns the bearer token that was added to the context with the
+// ContextWithBearer function, or an empty string if there is no such token.
+func bearerFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	bearer, _ := ctx.Value(bearerKeyValue).(string)
+	return bearer
+}
+
+// requestToken returns the access token that should be used to send a request with the given
+// context. That is the bearer token explicitly added to the context, if any. If there is none and
+// the connection has been configured to forward bearer tokens, it is the one returned by the
+// configured function. Otherwise it is the access token of the connection.
+func (c *Connection) requestToken(ctx context.Context) (token string, err error) {
+	// Check if the context contains an explicit bearer token:
+	token = bearerFromContext(ctx)
+	if token != "" {
+		return
+	}
+
+	// Check if we should forward the bearer token from the context:
+	if c.bearerForwarder != nil && ctx != nil {
+		token, err = c.bearerForwarder(ctx)
+		if err != nil {
+			err = fmt.Errorf("can't get bearer token to forward: %v", err)
+			return
+		}
+		if token != "" {
+			return
+		}
+	}
+
+	// Use the token of the connection:
+	token, _, err = c.TokensContext(ctx)
+	return
+}
+
+// bearerKeyType is the type of the key used to store the bearer token in the context.
+type bearerKeyType string
+
+// bearerKeyValue is the key used to store the bearer token in the context:
+const bearerKeyValue bearerKeyType = "bearer"
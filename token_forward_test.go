@@ -0,0 +1,175 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the forwarding of bearer tokens.
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Bearer forwarding", func() {
+	// Server used during the tests:
+	var apiServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Access token of the connection, and token of the caller:
+	var accessToken string
+	var callerToken string
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the tokens:
+		accessToken = DefaultToken("Bearer", 5*time.Minute)
+		callerToken = DefaultToken("Bearer", 5*time.Minute)
+
+		// Create the API server:
+		apiServer = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		apiServer.Close()
+	})
+
+	// callerKey is the key that the tests use to store the token of the caller in the context,
+	// simulating what the authentication handler does:
+	type callerKeyType string
+	const callerKey callerKeyType = "caller"
+
+	// callerBearer extracts the token of the caller from the context:
+	callerBearer := func(ctx context.Context) (string, error) {
+		bearer, _ := ctx.Value(callerKey).(string)
+		return bearer, nil
+	}
+
+	// verifyBearer returns a handler that verifies the bearer token of the request:
+	verifyBearer := func(token string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyHeaderKV("Authorization", "Bearer "+token),
+			ghttp.RespondWith(http.StatusOK, "{}"),
+		)
+	}
+
+	It("Uses the bearer added explicitly to the context", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			verifyBearer(callerToken),
+			verifyBearer(accessToken),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send a request on behalf of the caller, and then one with the connection token:
+		ctx := ContextWithBearer(context.Background(), callerToken)
+		_, err = connection.Get().Path("/mypath").SendContext(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = connection.Get().Path("/mypath").SendContext(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Forwards the bearer found by the configured function", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			verifyBearer(callerToken),
+			verifyBearer(accessToken),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			ForwardBearer(callerBearer).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send a request with a context that contains the caller token, and one without it:
+		ctx := context.WithValue(context.Background(), callerKey, callerToken)
+		_, err = connection.Get().Path("/mypath").SendContext(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = connection.Get().Path("/mypath").SendContext(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Can be built with only a forwarder", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			verifyBearer(callerToken),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			ForwardBearer(callerBearer).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		ctx := context.WithValue(context.Background(), callerKey, callerToken)
+		_, err = connection.Get().Path("/mypath").SendContext(ctx)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Fails if the forwarder fails", func() {
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			ForwardBearer(func(ctx context.Context) (string, error) {
+				return "", fmt.Errorf("myerror")
+			}).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		_, err = connection.Get().Path("/mypath").SendContext(context.Background())
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("myerror"))
+		Expect(apiServer.ReceivedRequests()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the background goroutine that renews the tokens of the
+// connection before they expire.
+
+package sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// tokenRenewalMinInterval is the minimum time between two consecutive attempts to renew the tokens
+// in the background. This avoids sending requests continuously when the server returns tokens that
+// are already expired or about to expire, or when it fails.
+var tokenRenewalMinInterval = 10 * time.Second
+
+// renewalKeyType is the type of the key used to indicate in the context that tokens are being
+// renewed in the background.
+type renewalKeyType string
+
+// renewalKeyValue is the key used to indicate in the context that tokens are being renewed in the
+// background, so that token sources don't try to interact with the user:
+const renewalKeyValue renewalKeyType = "renewal"
+
+// startRenewer starts the goroutine that renews the tokens in the background.
+func (c *Connection) startRenewer() {
+	var ctx context.Context
+	ctx, c.renewerCancel = context.WithCancel(context.Background())
+	c.renewerStop = make(chan struct{})
+	c.renewerWake = make(chan struct{}, 1)
+	c.renewerDone = make(chan struct{})
+	go c.runRenewer(ctx)
+}
+
+// stopRenewer stops the goroutine that renews the tokens in the background, and waits till it
+// finishes. The context used by the goroutine is cancelled first, so that a renewal request that
+// is in progress is aborted instead of delaying the stop.
+func (c *Connection) stopRenewer() {
+	if c.renewerStop == nil {
+		return
+	}
+	c.renewerCancel()
+	close(c.renewerStop)
+	<-c.renewerDone
+}
+
+// wakeRenewer tells the goroutine that renews the tokens in the background that the tokens have
+// changed, so that it recalculates when it should renew them.
+func (c *Connection) wakeRenewer() {
+	if c.renewerWake == nil {
+		return
+	}
+	select {
+	case c.renewerWake <- struct{}{}:
+	default:
+	}
+}
+
+// runRenewer is the loop executed by the goroutine that renews the tokens in the background.
+func (c *Connection) runRenewer(ctx context.Context) {
+	defer close(c.renewerDone)
+	for {
+		// Wait till it is time to renew the tokens, till the tokens change or till we are
+		// asked to stop:
+		delay, ok := c.renewalDelay(time.Now())
+		if ok {
+			c.logger.Debug(ctx, "Tokens will be renewed in %s", delay)
+			timer := time.NewTimer(delay)
+			select {
+			case <-c.renewerStop:
+				timer.Stop()
+				return
+			case <-c.renewerWake:
+				timer.Stop()
+				continue
+			case <-timer.C:
+			}
+		} else {
+			select {
+			case <-c.renewerStop:
+				return
+			case <-c.renewerWake:
+				continue
+			}
+		}
+
+		// Renew the tokens. If this fails because the connection is being closed then there
+		// is no need to report it.
+		_, _, err := c.renewTokens(ctx, true)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.logger.Warn(ctx, "Can't renew tokens in the background: %v", err)
+			c.updateTokenEventMetric(tokenEventRenewalFailure)
+		} else {
+			c.logger.Debug(ctx, "Tokens have been renewed in the background")
+			c.updateTokenEventMetric(tokenEventRenewal)
+		}
+
+		// Wait a minimum time before trying again, and discard the notification that we may
+		// have sent to ourselves when saving the new tokens:
+		select {
+		case <-c.renewerStop:
+			return
+		case <-time.After(tokenRenewalMinInterval):
+		}
+		select {
+		case <-c.renewerWake:
+		default:
+		}
+	}
+}
+
+// renewalDelay calculates how long to wait before renewing the tokens. It returns false if the
+// tokens don't need to be renewed, for example because there is no access token yet, or because
+// it never expires.
+func (c *Connection) renewalDelay(now time.Time) (delay time.Duration, ok bool) {
+	c.tokenMutex.Lock()
+	token := c.accessToken
+	c.tokenMutex.Unlock()
+	if token == nil {
+		return
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok || exp == 0 {
+		ok = false
+		return
+	}
+	expiry := time.Unix(int64(exp), 0)
+	renewal := expiry.Add(-1 * time.Minute)
+	iat, ok := claims["iat"].(float64)
+	if ok {
+		issued := time.Unix(int64(iat), 0)
+		lifetime := expiry.Sub(issued)
+		renewal = issued.Add(time.Duration(c.tokenRenewalFraction * float64(lifetime)))
+	}
+	delay = renewal.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	ok = true
+	return
+}
@@ -0,0 +1,284 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the concurrent and background renewal of tokens.
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Token renewal", func() {
+	// Server used during the tests:
+	var oidServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the server:
+		oidServer = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		oidServer.Close()
+	})
+
+	It("Sends only one request when multiple goroutines need tokens", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server so that it takes some time to respond, to make sure that
+		// all the goroutines need the tokens while the request is in progress:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyRefreshGrant(refreshToken),
+				func(w http.ResponseWriter, r *http.Request) {
+					time.Sleep(200 * time.Millisecond)
+				},
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			Tokens(refreshToken).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens from multiple goroutines:
+		const count = 10
+		results := make([]string, count)
+		errs := make([]error, count)
+		var wg sync.WaitGroup
+		wg.Add(count)
+		for i := 0; i < count; i++ {
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				results[i], _, errs[i] = connection.Tokens()
+			}(i)
+		}
+		wg.Wait()
+
+		// Check that all the goroutines got the same token, and that only one request was
+		// sent:
+		for i := 0; i < count; i++ {
+			Expect(errs[i]).ToNot(HaveOccurred())
+			Expect(results[i]).To(Equal(accessToken))
+		}
+		Expect(oidServer.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("Retries when the goroutine that sent the request is cancelled", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server so that the first request doesn't finish till it is
+		// cancelled, and the second one succeeds:
+		oidServer.AppendHandlers(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Read the form, as otherwise the server doesn't detect that the
+				// client closed the connection:
+				_ = r.ParseForm()
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+			},
+			ghttp.CombineHandlers(
+				VerifyRefreshGrant(refreshToken),
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			Tokens(refreshToken).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Start a request with a context that will be cancelled:
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		first := make(chan error, 1)
+		go func() {
+			_, _, err := connection.TokensContext(ctx)
+			first <- err
+		}()
+		Eventually(oidServer.ReceivedRequests).Should(HaveLen(1))
+
+		// Start a second request that will wait for the first one, and then cancel the
+		// first one:
+		second := make(chan string, 1)
+		go func() {
+			defer GinkgoRecover()
+			access, _, err := connection.Tokens()
+			Expect(err).ToNot(HaveOccurred())
+			second <- access
+		}()
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+
+		// Check that the first request failed and the second one got the token:
+		Eventually(first).Should(Receive(HaveOccurred()))
+		Eventually(second).Should(Receive(Equal(accessToken)))
+	})
+
+	It("Doesn't wait for a renewal in progress when the connection is closed", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 2*time.Second)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server so that the request doesn't finish till it is cancelled:
+		oidServer.AppendHandlers(
+			func(w http.ResponseWriter, r *http.Request) {
+				// Read the form, as otherwise the server doesn't detect that the
+				// client closed the connection:
+				_ = r.ParseForm()
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+			},
+		)
+
+		// Create the connection and wait till the renewal request has been sent:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			Tokens(accessToken, refreshToken).
+			TokenRenewalFraction(0.5).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(oidServer.ReceivedRequests, 5*time.Second).Should(HaveLen(1))
+
+		// Close the connection and check that it doesn't wait for the request:
+		before := time.Now()
+		err = connection.Close()
+		elapsed := time.Since(before)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(elapsed).To(BeNumerically("<", time.Second))
+	})
+
+	It("Renews the tokens in the background", func() {
+		// Generate the tokens:
+		firstAccess := DefaultToken("Bearer", 2*time.Second)
+		secondAccess := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				VerifyRefreshGrant(refreshToken),
+				RespondWithTokens(secondAccess, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			Tokens(firstAccess, refreshToken).
+			TokenRenewalFraction(0.5).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Wait till the token has been renewed, without requesting it:
+		Eventually(oidServer.ReceivedRequests, 5*time.Second).Should(HaveLen(1))
+		Eventually(func() string {
+			access, _, err := connection.Tokens()
+			Expect(err).ToNot(HaveOccurred())
+			return access
+		}).Should(Equal(secondAccess))
+	})
+
+	It("Doesn't renew tokens in the background by default", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 1*time.Second)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			Tokens(accessToken, refreshToken).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Check that no request is sent:
+		Consistently(oidServer.ReceivedRequests, 2*time.Second).Should(BeEmpty())
+	})
+
+	It("Stops renewing tokens when the connection is closed", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 2*time.Second)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Create and close the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(oidServer.URL()).
+			Tokens(accessToken, refreshToken).
+			TokenRenewalFraction(0.5).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		err = connection.Close()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Check that no request is sent:
+		Consistently(oidServer.ReceivedRequests, 2*time.Second).Should(BeEmpty())
+	})
+
+	It("Rejects invalid renewal fraction", func() {
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Tokens(DefaultToken("Bearer", 5*time.Minute)).
+			TokenRenewalFraction(1.5).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(connection).To(BeNil())
+	})
+})
@@ -0,0 +1,281 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the definition of the token source interface and the implementations of the
+// token sources that are included in the SDK.
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// TokenSource is the interface that must be implemented by objects that provide access tokens to
+// the connection. By default the connection uses the OpenID grants (resource owner password,
+// client credentials and refresh token) to obtain tokens, but that can be replaced using the
+// TokenSource method of the builder.
+//
+// The connection keeps the token returned by the Token method and calls it again only when that
+// token is about to expire, or when it renews tokens in the background. Implementations should
+// still cache the token and return it quickly while it is valid, as the connection may call the
+// method again, for example when the token returned doesn't have an expiration time that the
+// connection can use. Implementations must be safe for concurrent use by multiple goroutines.
+type TokenSource interface {
+	// Token returns the access token and the time when it expires. If the token doesn't expire
+	// the returned time should be the zero value.
+	Token(ctx context.Context) (token string, expiry time.Time, err error)
+}
+
+// staticTokenSource is a token source that always returns the same token.
+type staticTokenSource struct {
+	token  string
+	expiry time.Time
+}
+
+// NewStaticTokenSource creates a token source that always returns the given token. If the token is
+// a JSON web token then its expiration time will be extracted from the `exp` claim.
+func NewStaticTokenSource(token string) TokenSource {
+	return &staticTokenSource{
+		token:  token,
+		expiry: tokenExpiryTime(token),
+	}
+}
+
+// Token is the implementation of the TokenSource interface.
+func (s *staticTokenSource) Token(ctx context.Context) (token string, expiry time.Time, err error) {
+	err = checkExpiry(s.expiry)
+	if err != nil {
+		err = fmt.Errorf("static %v", err)
+		return
+	}
+	token = s.token
+	expiry = s.expiry
+	return
+}
+
+// fileTokenSource is a token source that reads the token from a file, and reads it again when the
+// file changes.
+type fileTokenSource struct {
+	path    string
+	mutex   *sync.Mutex
+	modTime time.Time
+	size    int64
+	token   string
+	expiry  time.Time
+}
+
+// NewFileTokenSource creates a token source that reads the token from the given file. The file is
+// checked every time that a token is requested, and read again if its modification time or size
+// have changed, so it can be rotated by other processes, for example by a sidecar container. Leading
+// and trailing white space is removed from the content of the file.
+func NewFileTokenSource(path string) TokenSource {
+	return &fileTokenSource{
+		path:  path,
+		mutex: &sync.Mutex{},
+	}
+}
+
+// Token is the implementation of the TokenSource interface.
+func (s *fileTokenSource) Token(ctx context.Context) (token string, expiry time.Time, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check if the file has changed since the last time it was read:
+	info, err := os.Stat(s.path)
+	if err != nil {
+		err = fmt.Errorf("can't check token file '%s': %v", s.path, err)
+		return
+	}
+	if s.token != "" && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		err = checkExpiry(s.expiry)
+		if err != nil {
+			err = fmt.Errorf("%v, and token file '%s' hasn't changed", err, s.path)
+			return
+		}
+		token = s.token
+		expiry = s.expiry
+		return
+	}
+
+	// Read the file:
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		err = fmt.Errorf("can't read token file '%s': %v", s.path, err)
+		return
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		err = fmt.Errorf("token file '%s' is empty", s.path)
+		return
+	}
+
+	// Save the token:
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	s.token = text
+	s.expiry = tokenExpiryTime(text)
+	err = checkExpiry(s.expiry)
+	if err != nil {
+		err = fmt.Errorf("%v, token file '%s' should be updated", err, s.path)
+		return
+	}
+	token = s.token
+	expiry = s.expiry
+	return
+}
+
+// execTokenSource is a token source that runs an external command to obtain the token.
+type execTokenSource struct {
+	name   string
+	args   []string
+	mutex  *sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewExecTokenSource creates a token source that runs the given command to obtain the token. This
+// is similar to the exec credential plugins used by `kubectl`: the command should write to the
+// standard output a JSON document like this:
+//
+//	{
+//		"kind": "ExecCredential",
+//		"status": {
+//			"token": "eyJhbGciOiJSUzI1NiIsInR5cCIgOiAiSldUIiwia2lkIiA...",
+//			"expirationTimestamp": "2020-01-10T12:00:00Z"
+//		}
+//	}
+//
+// The expiration timestamp is optional. If it isn't present and the token is a JSON web token
+// then its expiration time will be extracted from the `exp` claim. The token returned by the
+// command is cached, and the command is executed again only when the token expires in less than one
+// minute. If there is no way to determine the expiration time the command is executed only once.
+func NewExecTokenSource(name string, args ...string) TokenSource {
+	return &execTokenSource{
+		name:  name,
+		args:  append([]string(nil), args...),
+		mutex: &sync.Mutex{},
+	}
+}
+
+// execCredential is the type used to read the output of the command executed by the exec token
+// source.
+type execCredential struct {
+	Status *execCredentialStatus `json:"status"`
+}
+
+// execCredentialStatus is the type used to read the status section of the output of the command
+// executed by the exec token source.
+type execCredentialStatus struct {
+	Token               string     `json:"token"`
+	ExpirationTimestamp *time.Time `json:"expirationTimestamp"`
+}
+
+// Token is the implementation of the TokenSource interface.
+func (s *execTokenSource) Token(ctx context.Context) (token string, expiry time.Time, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Return the cached token if it is still valid:
+	if s.token != "" && (s.expiry.IsZero() || time.Until(s.expiry) >= 1*time.Minute) {
+		token = s.token
+		expiry = s.expiry
+		return
+	}
+
+	// Run the command:
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var stdout, stderr bytes.Buffer
+	command := exec.CommandContext(ctx, s.name, s.args...) // #nosec G204
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	err = command.Run()
+	if err != nil {
+		err = fmt.Errorf(
+			"can't run token command '%s': %v: %s",
+			s.name, err, strings.TrimSpace(stderr.String()),
+		)
+		return
+	}
+
+	// Parse the output:
+	var credential execCredential
+	err = json.Unmarshal(stdout.Bytes(), &credential)
+	if err != nil {
+		err = fmt.Errorf("can't parse output of token command '%s': %v", s.name, err)
+		return
+	}
+	if credential.Status == nil || credential.Status.Token == "" {
+		err = fmt.Errorf("output of token command '%s' doesn't contain a token", s.name)
+		return
+	}
+
+	// Save the token:
+	s.token = credential.Status.Token
+	if credential.Status.ExpirationTimestamp != nil {
+		s.expiry = *credential.Status.ExpirationTimestamp
+	} else {
+		s.expiry = tokenExpiryTime(s.token)
+	}
+	err = checkExpiry(s.expiry)
+	if err != nil {
+		err = fmt.Errorf("%v, but token command '%s' returned it", err, s.name)
+		return
+	}
+	token = s.token
+	expiry = s.expiry
+	return
+}
+
+// tokenExpiryTime tries to parse the given text as a JSON web token and returns the time when it
+// expires. If the text can't be parsed or the token doesn't expire it returns the zero time.
+func tokenExpiryTime(text string) time.Time {
+	token, _, err := new(jwt.Parser).ParseUnverified(text, jwt.MapClaims{})
+	if err != nil {
+		return time.Time{}
+	}
+	now := time.Now()
+	expires, left, err := tokenExpiry(token, now)
+	if err != nil || !expires {
+		return time.Time{}
+	}
+	return now.Add(left)
+}
+
+// checkExpiry returns an error if the given expiration time has already passed. The zero time means
+// that the token doesn't expire.
+func checkExpiry(expiry time.Time) error {
+	if expiry.IsZero() {
+		return nil
+	}
+	left := time.Until(expiry)
+	if left <= 0 {
+		return fmt.Errorf("token expired %s ago", -left)
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the token sources.
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Token sources", func() {
+	Describe("Static", func() {
+		It("Returns the token and its expiration time", func() {
+			accessToken := DefaultToken("Bearer", 5*time.Minute)
+			source := NewStaticTokenSource(accessToken)
+			token, expiry, err := source.Token(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal(accessToken))
+			Expect(expiry).To(BeTemporally("~", time.Now().Add(5*time.Minute), 2*time.Second))
+		})
+
+		It("Accepts opaque tokens", func() {
+			source := NewStaticTokenSource("myopaquetoken")
+			token, expiry, err := source.Token(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("myopaquetoken"))
+			Expect(expiry.IsZero()).To(BeTrue())
+		})
+	})
+
+	Describe("File", func() {
+		var tmp string
+
+		BeforeEach(func() {
+			var err error
+			tmp, err = ioutil.TempDir("", "tokens")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			err := os.RemoveAll(tmp)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Reads the token from the file", func() {
+			file := filepath.Join(tmp, "token")
+			err := ioutil.WriteFile(file, []byte("mytoken\n"), 0600)
+			Expect(err).ToNot(HaveOccurred())
+			source := NewFileTokenSource(file)
+			token, _, err := source.Token(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("mytoken"))
+		})
+
+		It("Reads the token again when the file changes", func() {
+			file := filepath.Join(tmp, "token")
+			err := ioutil.WriteFile(file, []byte("firsttoken"), 0600)
+			Expect(err).ToNot(HaveOccurred())
+			source := NewFileTokenSource(file)
+			token, _, err := source.Token(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("firsttoken"))
+			err = ioutil.WriteFile(file, []byte("secondtoken"), 0600)
+			Expect(err).ToNot(HaveOccurred())
+			token, _, err = source.Token(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("secondtoken"))
+		})
+
+		It("Fails if the file doesn't exist", func() {
+			source := NewFileTokenSource(filepath.Join(tmp, "missing"))
+			_, _, err := source.Token(context.Background())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Fails if the file is empty", func() {
+			file := filepath.Join(tmp, "token")
+			err := ioutil.WriteFile(file, []byte("\n"), 0600)
+			Expect(err).ToNot(HaveOccurred())
+			source := NewFileTokenSource(file)
+			_, _, err = source.Token(context.Background())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Exec", func() {
+		It("Returns the token written by the command", func() {
+			output := `{
+				"kind": "ExecCredential",
+				"status": {
+					"token": "mytoken",
+					"expirationTimestamp": "2100-01-01T00:00:00Z"
+				}
+			}`
+			source := NewExecTokenSource("echo", output)
+			token, expiry, err := source.Token(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("mytoken"))
+			Expect(expiry).To(Equal(time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)))
+		})
+
+		It("Takes the expiration time from the token", func() {
+			accessToken := DefaultToken("Bearer", 5*time.Minute)
+			output := fmt.Sprintf(`{"status": {"token": "%s"}}`, accessToken)
+			source := NewExecTokenSource("echo", output)
+			token, expiry, err := source.Token(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal(accessToken))
+			Expect(expiry).To(BeTemporally("~", time.Now().Add(5*time.Minute), 2*time.Second))
+		})
+
+		It("Fails if the command fails", func() {
+			source := NewExecTokenSource("false")
+			_, _, err := source.Token(context.Background())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Fails if the output doesn't contain a token", func() {
+			source := NewExecTokenSource("echo", `{"status": {}}`)
+			_, _, err := source.Token(context.Background())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Connection", func() {
+		var apiServer *ghttp.Server
+
+		BeforeEach(func() {
+			apiServer = ghttp.NewServer()
+		})
+
+		AfterEach(func() {
+			apiServer.Close()
+		})
+
+		It("Can be created with only a token source", func() {
+			connection, err := NewConnectionBuilder().
+				TokenSource(NewStaticTokenSource("mytoken")).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			defer connection.Close()
+		})
+
+		It("Uses the token returned by the source", func() {
+			// Configure the server:
+			apiServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyHeaderKV("Authorization", "Bearer mytoken"),
+					ghttp.RespondWith(http.StatusOK, nil),
+				),
+			)
+
+			// Create the connection:
+			connection, err := NewConnectionBuilder().
+				URL(apiServer.URL()).
+				TokenSource(NewStaticTokenSource("mytoken")).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			defer connection.Close()
+
+			// Send the request:
+			response, err := connection.Get().
+				Path("/mypath").
+				Send()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status()).To(Equal(http.StatusOK))
+		})
+
+		It("Calls the source only when the token is about to expire", func() {
+			// Create the connection:
+			source := &sequenceTokenSource{
+				mutex: &sync.Mutex{},
+				tokens: []string{
+					DefaultToken("Bearer", 5*time.Minute),
+				},
+			}
+			connection, err := NewConnectionBuilder().
+				URL(apiServer.URL()).
+				TokenSource(source).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			defer connection.Close()
+
+			// Get the tokens twice and check that the source was called once:
+			first, _, err := connection.Tokens()
+			Expect(err).ToNot(HaveOccurred())
+			second, _, err := connection.Tokens()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(second).To(Equal(first))
+			Expect(source.calls()).To(Equal(1))
+		})
+
+		It("Renews the token of the source in the background", func() {
+			// Create the connection:
+			firstToken := DefaultToken("Bearer", 2*time.Second)
+			secondToken := DefaultToken("Bearer", 5*time.Minute)
+			source := &sequenceTokenSource{
+				mutex: &sync.Mutex{},
+				tokens: []string{
+					firstToken,
+					secondToken,
+				},
+			}
+			connection, err := NewConnectionBuilder().
+				URL(apiServer.URL()).
+				TokenSource(source).
+				TokenRenewalFraction(0.5).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			defer connection.Close()
+
+			// Get the first token, and then wait till it is renewed:
+			access, _, err := connection.Tokens()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(access).To(Equal(firstToken))
+			Eventually(source.calls, 5*time.Second).Should(Equal(2))
+			access, _, err = connection.Tokens()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(access).To(Equal(secondToken))
+		})
+
+		It("Fails if the token returned by the source is expired", func() {
+			accessToken := DefaultToken("Bearer", -5*time.Minute)
+			connection, err := NewConnectionBuilder().
+				URL(apiServer.URL()).
+				TokenSource(NewStaticTokenSource(accessToken)).
+				Build()
+			Expect(err).ToNot(HaveOccurred())
+			defer connection.Close()
+			_, _, err = connection.Tokens()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
+
+// sequenceTokenSource is a token source that returns the given tokens in order, repeating the last
+// one, and counts how many times it has been called.
+type sequenceTokenSource struct {
+	mutex  *sync.Mutex
+	tokens []string
+	count  int
+}
+
+func (s *sequenceTokenSource) Token(ctx context.Context) (token string, expiry time.Time,
+	err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	index := s.count
+	if index >= len(s.tokens) {
+		index = len(s.tokens) - 1
+	}
+	s.count++
+	token = s.tokens[index]
+	expiry = tokenExpiryTime(token)
+	return
+}
+
+func (s *sequenceTokenSource) calls() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.count
+}
@@ -0,0 +1,241 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the logic used to verify the signatures and claims of the access tokens
+// obtained by the connection, using the JSON web key set published by the issuer.
+
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+
+	"github.com/openshift-online/ocm-sdk-go/internal"
+)
+
+// tokenKeysReloadInterval is the minimum time between two consecutive reloads of the keys
+// triggered by tokens signed with unknown keys.
+var tokenKeysReloadInterval = 1 * time.Minute
+
+// tokenVerifier verifies access tokens using the keys published by the issuer.
+type tokenVerifier struct {
+	logger     Logger
+	client     *http.Client
+	issuer     string
+	audience   string
+	mutex      *sync.Mutex
+	keysURL    string
+	keys       map[string]*internal.Key
+	lastReload time.Time
+}
+
+// newTokenVerifier creates a verifier that accepts tokens issued by the given issuer for the given
+// audience.
+func newTokenVerifier(logger Logger, client *http.Client, issuer,
+	audience string) *tokenVerifier {
+	return &tokenVerifier{
+		logger:   logger,
+		client:   client,
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		audience: audience,
+		mutex:    &sync.Mutex{},
+		keys:     map[string]*internal.Key{},
+	}
+}
+
+// verify checks the signature of the given access token, and that its issuer, audience and
+// expiration time are acceptable. If expiry is false expired tokens will be accepted, this is
+// intended for tokens that were explicitly given to the connection, as they will be replaced
+// anyhow.
+func (v *tokenVerifier) verify(ctx context.Context, token *jwt.Token, expiry bool) error {
+	// Check the signature. Note that we don't use the claims validation provided by the
+	// parser, because we want to control which claims are checked and the error messages.
+	parser := &jwt.Parser{
+		SkipClaimsValidation: true,
+	}
+	_, err := parser.Parse(token.Raw, func(token *jwt.Token) (interface{}, error) {
+		return v.selectKey(ctx, token)
+	})
+	if err != nil {
+		return fmt.Errorf("signature isn't valid: %v", err)
+	}
+
+	// Check the claims:
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return fmt.Errorf("expected map claims but got %T", token.Claims)
+	}
+	iss, ok := claims["iss"].(string)
+	if !ok {
+		return fmt.Errorf("token doesn't contain the 'iss' claim")
+	}
+	if strings.TrimSuffix(iss, "/") != v.issuer {
+		return fmt.Errorf("issuer '%s' doesn't match expected '%s'", iss, v.issuer)
+	}
+	if !v.checkAudience(claims) {
+		return fmt.Errorf("token isn't intended for client '%s'", v.audience)
+	}
+	if expiry {
+		expires, left, err := tokenExpiry(token, time.Now())
+		if err != nil {
+			return err
+		}
+		if expires && left <= 0 {
+			return fmt.Errorf("token expired %s ago", -left)
+		}
+	}
+
+	return nil
+}
+
+// checkAudience checks that the token is intended for the client. SSO servers put the client
+// identifier in the `azp` claim of access tokens, and the `aud` claim usually contains the
+// services that the token can be used with, so the `aud` claim is only checked when there is
+// no `azp` claim. Tokens that contain neither are rejected, as there is no way to know who they
+// were issued for.
+func (v *tokenVerifier) checkAudience(claims jwt.MapClaims) bool {
+	if v.audience == "" {
+		return true
+	}
+	if azp, ok := claims["azp"].(string); ok {
+		return azp == v.audience
+	}
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == v.audience
+	case []interface{}:
+		for _, item := range aud {
+			if item == v.audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selectKey selects the key that should be used to verify the given token, loading the keys from
+// the issuer if needed.
+func (v *tokenVerifier) selectKey(ctx context.Context, token *jwt.Token) (result interface{},
+	err error) {
+	// Get the key identifier:
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		err = fmt.Errorf("token doesn't have a 'kid' field in the header")
+		return
+	}
+
+	// Get the key for that key identifier. If there is no such key and we didn't reload keys
+	// recently then we try to reload them now.
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	key, ok := v.keys[kid]
+	if !ok && time.Since(v.lastReload) > tokenKeysReloadInterval {
+		err = v.loadKeys(ctx)
+		if err != nil {
+			return
+		}
+		v.lastReload = time.Now()
+		key, ok = v.keys[kid]
+	}
+	if !ok {
+		err = fmt.Errorf("there is no key for key identifier '%s'", kid)
+		return
+	}
+
+	// Check that the key can be used with this token:
+	err = key.Check(token)
+	if err != nil {
+		return
+	}
+	result = key.Public
+
+	return
+}
+
+// loadKeys loads the JSON web key set of the issuer, discovering its URL first if needed.
+func (v *tokenVerifier) loadKeys(ctx context.Context) error {
+	// Discover the URL of the keys:
+	if v.keysURL == "" {
+		document, err := internal.Discover(ctx, v.client, v.issuer)
+		if err != nil {
+			return err
+		}
+		if document.JWKSURI == "" {
+			return fmt.Errorf("OpenID configuration doesn't contain the keys URL")
+		}
+		v.keysURL = document.JWKSURI
+	}
+
+	// Load the keys:
+	v.logger.Debug(ctx, "Loading keys from '%s'", v.keysURL)
+	var set internal.KeySetData
+	err := v.get(ctx, v.keysURL, &set)
+	if err != nil {
+		return fmt.Errorf("can't load keys: %v", err)
+	}
+	for _, data := range set.Keys {
+		err = internal.CheckKey(data)
+		if err == nil {
+			var key *internal.Key
+			key, err = internal.ParseKey(data)
+			if err == nil {
+				v.keys[data.Kid] = key
+				continue
+			}
+		}
+		v.logger.Warn(ctx, "Key '%s' will be ignored: %v", data.Kid, err)
+	}
+
+	return nil
+}
+
+// get sends a GET request to the given URL and parses the JSON response into the given value.
+func (v *tokenVerifier) get(ctx context.Context, addr string, value interface{}) error {
+	request, err := http.NewRequest(http.MethodGet, addr, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	request = request.WithContext(ctx)
+	response, err := v.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("response status is: %s", response.Status)
+	}
+	return json.Unmarshal(body, value)
+}
+
+// defaultIssuer calculates the default issuer from the token URL, using the conventions of
+// Keycloak. For example, if the token URL is `.../auth/realms/myrealm/protocol/openid-connect/token`
+// then the issuer will be `.../auth/realms/myrealm`.
+func defaultIssuer(tokenURL string) string {
+	return strings.TrimSuffix(tokenURL, "/protocol/openid-connect/token")
+}
@@ -0,0 +1,428 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the verification of tokens.
+
+package sdk
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Token verification", func() {
+	// Server used during the tests:
+	var oidServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Issuer and token URL of the server:
+	var issuer string
+	var tokenURL string
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the server:
+		oidServer = ghttp.NewServer()
+		oidServer.SetAllowUnhandledRequests(true)
+		issuer = oidServer.URL() + "/auth/realms/myrealm"
+		tokenURL = issuer + "/protocol/openid-connect/token"
+		oidServer.RouteToHandler(
+			http.MethodGet,
+			"/auth/realms/myrealm/.well-known/openid-configuration",
+			RespondWithJSONTemplate(
+				http.StatusOK,
+				`{
+					"issuer": "{{ .Issuer }}",
+					"jwks_uri": "{{ .Issuer }}/protocol/openid-connect/certs"
+				}`,
+				"Issuer", issuer,
+			),
+		)
+		oidServer.RouteToHandler(
+			http.MethodGet,
+			"/auth/realms/myrealm/protocol/openid-connect/certs",
+			RespondWithJSON(http.StatusOK, jwksJSON(jwtPublicKey)),
+		)
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		oidServer.Close()
+	})
+
+	// respondWithTokens configures the server so that it returns the given tokens from the
+	// token endpoint:
+	respondWithTokens := func(accessToken, refreshToken string) {
+		oidServer.RouteToHandler(
+			http.MethodPost,
+			"/auth/realms/myrealm/protocol/openid-connect/token",
+			RespondWithTokens(accessToken, refreshToken),
+		)
+	}
+
+	It("Accepts valid token", func() {
+		// Generate the tokens:
+		accessToken := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": DefaultClientID,
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+		respondWithTokens(accessToken, refreshToken)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+	})
+
+	It("Rejects token signed with unknown key", func() {
+		// Generate the tokens:
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		accessToken := issueSignedToken(otherKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": DefaultClientID,
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+		respondWithTokens(accessToken, refreshToken)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("signature"))
+	})
+
+	It("Doesn't keep the refresh token if the access token is rejected", func() {
+		// Generate the tokens:
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		accessToken := issueSignedToken(otherKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": DefaultClientID,
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+
+		// Configure the server so that it remembers the grants used:
+		var grants []string
+		oidServer.RouteToHandler(
+			http.MethodPost,
+			"/auth/realms/myrealm/protocol/openid-connect/token",
+			ghttp.CombineHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					grants = append(grants, r.FormValue("grant_type"))
+				},
+				RespondWithTokens(accessToken, refreshToken),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Try to get the tokens twice. The second attempt should use the password again,
+		// because the refresh token returned with the rejected access token shouldn't be
+		// used:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(grants).To(Equal([]string{"password", "password"}))
+	})
+
+	It("Rejects token with wrong issuer", func() {
+		// Generate the tokens:
+		accessToken := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"iss": "https://other.example.com",
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+		respondWithTokens(accessToken, refreshToken)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("issuer"))
+	})
+
+	It("Rejects token for other client", func() {
+		// Generate the tokens:
+		accessToken := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": "otherclient",
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+		respondWithTokens(accessToken, refreshToken)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("isn't intended for client"))
+	})
+
+	It("Rejects token without audience", func() {
+		// Generate the tokens:
+		accessToken := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"iss": issuer,
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+		respondWithTokens(accessToken, refreshToken)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("isn't intended for client"))
+	})
+
+	It("Rejects expired token returned by the server", func() {
+		// Generate the tokens:
+		accessToken := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": DefaultClientID,
+			"exp": time.Now().Add(-5 * time.Minute).Unix(),
+		})
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+		respondWithTokens(accessToken, refreshToken)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("expired"))
+	})
+
+	It("Fails to build if the given token isn't valid", func() {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		accessToken := issueSignedToken(otherKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": DefaultClientID,
+			"typ": "Bearer",
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			Tokens(accessToken).
+			VerifyTokens(true).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("can't verify access token"))
+		Expect(connection).To(BeNil())
+	})
+
+	It("Accepts expired token given to the builder", func() {
+		accessToken := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": DefaultClientID,
+			"typ": "Bearer",
+			"exp": time.Now().Add(-5 * time.Minute).Unix(),
+		})
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			Tokens(accessToken).
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+	})
+
+	It("Doesn't verify tokens by default", func() {
+		// Generate the tokens:
+		accessToken := DefaultToken("Bearer", 5*time.Minute)
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+		respondWithTokens(accessToken, refreshToken)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+	})
+
+	It("Ignores cached access token that isn't valid", func() {
+		// Save to the cache an access token signed with a key that isn't published by the
+		// issuer:
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		forgedToken := issueSignedToken(otherKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": DefaultClientID,
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		tmp, err := ioutil.TempDir("", "cache")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmp)
+		path := filepath.Join(tmp, "tokens.json")
+		cache := newTokenCache(path, tokenURL, DefaultClientID, "myuser", DefaultScopes, "")
+		err = cache.save(forgedToken, "")
+		Expect(err).ToNot(HaveOccurred())
+
+		// Generate the tokens that the server will return:
+		accessToken := issueSignedToken(jwtPrivateKey, jwt.MapClaims{
+			"iss": issuer,
+			"azp": DefaultClientID,
+			"exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		refreshToken := DefaultToken("Refresh", 10*time.Hour)
+		respondWithTokens(accessToken, refreshToken)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			TokenURL(tokenURL).
+			User("myuser", "mypassword").
+			TokenCache(path).
+			VerifyTokens(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Get the tokens and check that the cached one wasn't used:
+		returnedAccess, _, err := connection.Tokens()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(returnedAccess).To(Equal(accessToken))
+	})
+})
+
+// issueSignedToken generates a token with the given claims, signed with the given key and with
+// the key identifier used by the jwksJSON function.
+func issueSignedToken(key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "123"
+	result, err := token.SignedString(key)
+	Expect(err).ToNot(HaveOccurred())
+	return result
+}
+
+// jwksJSON generates a JSON web key set containing the given public key.
+func jwksJSON(key *rsa.PublicKey) string {
+	return fmt.Sprintf(
+		`{
+			"keys": [{
+				"kid": "123",
+				"kty": "RSA",
+				"alg": "RS256",
+				"e": "%s",
+				"n": "%s"
+			}]
+		}`,
+		base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+	)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the OpenTelemetry tracing of the requests sent by the
+// connection.
+
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/semconv"
+
+	"github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+// Names of the attributes added to spans, in addition to the standard HTTP ones:
+const (
+	tracingAttemptKey   = label.Key("ocm.attempt")
+	tracingErrorCodeKey = label.Key("ocm.error_code")
+	tracingGrantKey     = label.Key("ocm.grant_type")
+)
+
+// Name of the span used for token requests:
+const tracingTokenSpan = "token"
+
+// Maximum number of bytes of the body of an error response that will be read in order to add the
+// error code to the span:
+const tracingBodyLimit = 64 * 1024
+
+// startCallSpan starts the span for one attempt to send an API request. The name of the span is
+// the anonymized path used for metrics.
+func (c *Connection) startCallSpan(ctx context.Context, request *http.Request, metric string,
+	attempt int) (context.Context, trace.Span) {
+	return c.tracer.Start(
+		ctx,
+		metric,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(request.Method),
+			tracingAttemptKey.Int(attempt),
+		),
+	)
+}
+
+// endCallSpan adds to the span the results of one attempt to send an API request and ends it. If
+// the response contains an error the code of that error is also added, reading the body and
+// replacing it with a copy stored in memory. Bodies larger than tracingBodyLimit aren't copied
+// and the error code isn't added. It returns the given response and error, unless reading the body
+// fails, in that case the body is closed and the read error is returned instead.
+func (c *Connection) endCallSpan(ctx context.Context, span trace.Span, response *http.Response,
+	err error) (*http.Response, error) {
+	defer span.End()
+	if err != nil {
+		span.RecordError(ctx, err, trace.WithErrorStatus(codes.Error))
+		return response, err
+	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(response.StatusCode))
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(response.StatusCode))
+	if response.StatusCode < http.StatusBadRequest || response.Body == nil ||
+		!span.IsRecording() {
+		return response, nil
+	}
+	original := response.Body
+	body, err := ioutil.ReadAll(io.LimitReader(original, tracingBodyLimit))
+	if err != nil {
+		err = fmt.Errorf("can't read response body: %v", err)
+		span.RecordError(ctx, err, trace.WithErrorStatus(codes.Error))
+		_ = original.Close()
+		return nil, err
+	}
+	if len(body) >= tracingBodyLimit {
+		response.Body = &prefixedBody{
+			Reader: io.MultiReader(bytes.NewReader(body), original),
+			Closer: original,
+		}
+		return response, nil
+	}
+	_ = original.Close()
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	apiErr, err := errors.UnmarshalError(body)
+	if err == nil && apiErr.Code() != "" {
+		span.SetAttributes(tracingErrorCodeKey.String(apiErr.Code()))
+	}
+	return response, nil
+}
+
+// prefixedBody is the response body used when the body is too large to be copied to memory. It
+// returns the part that has already been read followed by the rest of the original body, and
+// closes the original body.
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// startTokenSpan starts the span for a token request.
+func (c *Connection) startTokenSpan(ctx context.Context, grant string) (context.Context,
+	trace.Span) {
+	return c.tracer.Start(
+		ctx,
+		tracingTokenSpan,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(http.MethodPost),
+			tracingGrantKey.String(grant),
+		),
+	)
+}
+
+// endTokenSpan adds to the span the results of a token request and ends it.
+func (c *Connection) endTokenSpan(ctx context.Context, span trace.Span, code int, err error) {
+	defer span.End()
+	if code != 0 {
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(code))
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCode(code))
+	}
+	if err != nil {
+		tokenErr, ok := err.(*tokenError)
+		if ok && tokenErr.code != "" {
+			span.SetAttributes(tracingErrorCodeKey.String(tokenErr.code))
+		}
+		span.RecordError(ctx, err, trace.WithErrorStatus(codes.Error))
+	}
+}
@@ -0,0 +1,426 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the OpenTelemetry tracing of requests.
+
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+	"go.opentelemetry.io/otel/api/global"
+	"go.opentelemetry.io/otel/api/trace"
+	"go.opentelemetry.io/otel/api/trace/tracetest"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/label"
+	"go.opentelemetry.io/otel/propagators"
+
+	"github.com/openshift-online/ocm-sdk-go/clustersmgmt"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/internal"
+)
+
+// tracingServer is a server that only implements the version 1 of the clusters management service,
+// and that doesn't have any resource. It is used to test the spans created by the generated
+// servers.
+type tracingServer struct {
+	Server
+}
+
+func (s *tracingServer) ClustersMgmt() clustersmgmt.Server {
+	return &tracingClustersMgmtServer{}
+}
+
+type tracingClustersMgmtServer struct {
+}
+
+func (s *tracingClustersMgmtServer) V1() cmv1.Server {
+	return &tracingClustersMgmtV1Server{}
+}
+
+type tracingClustersMgmtV1Server struct {
+	cmv1.Server
+}
+
+func (s *tracingClustersMgmtV1Server) Clusters() cmv1.ClustersServer {
+	return nil
+}
+
+var _ = Describe("Tracing", func() {
+	// Servers used during the tests:
+	var oidServer *ghttp.Server
+	var apiServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Recorder that collects the spans, and provider that uses it:
+	var recorder *tracetest.StandardSpanRecorder
+	var provider trace.TracerProvider
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the servers:
+		oidServer = ghttp.NewServer()
+		apiServer = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Create the tracer provider:
+		recorder = &tracetest.StandardSpanRecorder{}
+		provider = tracetest.NewTracerProvider(tracetest.WithSpanRecorder(recorder))
+	})
+
+	AfterEach(func() {
+		// Stop the servers:
+		oidServer.Close()
+		apiServer.Close()
+	})
+
+	// attribute returns the value of the given attribute of the span, as a string:
+	attribute := func(span *tracetest.Span, key label.Key) string {
+		value, ok := span.Attributes()[key]
+		if !ok {
+			return ""
+		}
+		return value.Emit()
+	}
+
+	It("Creates a span for each API request and propagates it", func() {
+		// Configure the server so that it saves the trace header:
+		var traceparent string
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					traceparent = r.Header.Get("traceparent")
+				},
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{"kind": "Cluster", "id": "123"}`,
+					http.Header{
+						"Content-Type": []string{"application/json"},
+					},
+				),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(DefaultToken("Bearer", 5*time.Minute)).
+			TracerProvider(provider).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		_, err = connection.ClustersMgmt().V1().Clusters().Cluster("123").Get().Send()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Check the span:
+		spans := recorder.Completed()
+		Expect(spans).To(HaveLen(1))
+		span := spans[0]
+		Expect(span.Name()).To(Equal("/api/clusters_mgmt/v1/clusters/-"))
+		Expect(span.SpanKind()).To(Equal(trace.SpanKindClient))
+		Expect(attribute(span, "http.method")).To(Equal(http.MethodGet))
+		Expect(attribute(span, "http.status_code")).To(Equal("200"))
+		Expect(attribute(span, "ocm.attempt")).To(Equal("1"))
+
+		// Check that the trace context was sent to the server:
+		Expect(traceparent).To(ContainSubstring(span.SpanContext().TraceID.String()))
+		Expect(traceparent).To(ContainSubstring(span.SpanContext().SpanID.String()))
+	})
+
+	It("Adds the error code to the span", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(
+				http.StatusNotFound,
+				`{
+					"kind": "Error",
+					"id": "404",
+					"code": "CLUSTERS-MGMT-404",
+					"reason": "Cluster '123' not found"
+				}`,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(DefaultToken("Bearer", 5*time.Minute)).
+			TracerProvider(provider).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request, and check that the error is still returned to the caller:
+		response, err := connection.ClustersMgmt().V1().Clusters().Cluster("123").Get().Send()
+		Expect(err).To(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusNotFound))
+		Expect(response.Error().Code()).To(Equal("CLUSTERS-MGMT-404"))
+
+		// Check the span:
+		spans := recorder.Completed()
+		Expect(spans).To(HaveLen(1))
+		span := spans[0]
+		Expect(attribute(span, "http.status_code")).To(Equal("404"))
+		Expect(attribute(span, "ocm.error_code")).To(Equal("CLUSTERS-MGMT-404"))
+		Expect(span.StatusCode()).To(Equal(codes.Error))
+	})
+
+	It("Doesn't truncate large error responses", func() {
+		// Configure the server with an error response larger than the limit:
+		reason := strings.Repeat("x", 2*tracingBodyLimit)
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(
+				http.StatusNotFound,
+				`{
+					"kind": "Error",
+					"id": "404",
+					"code": "CLUSTERS-MGMT-404",
+					"reason": "`+reason+`"
+				}`,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(DefaultToken("Bearer", 5*time.Minute)).
+			TracerProvider(provider).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request, and check that the complete error is returned to the caller:
+		response, err := connection.ClustersMgmt().V1().Clusters().Cluster("123").Get().Send()
+		Expect(err).To(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusNotFound))
+		Expect(response.Error().Reason()).To(Equal(reason))
+
+		// Check that the span doesn't contain the error code, as the body wasn't read:
+		spans := recorder.Completed()
+		Expect(spans).To(HaveLen(1))
+		Expect(attribute(spans[0], "http.status_code")).To(Equal("404"))
+		Expect(attribute(spans[0], "ocm.error_code")).To(BeEmpty())
+	})
+
+	It("Creates a span for each attempt", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusServiceUnavailable, "{}"),
+			ghttp.RespondWith(http.StatusOK, "{}"),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(DefaultToken("Bearer", 5*time.Minute)).
+			TracerProvider(provider).
+			RetryLimit(2).
+			RetryInterval(10 * time.Millisecond).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusOK))
+
+		// Check the spans:
+		spans := recorder.Completed()
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0].Name()).To(Equal("/-"))
+		Expect(attribute(spans[0], "ocm.attempt")).To(Equal("1"))
+		Expect(attribute(spans[0], "http.status_code")).To(Equal("503"))
+		Expect(attribute(spans[1], "ocm.attempt")).To(Equal("2"))
+		Expect(attribute(spans[1], "http.status_code")).To(Equal("200"))
+	})
+
+	It("Creates a span for token requests", func() {
+		// Configure the servers:
+		var traceparent string
+		oidServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					traceparent = r.Header.Get("traceparent")
+				},
+				RespondWithTokens(
+					DefaultToken("Bearer", 5*time.Minute),
+					DefaultToken("Refresh", 10*time.Hour),
+				),
+			),
+		)
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, "{}"),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			TokenURL(oidServer.URL()).
+			Client("myclient", "mysecret").
+			TracerProvider(provider).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		_, err = connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Check the spans:
+		var token *tracetest.Span
+		for _, span := range recorder.Completed() {
+			if span.Name() == "token" {
+				token = span
+			}
+		}
+		Expect(token).ToNot(BeNil())
+		Expect(attribute(token, "http.method")).To(Equal(http.MethodPost))
+		Expect(attribute(token, "http.status_code")).To(Equal("200"))
+		Expect(attribute(token, "ocm.grant_type")).To(Equal("client_credentials"))
+		Expect(traceparent).To(ContainSubstring(token.SpanContext().SpanID.String()))
+	})
+
+	It("Adds the OAuth error code to the token span", func() {
+		// Configure the server:
+		oidServer.AppendHandlers(
+			ghttp.RespondWith(
+				http.StatusUnauthorized,
+				`{
+					"error": "invalid_client",
+					"error_description": "Invalid client credentials"
+				}`,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			TokenURL(oidServer.URL()).
+			Client("myclient", "mysecret").
+			TracerProvider(provider).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Request the tokens:
+		_, _, err = connection.Tokens()
+		Expect(err).To(HaveOccurred())
+
+		// Check the span:
+		spans := recorder.Completed()
+		Expect(spans).ToNot(BeEmpty())
+		span := spans[0]
+		Expect(span.Name()).To(Equal("token"))
+		Expect(attribute(span, "http.status_code")).To(Equal("401"))
+		Expect(attribute(span, "ocm.error_code")).To(Equal("invalid_client"))
+		Expect(span.StatusCode()).To(Equal(codes.Error))
+	})
+
+	It("Lets server handlers hijack the connection", func() {
+		// Create a server that hijacks the connection inside the span:
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w, _, end := internal.StartServerSpan(
+					provider.Tracer("test"), propagators.TraceContext{}, w, r, "test",
+				)
+				defer end()
+				hijacker, ok := w.(http.Hijacker)
+				Expect(ok).To(BeTrue())
+				conn, buffer, err := hijacker.Hijack()
+				Expect(err).ToNot(HaveOccurred())
+				defer conn.Close()
+				_, err = buffer.WriteString("HTTP/1.1 204 No Content\r\n\r\n")
+				Expect(err).ToNot(HaveOccurred())
+				err = buffer.Flush()
+				Expect(err).ToNot(HaveOccurred())
+			},
+		))
+		defer server.Close()
+
+		// Send the request:
+		response, err := http.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		Expect(response.StatusCode).To(Equal(http.StatusNoContent))
+	})
+
+	It("Creates a span in the generated servers", func() {
+		// Use the tracer provider as the global one, as that is what the generated servers
+		// use:
+		global.SetTracerProvider(provider)
+		defer global.SetTracerProvider(trace.NoopTracerProvider())
+
+		// Create a client span, so that we can check that the server span is its child:
+		ctx, client := provider.Tracer("test").Start(context.Background(), "client")
+		client.End()
+
+		// Send the request to the adapter, including the trace context in the headers:
+		request := httptest.NewRequest(http.MethodGet, "/clusters_mgmt/v1/clusters", nil)
+		propagators.TraceContext{}.Inject(ctx, request.Header)
+		response := httptest.NewRecorder()
+		NewAdapter(&tracingServer{}, logger).ServeHTTP(response, request)
+		Expect(response.Code).To(Equal(http.StatusNotFound))
+
+		// Check the span:
+		var server *tracetest.Span
+		for _, span := range recorder.Completed() {
+			if span.Name() == "/api/clusters_mgmt/v1" {
+				server = span
+			}
+		}
+		Expect(server).ToNot(BeNil())
+		Expect(server.SpanKind()).To(Equal(trace.SpanKindServer))
+		Expect(server.SpanContext().TraceID).To(Equal(client.SpanContext().TraceID))
+		Expect(server.ParentSpanID()).To(Equal(client.SpanContext().SpanID))
+		Expect(attribute(server, "http.target")).To(Equal("/clusters_mgmt/v1/clusters"))
+		Expect(attribute(server, "http.status_code")).To(Equal("404"))
+	})
+})
@@ -0,0 +1,190 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the logic that creates the HTTP transport used by the connection.
+
+package sdk
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Keep alive period used for the TCP connections:
+const transportKeepAlive = 30 * time.Second
+
+// TransportWrapper is the type of the functions that can be used to wrap the HTTP round tripper
+// used by the connection, for example to add headers or to audit requests. The function receives
+// the round tripper that sends the request, and returns a new round tripper that should eventually
+// call it.
+type TransportWrapper func(http.RoundTripper) http.RoundTripper
+
+// MetricPathFromContext returns the anonymized path of the request that is used to report metrics,
+// for example `/api/clusters_mgmt/v1/clusters/-`. This is intended for transport wrappers, that can
+// get it from the context of the request. It returns an empty string for requests that aren't API
+// requests, like the requests sent to obtain tokens.
+func MetricPathFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	path, _ := ctx.Value(metricPathKeyValue).(string)
+	return path
+}
+
+// contextWithMetricPath creates a new context containing the given anonymized path.
+func contextWithMetricPath(parent context.Context, path string) context.Context {
+	return context.WithValue(parent, metricPathKeyValue, path)
+}
+
+// createTransport checks the transport options stored in the builder and creates the HTTP round
+// tripper that will be used by the connection, wrapped with the configured transport wrappers. It
+// also returns the base round tripper, without the wrappers, so that the connection can close its
+// idle connections even if the wrappers don't support that. The returned base round tripper is nil
+// when it has been provided by the caller, as it may be shared with other connections and then
+// the connection shouldn't close its idle connections.
+func (b *ConnectionBuilder) createTransport() (base, result http.RoundTripper, err error) {
+	result, err = b.createBaseTransport()
+	if err != nil {
+		return
+	}
+	if b.transport == nil {
+		base = result
+	}
+
+	// Apply the wrappers in reverse order, so that the first one added will be the first one
+	// to receive the requests:
+	for i := len(b.transportWrappers) - 1; i >= 0; i-- {
+		result = b.transportWrappers[i](result)
+	}
+
+	return
+}
+
+// createBaseTransport creates the HTTP round tripper that sends the requests to the network, or
+// returns the custom one if it was provided.
+func (b *ConnectionBuilder) createBaseTransport() (result http.RoundTripper, err error) {
+	// Check the parameters:
+	if b.timeout < 0 {
+		err = fmt.Errorf("timeout %s isn't valid, it must be zero or positive", b.timeout)
+		return
+	}
+	if b.dialTimeout < 0 {
+		err = fmt.Errorf(
+			"dial timeout %s isn't valid, it must be zero or positive",
+			b.dialTimeout,
+		)
+		return
+	}
+	if b.tlsHandshakeTimeout < 0 {
+		err = fmt.Errorf(
+			"TLS handshake timeout %s isn't valid, it must be zero or positive",
+			b.tlsHandshakeTimeout,
+		)
+		return
+	}
+	if b.responseHeaderTimeout < 0 {
+		err = fmt.Errorf(
+			"response header timeout %s isn't valid, it must be zero or positive",
+			b.responseHeaderTimeout,
+		)
+		return
+	}
+	if b.idleConnTimeout < 0 {
+		err = fmt.Errorf(
+			"idle connection timeout %s isn't valid, it must be zero or positive",
+			b.idleConnTimeout,
+		)
+		return
+	}
+	if b.maxIdleConnsPerHost < 0 {
+		err = fmt.Errorf(
+			"maximum idle connections per host %d isn't valid, it must be zero or positive",
+			b.maxIdleConnsPerHost,
+		)
+		return
+	}
+	if (b.clientCertFile == "") != (b.clientKeyFile == "") {
+		err = fmt.Errorf("both the client certificate and key files must be provided")
+		return
+	}
+
+	// If a custom transport has been provided then use it:
+	if b.transport != nil {
+		result = b.transport
+		return
+	}
+
+	// Prepare the TLS configuration:
+	// #nosec 402
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: b.insecure,
+		RootCAs:            b.trustedCAs,
+	}
+	if b.clientCertFile != "" {
+		var certificate tls.Certificate
+		certificate, err = tls.LoadX509KeyPair(b.clientCertFile, b.clientKeyFile)
+		if err != nil {
+			err = fmt.Errorf(
+				"can't load client certificate from files '%s' and '%s': %v",
+				b.clientCertFile, b.clientKeyFile, err,
+			)
+			return
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	// Prepare the proxy function:
+	var proxy func(*http.Request) (*url.URL, error)
+	if b.proxyURL != "" {
+		var proxyURL *url.URL
+		proxyURL, err = url.Parse(b.proxyURL)
+		if err != nil {
+			err = fmt.Errorf("proxy URL '%s' isn't valid: %v", b.proxyURL, err)
+			return
+		}
+		proxy = http.ProxyURL(proxyURL)
+	} else if b.proxyFromEnvironment {
+		proxy = http.ProxyFromEnvironment
+	}
+
+	// Create the transport:
+	dialer := &net.Dialer{
+		Timeout:   b.dialTimeout,
+		KeepAlive: transportKeepAlive,
+	}
+	result = &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   b.tlsHandshakeTimeout,
+		ResponseHeaderTimeout: b.responseHeaderTimeout,
+		IdleConnTimeout:       b.idleConnTimeout,
+		MaxIdleConnsPerHost:   b.maxIdleConnsPerHost,
+	}
+
+	return
+}
+
+// metricPathKeyType is the type of the key used to store the metric path in the context.
+type metricPathKeyType string
+
+// metricPathKeyValue is the key used to store the metric path in the context:
+const metricPathKeyValue metricPathKeyType = "metricPath"
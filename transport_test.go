@@ -0,0 +1,520 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains tests for the configuration of the HTTP transport.
+
+package sdk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo" // nolint
+	. "github.com/onsi/gomega" // nolint
+
+	"github.com/onsi/gomega/ghttp"
+)
+
+// recordingTransport is a round tripper that records the requests that it sends and if idle
+// connections have been closed.
+type recordingTransport struct {
+	next     http.RoundTripper
+	requests int
+	closed   bool
+}
+
+func (t *recordingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.requests++
+	return t.next.RoundTrip(request)
+}
+
+func (t *recordingTransport) CloseIdleConnections() {
+	t.closed = true
+}
+
+var _ = Describe("Transport", func() {
+	// Server used during the tests:
+	var apiServer *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	// Access token used during the tests:
+	var accessToken string
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the token:
+		accessToken = DefaultToken("Bearer", 5*time.Minute)
+
+		// Create the API server:
+		apiServer = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		apiServer.Close()
+	})
+
+	It("Sends requests through the proxy", func() {
+		// Configure the proxy, which receives the complete URL of the target server:
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(http.MethodGet, "/mypath"),
+				func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.URL.Host).To(Equal("api.example.com"))
+				},
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL("http://api.example.com").
+			Tokens(accessToken).
+			ProxyURL(apiServer.URL()).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusOK))
+	})
+
+	It("Uses the custom transport and doesn't close its idle connections", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, "{}"),
+		)
+
+		// Create the connection:
+		transport := &recordingTransport{
+			next: http.DefaultTransport,
+		}
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			Transport(transport).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Send the request:
+		_, err = connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transport.requests).To(Equal(1))
+
+		// Close the connection:
+		err = connection.Close()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transport.closed).To(BeFalse())
+	})
+
+	It("Doesn't close idle connections of the custom transport when it is wrapped", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, "{}"),
+		)
+
+		// Create the connection with a wrapper that doesn't close idle connections:
+		transport := &recordingTransport{
+			next: http.DefaultTransport,
+		}
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			Transport(transport).
+			TransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+				return &wrapperTransport{
+					next:   next,
+					before: func(request *http.Request) {},
+				}
+			}).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Send the request:
+		_, err = connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transport.requests).To(Equal(1))
+
+		// Close the connection:
+		err = connection.Close()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(transport.closed).To(BeFalse())
+	})
+
+	It("Closes idle connections of the transport that it creates", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.RespondWith(http.StatusOK, "{}"),
+		)
+
+		// Create the connection with a wrapper that records if idle connections are closed:
+		var wrapper *recordingTransport
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			TransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+				wrapper = &recordingTransport{
+					next: next,
+				}
+				return wrapper
+			}).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+
+		// Send the request:
+		_, err = connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wrapper.requests).To(Equal(1))
+
+		// Close the connection:
+		err = connection.Close()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wrapper.closed).To(BeTrue())
+	})
+
+	It("Honors the response header timeout", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					time.Sleep(500 * time.Millisecond)
+				},
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			ResponseHeaderTimeout(50 * time.Millisecond).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		_, err = connection.Get().Path("/mypath").Send()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("timeout"))
+	})
+
+	It("Honors the request timeout", func() {
+		// Configure the server:
+		apiServer.AppendHandlers(
+			ghttp.CombineHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					time.Sleep(500 * time.Millisecond)
+				},
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(apiServer.URL()).
+			Tokens(accessToken).
+			Timeout(50 * time.Millisecond).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		_, err = connection.Get().Path("/mypath").Send()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Presents the client certificate", func() {
+		// Create a temporary directory for the certificate files:
+		tmp, err := ioutil.TempDir("", "client-cert-*")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(tmp) // nolint
+
+		// Generate a self signed client certificate:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject: pkix.Name{
+				CommonName: "myclient",
+			},
+			NotBefore:   time.Now().Add(-time.Minute),
+			NotAfter:    time.Now().Add(time.Hour),
+			KeyUsage:    x509.KeyUsageDigitalSignature,
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		Expect(err).ToNot(HaveOccurred())
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		certFile := filepath.Join(tmp, "tls.crt")
+		keyFile := filepath.Join(tmp, "tls.key")
+		err = ioutil.WriteFile(
+			certFile,
+			pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+			0600,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		err = ioutil.WriteFile(
+			keyFile,
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+			0600,
+		)
+		Expect(err).ToNot(HaveOccurred())
+
+		// Create a server that requires a client certificate and saves its name:
+		var name string
+		server := httptest.NewUnstartedServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				name = r.TLS.PeerCertificates[0].Subject.CommonName
+				w.Header().Set("Content-Type", "application/json")
+				_, err := w.Write([]byte("{}"))
+				Expect(err).ToNot(HaveOccurred())
+			},
+		))
+		server.TLS = &tls.Config{
+			ClientAuth: tls.RequireAnyClientCert,
+		}
+		server.StartTLS()
+		defer server.Close()
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(server.URL).
+			Tokens(accessToken).
+			Insecure(true).
+			ClientCertificate(certFile, keyFile).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusOK))
+		Expect(name).To(Equal("myclient"))
+	})
+
+	It("Can't be built with client certificate but without key", func() {
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Tokens(accessToken).
+			ClientCertificate("tls.crt", "").
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("key"))
+		Expect(connection).To(BeNil())
+	})
+
+	It("Can't be built with client certificate that doesn't exist", func() {
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Tokens(accessToken).
+			ClientCertificate("/doesnt/exist/tls.crt", "/doesnt/exist/tls.key").
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("client certificate"))
+		Expect(connection).To(BeNil())
+	})
+
+	It("Can't be built with negative timeout", func() {
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			Tokens(accessToken).
+			DialTimeout(-1 * time.Second).
+			Build()
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("dial timeout"))
+		Expect(connection).To(BeNil())
+	})
+})
+
+// wrapperTransport is a round tripper used to test transport wrappers. It calls a function before
+// passing the request to the next round tripper.
+type wrapperTransport struct {
+	next   http.RoundTripper
+	before func(request *http.Request)
+}
+
+func (t *wrapperTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.before(request)
+	return t.next.RoundTrip(request)
+}
+
+var _ = Describe("Transport wrapper", func() {
+	// Server used during the tests:
+	var server *ghttp.Server
+
+	// Logger used during the tests:
+	var logger Logger
+
+	BeforeEach(func() {
+		var err error
+
+		// Create the server:
+		server = ghttp.NewServer()
+
+		// Create the logger:
+		logger, err = NewStdLoggerBuilder().
+			Streams(GinkgoWriter, GinkgoWriter).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		// Stop the server:
+		server.Close()
+	})
+
+	// wrapper returns a transport wrapper that calls the given function for each request:
+	wrapper := func(before func(request *http.Request)) TransportWrapper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return &wrapperTransport{
+				next:   next,
+				before: before,
+			}
+		}
+	}
+
+	It("Calls wrappers in the order they were added", func() {
+		// Configure the server:
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyHeaderKV("X-Order", "first", "second"),
+				ghttp.RespondWith(http.StatusOK, "{}"),
+			),
+		)
+
+		// Create the connection:
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(server.URL()).
+			Tokens(DefaultToken("Bearer", 5*time.Minute)).
+			TransportWrapper(wrapper(func(request *http.Request) {
+				request.Header.Add("X-Order", "first")
+			})).
+			TransportWrapper(wrapper(func(request *http.Request) {
+				request.Header.Add("X-Order", "second")
+			})).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		response, err := connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Status()).To(Equal(http.StatusOK))
+	})
+
+	It("Gives access to the authorization header and the metric path", func() {
+		// Configure the server:
+		server.AppendHandlers(
+			ghttp.RespondWith(
+				http.StatusOK,
+				`{"kind": "Cluster", "id": "123"}`,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			),
+		)
+
+		// Create the connection:
+		token := DefaultToken("Bearer", 5*time.Minute)
+		var authorization string
+		var metric string
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(server.URL()).
+			Tokens(token).
+			TransportWrapper(wrapper(func(request *http.Request) {
+				authorization = request.Header.Get("Authorization")
+				metric = MetricPathFromContext(request.Context())
+			})).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		_, err = connection.ClustersMgmt().V1().Clusters().Cluster("123").Get().Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(authorization).To(Equal("Bearer " + token))
+		Expect(metric).To(Equal("/api/clusters_mgmt/v1/clusters/-"))
+	})
+
+	It("Wraps token requests", func() {
+		// Configure the server:
+		server.AppendHandlers(
+			RespondWithTokens(
+				DefaultToken("Bearer", 5*time.Minute),
+				DefaultToken("Refresh", 10*time.Hour),
+			),
+			ghttp.RespondWith(http.StatusOK, "{}"),
+		)
+
+		// Create the connection:
+		var paths []string
+		var metrics []string
+		connection, err := NewConnectionBuilder().
+			Logger(logger).
+			URL(server.URL()).
+			TokenURL(server.URL()+"/token").
+			User("myuser", "mypassword").
+			TransportWrapper(wrapper(func(request *http.Request) {
+				paths = append(paths, request.URL.Path)
+				metrics = append(metrics, MetricPathFromContext(request.Context()))
+			})).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		defer connection.Close()
+
+		// Send the request:
+		_, err = connection.Get().Path("/mypath").Send()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(paths).To(Equal([]string{"/token", "/mypath"}))
+		Expect(metrics).To(Equal([]string{"", "/-"}))
+	})
+})